@@ -1,125 +1,176 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/backup"
 	"github.com/asdine/storm/v3"
 	bolt "go.etcd.io/bbolt"
 )
 
 type options struct {
-	DBPath        string
-	BackupDir     string
-	RetentionDays int
+	DBPath     string
+	BackupDir  string
+	Retention  backup.RetentionPolicy
+	DryRun     bool
+	VerifyFile string
+	KeyFile    string
 }
 
+// revision is set at build time with -ldflags "-X main.revision=..." and recorded in the manifests
+var revision = "local"
+
+// lockTimeout is how long the database lock is waited for
+const lockTimeout = 5 * time.Second
+
 func main() {
 	opts := options{}
 	flag.StringVar(&opts.DBPath, "db", "for-twenty-readers.db", "Path to database file")
 	flag.StringVar(&opts.BackupDir, "backup-dir", "./backups", "Directory for backups")
-	flag.IntVar(&opts.RetentionDays, "retention", 30, "Days to keep backups")
+	flag.IntVar(&opts.Retention.Daily, "keep-daily", 7, "Number of days to keep the newest backup of")
+	flag.IntVar(&opts.Retention.Weekly, "keep-weekly", 4, "Number of weeks to keep the newest backup of")
+	flag.IntVar(&opts.Retention.Monthly, "keep-monthly", 12, "Number of months to keep the newest backup of")
+	flag.IntVar(&opts.Retention.Yearly, "keep-yearly", 3, "Number of years to keep the newest backup of")
+	flag.BoolVar(&opts.DryRun, "dry-run", false,
+		"List the backups the retention policy keeps and deletes without creating or deleting any")
+	flag.StringVar(&opts.VerifyFile, "verify", "", "Verify the backup file instead of creating one")
+	flag.StringVar(&opts.KeyFile, "key-file", "",
+		"Encrypt the backup with the key file, "+backup.PassphraseEnv+" encrypts it with a passphrase")
 	flag.Parse()
 
-	if err := runBackup(opts); err != nil {
+	secret, err := backup.NewSecret(os.Getenv(backup.PassphraseEnv), opts.KeyFile)
+	if err != nil {
+		slog.Error("invalid encryption options", "error", err)
+		os.Exit(1)
+	}
+	if err := opts.Retention.Validate(); err != nil {
+		slog.Error("invalid retention policy", "error", err)
+		os.Exit(1)
+	}
+
+	if opts.DryRun {
+		if err := runDryRun(opts, secret); err != nil {
+			slog.Error("dry run failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.VerifyFile != "" {
+		if err := runVerify(opts.VerifyFile, secret); err != nil {
+			slog.Error("verification failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := runBackup(opts, secret); err != nil {
 		slog.Error("backup failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func runBackup(opts options) error {
-	slog.Info("Starting backup", "db", opts.DBPath, "backup_dir", opts.BackupDir)
+func runBackup(opts options, secret *backup.Secret) error {
+	slog.Info("Starting backup", "db", opts.DBPath, "backup_dir", opts.BackupDir, "encrypted", secret != nil)
 
 	if _, err := os.Stat(opts.DBPath); os.IsNotExist(err) {
 		return fmt.Errorf("database file not found: %s", opts.DBPath)
 	}
 
-	if err := os.MkdirAll(opts.BackupDir, 0o750); err != nil {
-		return fmt.Errorf("failed to create backup directory: %w", err)
+	// the running application holds the lock of its database and takes the backups itself
+	db, err := storm.Open(opts.DBPath, storm.BoltOptions(0o600, &bolt.Options{Timeout: lockTimeout}))
+	if errors.Is(err, bolt.ErrTimeout) {
+		return fmt.Errorf("database %s is held by another process, a running application takes the backups "+
+			"itself with BACKUP_DIR set", opts.DBPath)
 	}
-
-	db, err := storm.Open(opts.DBPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer db.Close()
 
-	timestamp := time.Now().Format("20060102_150405")
-	backupPath := filepath.Join(opts.BackupDir, fmt.Sprintf("for-twenty-readers_%s.db", timestamp))
-
-	backupFile, err := os.Create(filepath.Clean(backupPath))
+	backupPath, err := backup.NewBackups(db.Bolt, opts.BackupDir, revision, secret, opts.Retention).CreateBackup()
 	if err != nil {
-		return fmt.Errorf("failed to create backup file: %w", err)
+		logProblems(opts.DBPath, err)
+		return fmt.Errorf("failed to create backup: %w", err)
 	}
-	defer func() { _ = backupFile.Close() }()
+	slog.Info("Backup created successfully", "path", backupPath)
+	return nil
+}
 
-	err = db.Bolt.View(func(tx *bolt.Tx) error {
-		_, errWrite := tx.WriteTo(backupFile)
-		if errWrite != nil {
-			return fmt.Errorf("failed to write backup file: %w", errWrite)
-		}
-		return nil
-	})
+// runVerify checks the backup the way cmd/restore does before restoring it
+func runVerify(path string, secret *backup.Secret) error {
+	manifest, report, err := backup.VerifyFile(path, secret)
 	if err != nil {
-		errRemove := os.Remove(backupPath)
-		if errRemove != nil {
-			return fmt.Errorf("failed to remove file path %s: %w", backupPath, errRemove)
-		}
-		return fmt.Errorf("failed to write backup: %w", err)
+		return err
 	}
-
-	stat, _ := backupFile.Stat()
-	slog.Info("Backup created successfully",
-		"path", backupPath,
-		"size_mb", stat.Size()/1024/1024,
-	)
-
-	if errClOld := cleanOldBackups(opts.BackupDir, opts.RetentionDays); errClOld != nil {
-		slog.Warn("failed to clean old backups", "error", errClOld)
+	for _, problem := range report.Problems {
+		slog.Warn("backup problem", "path", path, "problem", problem)
+	}
+	if !report.OK() {
+		return fmt.Errorf("backup %s has %d problems", path, len(report.Problems))
 	}
 
+	attrs := []any{
+		"path", path,
+		"schema_version", report.SchemaVersion,
+		"groups", report.Groups,
+		"readers", report.Readers,
+		"calendars", report.Calendars,
+	}
+	if manifest != nil {
+		attrs = append(attrs, "created_at", manifest.CreatedAt, "revision", manifest.Revision)
+	}
+	slog.Info("Backup verified", attrs...)
 	return nil
 }
 
-func cleanOldBackups(backupDir string, retentionDays int) error {
-	cutoffTime := time.Now().AddDate(0, 0, -retentionDays)
+// logProblems logs each problem of a database that failed verification
+func logProblems(path string, err error) {
+	var verification backup.VerificationError
+	if !errors.As(err, &verification) {
+		return
+	}
+	for _, problem := range verification.Problems {
+		slog.Warn("database problem", "path", path, "problem", problem)
+	}
+}
 
-	entries, err := os.ReadDir(backupDir)
+// runDryRun lists what the retention policy does with the backups. The newest backup that verifies
+// with the given passphrase or key file is the one protected.
+func runDryRun(opts options, secret *backup.Secret) error {
+	files, err := backup.ListFiles(opts.BackupDir)
 	if err != nil {
-		return fmt.Errorf("failed to read backup directory: %w", err)
+		return err
 	}
-
-	deleted := 0
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		matched, err := filepath.Match("for-twenty-readers_*.db", entry.Name())
-		if err != nil || !matched {
-			continue
-		}
-
-		info, err := entry.Info()
+	newestVerified, ok := backup.NewestVerified(files, func(path string) error {
+		_, report, err := backup.VerifyFile(path, secret)
 		if err != nil {
-			continue
+			return err
 		}
-
-		if info.ModTime().Before(cutoffTime) {
-			path := filepath.Join(backupDir, entry.Name())
-			if err := os.Remove(path); err != nil {
-				slog.Warn("failed to delete old backup", "path", path, "error", err)
-			} else {
-				deleted++
-				slog.Info("deleted old backup", "path", path)
-			}
+		if !report.OK() {
+			return fmt.Errorf("backup has %d problems", len(report.Problems))
 		}
+		return nil
+	})
+	if !ok {
+		slog.Warn("no backup passes verification", "backup_dir", opts.BackupDir)
 	}
 
-	slog.Info("cleanup completed", "deleted", deleted)
+	kept := 0
+	for _, decision := range opts.Retention.Plan(files, newestVerified) {
+		if decision.Keep {
+			kept++
+			fmt.Printf("keep    %s (%s)\n", decision.File.Path, strings.Join(decision.Reasons, ", "))
+		} else {
+			fmt.Printf("delete  %s\n", decision.File.Path)
+		}
+	}
+	fmt.Printf("\n%d backups kept, %d deleted\n", kept, len(files)-kept)
 	return nil
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/sqlite"
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+	bolt "go.etcd.io/bbolt"
+)
+
+// lockTimeout is how long the database lock is waited for
+const lockTimeout = 5 * time.Second
+
+type options struct {
+	DBPath     string
+	SQLitePath string
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.DBPath, "db", "for-twenty-readers.db", "Path to bolt database file")
+	flag.StringVar(&opts.SQLitePath, "sqlite", "for-twenty-readers.sqlite", "Path to new SQLite database file")
+	flag.Parse()
+
+	if err := runCopy(opts); err != nil {
+		slog.Error("copy failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func runCopy(opts options) error {
+	slog.Info("Starting copy", "db", opts.DBPath, "sqlite", opts.SQLitePath)
+
+	if _, err := os.Stat(opts.DBPath); os.IsNotExist(err) {
+		return fmt.Errorf("database file not found: %s", opts.DBPath)
+	}
+	// copying into a database which already has data would mix the two
+	if _, err := os.Stat(opts.SQLitePath); err == nil {
+		return fmt.Errorf("sqlite database already exists: %s", opts.SQLitePath)
+	}
+
+	// the source is kept as it is for a rollback, a copy of it is migrated and read
+	tmpDir, err := os.MkdirTemp("", "bolt2sqlite-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	sourceCopy := filepath.Join(tmpDir, "source.db")
+	if err := copySource(opts.DBPath, sourceCopy); err != nil {
+		return err
+	}
+
+	db, err := storm.Open(sourceCopy, storm.Codec(json.Codec))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	// databases the application has not opened since an update are read in the current shape
+	if _, err := adapters.Migrate(db, false); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	sqliteDB, err := sqlite.Open(opts.SQLitePath)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+
+	stats, err := adapters.CopyToRepositories(
+		context.Background(),
+		db,
+		sqlite.NewReaderGroupRepository(sqliteDB),
+		sqlite.NewPsalmReaderRepository(sqliteDB),
+		sqlite.NewCalendarOfReaderRepository(sqliteDB),
+	)
+	if err != nil {
+		_ = sqliteDB.Close()
+		if errRemove := os.Remove(opts.SQLitePath); errRemove != nil {
+			slog.Warn("failed to remove incomplete sqlite database", "path", opts.SQLitePath, "error", errRemove)
+		}
+		return fmt.Errorf("failed to copy data: %w", err)
+	}
+
+	slog.Info("Copy finished successfully",
+		"groups", stats.Groups,
+		"calendars", stats.Calendars,
+		"psalm_readers", stats.PsalmReaders,
+		"cleared_telegram_ids", stats.ClearedTelegramIDs,
+	)
+	return nil
+}
+
+// copySource writes a consistent copy of the bolt database at src to dst, opening src read-only
+func copySource(src, dst string) error {
+	source, err := bolt.Open(src, 0o600, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return fmt.Errorf("database %s is held by another process, stop the application before copying it", src)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer source.Close()
+
+	err = source.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(dst, 0o600)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy database: %w", err)
+	}
+	return nil
+}
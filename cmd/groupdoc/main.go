@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/query"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/config"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/service"
+	"github.com/gofrs/uuid/v5"
+)
+
+type options struct {
+	ExportID   string
+	ImportFile string
+	OutFile    string
+	Mode       string
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.ExportID, "export", "", "ID of the group to export")
+	flag.StringVar(&opts.ImportFile, "import", "", "Path to the group document to import")
+	flag.StringVar(&opts.OutFile, "out", "", "Path to write the exported document to, group_<id>.json by default")
+	flag.StringVar(&opts.Mode, "mode", string(command.ImportKeepIDs),
+		"What to do when the imported group exists: keep, regenerate or merge")
+	flag.Parse()
+
+	if (opts.ExportID == "") == (opts.ImportFile == "") {
+		fmt.Println("Usage: groupdoc -export <group id> [-out <file>]")
+		fmt.Println("       groupdoc -import <file> [-mode keep|regenerate|merge]")
+		fmt.Println("\nThe database is chosen by the same environment variables as the application uses.")
+		os.Exit(1)
+	}
+
+	cfg, err := config.NewConfiguration()
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
+	ctx := command.WithActor(context.Background(), command.Actor{Name: "groupdoc", Source: domain.AuditSourceSystem})
+	application := service.NewApplication(ctx, slog.Default(), *cfg)
+	defer application.Close()
+
+	if opts.ExportID != "" {
+		err = runExport(ctx, application, opts)
+	} else {
+		err = runImport(ctx, application, opts)
+	}
+	if err != nil {
+		slog.Error("groupdoc failed", "error", err)
+		application.Close()
+		os.Exit(1) //nolint:gocritic
+	}
+}
+
+func runExport(ctx context.Context, application *app.Application, opts options) error {
+	groupID, err := uuid.FromString(opts.ExportID)
+	if err != nil {
+		return fmt.Errorf("invalid group id %q: %w", opts.ExportID, err)
+	}
+
+	exported, err := application.Queries.ExportReaderGroup.Handle(ctx, query.ExportReaderGroup{ID: groupID})
+	if err != nil {
+		return err
+	}
+
+	outFile := opts.OutFile
+	if outFile == "" {
+		outFile = fmt.Sprintf("group_%s.json", groupID)
+	}
+	if err := os.WriteFile(filepath.Clean(outFile), exported.Document, 0o600); err != nil {
+		return fmt.Errorf("failed to write document: %w", err)
+	}
+
+	slog.Info("Group exported successfully", "group", exported.Name, "path", outFile)
+	return nil
+}
+
+func runImport(ctx context.Context, application *app.Application, opts options) error {
+	mode, err := command.ParseImportMode(opts.Mode)
+	if err != nil {
+		return err
+	}
+
+	document, err := os.ReadFile(filepath.Clean(opts.ImportFile))
+	if err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+
+	result, err := application.Commands.ImportReaderGroup.Handle(ctx, command.ImportReaderGroup{
+		Document: document,
+		Mode:     mode,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Group imported successfully",
+		"group_id", result.GroupID,
+		"created", result.Created,
+		"readers", result.AddedReaders,
+		"no_reading_periods", result.AddedNoReadingPeriods,
+		"calendars", result.AddedCalendars,
+		"skipped_readers", result.SkippedReaders,
+		"skipped_no_reading_periods", result.SkippedPeriods,
+		"skipped_calendars", result.SkippedCalendars,
+		"cleared_telegram_ids", result.ClearedTelegramIDs,
+	)
+	return nil
+}
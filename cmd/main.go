@@ -20,6 +20,7 @@ type options struct {
 	Conf          string `short:"f" long:"conf" env:"FM_CONF" default:"for_twenty_readers.yml" description:"config file (yml)"`
 	Dbg           bool   `long:"dbg" env:"DEBUG" description:"debug mode"`
 	TelegramToken string `long:"telegram-token" env:"TELEGRAM_BOT_TOKEN" description:"Telegram bot token"`
+	MigrateDryRun bool   `long:"migrate-dry-run" description:"report pending database migrations and exit"`
 }
 
 var revision = "local"
@@ -40,13 +41,25 @@ func main() {
 		slog.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
+	cfg.System.Revision = revision
+
+	if opts.MigrateDryRun {
+		reportMigrations(*cfg)
+		return
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	app := service.NewApplication(ctx, logger)
+	app := service.NewApplication(ctx, logger, *cfg)
 	defer app.Close()
 
+	go service.PurgeTrashPeriodically(ctx, app.Commands.PurgeTrash, cfg.Trash.PurgeInterval)
+	if cfg.Backup.Dir != "" {
+		slog.Info("Backups scheduled", "dir", cfg.Backup.Dir, "interval", cfg.Backup.Interval)
+		go service.BackupPeriodically(ctx, app.Commands.CreateBackup, cfg.Backup.Interval)
+	}
+
 	if opts.TelegramToken != "" {
 
 		bot, err := telegram.NewBot(
@@ -88,6 +101,21 @@ func main() {
 	srv.Run(ctx, opts.Port)
 }
 
+func reportMigrations(cfg config.Config) {
+	results, err := service.MigrateDryRun(cfg)
+	if err != nil {
+		slog.Error("failed to check migrations", "error", err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		slog.Info("database schema is up to date")
+		return
+	}
+	for _, result := range results {
+		slog.Info("migration would be applied", "version", result.Version, "name", result.Name, "changed", result.Changed)
+	}
+}
+
 func setupLog(dbg bool) {
 	logLevel := slog.LevelInfo
 	if dbg {
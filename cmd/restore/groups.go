@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/config"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/service"
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+	"github.com/gofrs/uuid/v5"
+	bolt "go.etcd.io/bbolt"
+)
+
+// lockTimeout is how long the database lock is waited for
+const lockTimeout = 5 * time.Second
+
+// openBackupDB opens the database extracted from the backup. It is a temporary copy,
+// so it is migrated to the schema the repositories read.
+func openBackupDB(path string) (*storm.DB, error) {
+	db, err := storm.Open(path, storm.Codec(json.Codec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup database: %w", err)
+	}
+	if _, err := adapters.Migrate(db, false); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate backup database: %w", err)
+	}
+	return db, nil
+}
+
+func listGroups(extracted string) error {
+	ctx := context.Background()
+	db, err := openBackupDB(extracted)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	repo := adapters.NewReaderGroupRepository(db)
+
+	groups, err := repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list groups of backup: %w", err)
+	}
+
+	fmt.Println("\nGroups in the backup:")
+	for _, group := range groups {
+		calendars, err := repo.CountCalendars(ctx, group.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count calendars of group %s: %w", group.ID, err)
+		}
+		fmt.Printf("  %s  %s (%d readers, %d calendars)\n", group.ID, group.Name, len(group.Readers), calendars)
+	}
+	if len(groups) == 0 {
+		fmt.Println("  No groups found")
+	}
+	return nil
+}
+
+// restoreGroups restores the chosen groups of the backup into the database of the application configuration,
+// or of -db when it is given, and leaves the other groups as they are. The changes are shown first.
+func restoreGroups(opts options, extracted string) error {
+	mode, err := command.ParseRestoreGroupMode(opts.GroupMode)
+	if err != nil {
+		return err
+	}
+	groupIDs, err := parseGroupIDs(opts.Groups)
+	if err != nil {
+		return err
+	}
+
+	groups, err := backupGroups(extracted, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfiguration()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if opts.dbChanged {
+		cfg.Storage.Backend, cfg.Storage.BoltPath = config.StorageBolt, opts.DBPath
+	}
+	if cfg.Storage.Backend == config.StorageBolt {
+		if err := checkNotHeld(cfg.Storage.BoltPath); err != nil {
+			return err
+		}
+	}
+
+	ctx := command.WithActor(context.Background(), command.Actor{Name: "restore", Source: domain.AuditSourceSystem})
+	application := service.NewApplication(ctx, slog.Default(), *cfg)
+	defer application.Close()
+	handler := application.Commands.RestoreGroupFromBackup
+
+	for _, group := range groups {
+		result, err := handler.Handle(ctx, command.RestoreGroupFromBackup{Group: group, Mode: mode, DryRun: true})
+		if err != nil {
+			return fmt.Errorf("failed to check group %s: %w", group.ID, err)
+		}
+		printGroupDiff(group, mode, result)
+	}
+	if opts.Diff {
+		return nil
+	}
+
+	if !opts.Force {
+		fmt.Printf("\n⚠️  WARNING: This will %s %d groups in the current database!\n", mode, len(groups))
+		if !confirm() {
+			fmt.Println("Restore canceled")
+			return nil
+		}
+	}
+
+	for _, group := range groups {
+		result, err := handler.Handle(ctx, command.RestoreGroupFromBackup{Group: group, Mode: mode})
+		if err != nil {
+			return fmt.Errorf("failed to restore group %s: %w", group.ID, err)
+		}
+		slog.Info("Group restored",
+			"group", group.Name,
+			"group_id", result.GroupID,
+			"mode", mode,
+			"created", result.Created,
+			"changes", len(result.Changes),
+		)
+	}
+
+	fmt.Println("\n✅ Groups restored successfully!")
+	if mode == command.RestoreOverwrite {
+		fmt.Println("The overwritten groups are in the trash")
+	}
+	return nil
+}
+
+// checkNotHeld fails when another process, the running application, holds the lock of the bolt database,
+// the application would wait for it forever
+func checkNotHeld(path string) error {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return fmt.Errorf("database %s is held by another process, stop the application before restoring groups", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	return db.Close()
+}
+
+func parseGroupIDs(value string) ([]uuid.UUID, error) {
+	var groupIDs []uuid.UUID
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		groupID, err := uuid.FromString(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group id %q: %w", field, err)
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+	if len(groupIDs) == 0 {
+		return nil, errors.New("no groups to restore, give their IDs with -groups, -list-groups lists them")
+	}
+	return groupIDs, nil
+}
+
+// backupGroups reads the groups with their calendars from the extracted backup
+func backupGroups(extracted string, groupIDs []uuid.UUID) ([]*domain.ReaderGroup, error) {
+	db, err := openBackupDB(extracted)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	repo := adapters.NewReaderGroupRepository(db)
+
+	groups := make([]*domain.ReaderGroup, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		group, err := repo.GetByIDWithCalendars(context.Background(), groupID)
+		var notFound domain.ReaderGroupNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("group %s is not in the backup", groupID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read group %s from backup: %w", groupID, err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func printGroupDiff(
+	group *domain.ReaderGroup,
+	mode command.RestoreGroupMode,
+	result *command.RestoreGroupFromBackupResult,
+) {
+	fmt.Printf("\nGroup %s (%s):\n", group.Name, group.ID)
+	switch {
+	case mode == command.RestoreAsCopy:
+		fmt.Printf("  restored as a copy under a new ID with %d readers and %d calendars\n",
+			len(group.Readers), len(group.Calendars))
+		if result.ClearedTelegramIDs > 0 {
+			fmt.Printf("  %d readers of the copy lose the Telegram account that reads in another group\n",
+				result.ClearedTelegramIDs)
+		}
+		return
+	case result.Created:
+		fmt.Println("  not in the current database, it is created")
+	case len(result.Changes) == 0:
+		fmt.Println("  same as in the current database, nothing to restore")
+		return
+	}
+	for _, change := range result.Changes {
+		fmt.Printf("  %s: %q → %q\n", change.Field, change.Before, change.After)
+	}
+}
@@ -9,24 +9,46 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/backup"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
 )
 
 type options struct {
 	BackupFile string
 	DBPath     string
 	Force      bool
+	KeyFile    string
+	ListGroups bool
+	Groups     string
+	GroupMode  string
+	Diff       bool
+	// dbChanged tells that -db was given, a group restore goes to the database of the configuration otherwise
+	dbChanged bool
 }
 
 func main() {
 	opts := options{}
 	flag.StringVar(&opts.BackupFile, "backup", "", "Path to backup file (required)")
 	flag.StringVar(&opts.DBPath, "db", "for-twenty-readers.db", "Path to database file")
-	flag.BoolVar(&opts.Force, "force", false, "Skip confirmation prompt")
+	flag.BoolVar(&opts.Force, "force", false, "Skip confirmation prompt and restore a backup that fails verification")
+	flag.StringVar(&opts.KeyFile, "key-file", "",
+		"Key file of an encrypted backup, "+backup.PassphraseEnv+" holds the passphrase of one")
+	flag.BoolVar(&opts.ListGroups, "list-groups", false, "List the groups in the backup")
+	flag.StringVar(&opts.Groups, "groups", "",
+		"Comma separated IDs of the groups to restore into the current database instead of replacing it")
+	flag.StringVar(&opts.GroupMode, "group-mode", string(command.RestoreOverwrite),
+		"How to restore the groups: overwrite the stored ones or copy them under new IDs")
+	flag.BoolVar(&opts.Diff, "diff", false, "Show what restoring the groups would change without restoring them")
 	flag.Parse()
+	flag.Visit(func(f *flag.Flag) { opts.dbChanged = opts.dbChanged || f.Name == "db" })
 
 	if opts.BackupFile == "" {
 		listBackups()
-		fmt.Println("\nUsage: restore -backup <file> [-db <path>] [-force]")
+		fmt.Println("\nUsage: restore -backup <file> [-db <path>] [-key-file <path>] [-force]")
+		fmt.Println("       restore -backup <file> -list-groups")
+		fmt.Println("       restore -backup <file> -groups <id,...> [-group-mode overwrite|copy] [-diff] [-force]")
+		fmt.Println("\nStop the application first, restore needs its database file to itself.")
 		os.Exit(1)
 	}
 
@@ -43,17 +65,42 @@ func runRestore(opts options) error {
 		return fmt.Errorf("backup file not found: %s", opts.BackupFile)
 	}
 
+	secret, err := backup.NewSecret(os.Getenv(backup.PassphraseEnv), opts.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	// archives are unpacked first, the database that is put in place is the one verified
+	tmpDir, err := os.MkdirTemp("", "restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	extracted := filepath.Join(tmpDir, "backup.db")
+	manifest, err := backup.Extract(opts.BackupFile, extracted, secret)
+	if err != nil {
+		return fmt.Errorf("failed to extract backup: %w", err)
+	}
+
+	if err := verifyBackup(extracted, manifest); err != nil {
+		if !opts.Force {
+			return fmt.Errorf("%w; use -force to restore it anyway", err)
+		}
+		slog.Warn("restoring a backup that failed verification", "error", err)
+	}
+
+	switch {
+	case opts.ListGroups:
+		return listGroups(extracted)
+	case opts.Groups != "" || opts.Diff:
+		return restoreGroups(opts, extracted)
+	}
+
 	if !opts.Force {
 		fmt.Printf("\n⚠️  WARNING: This will replace the current database!\n")
 		fmt.Printf("Backup file: %s\n", opts.BackupFile)
 		fmt.Printf("Target DB:   %s\n\n", opts.DBPath)
-		fmt.Print("Continue? (yes/no): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		answer, _ := reader.ReadString('\n')
-		answer = strings.TrimSpace(strings.ToLower(answer))
-
-		if answer != "yes" && answer != "y" {
+		if !confirm() {
 			fmt.Println("Restore canceled")
 			return nil
 		}
@@ -69,7 +116,7 @@ func runRestore(opts options) error {
 		slog.Info("created safety backup", "path", safetyBackup)
 	}
 
-	if err := copyFile(opts.BackupFile, opts.DBPath); err != nil {
+	if err := copyFile(extracted, opts.DBPath); err != nil {
 		return fmt.Errorf("failed to restore database: %w", err)
 	}
 
@@ -85,6 +132,41 @@ func runRestore(opts options) error {
 	return nil
 }
 
+// verifyBackup refuses files that are not readable databases or hold records the application can not read
+func verifyBackup(path string, manifest *backup.Manifest) error {
+	report, err := backup.VerifyExtracted(path, manifest)
+	if err != nil {
+		return fmt.Errorf("backup is not a readable database: %w", err)
+	}
+	for _, problem := range report.Problems {
+		slog.Warn("backup problem", "problem", problem)
+	}
+	if !report.OK() {
+		return fmt.Errorf("backup has %d problems", len(report.Problems))
+	}
+
+	attrs := []any{
+		"schema_version", report.SchemaVersion,
+		"groups", report.Groups,
+		"readers", report.Readers,
+		"calendars", report.Calendars,
+	}
+	if manifest != nil {
+		attrs = append(attrs, "created_at", manifest.CreatedAt, "revision", manifest.Revision)
+	}
+	slog.Info("Backup verified", attrs...)
+	return nil
+}
+
+func confirm() bool {
+	fmt.Print("Continue? (yes/no): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "yes" || answer == "y"
+}
+
 func copyFile(src, dst string) error {
 	cleanSrc := filepath.Clean(src)
 	cleanDst := filepath.Clean(dst)
@@ -125,8 +207,7 @@ func listBackups() {
 				continue
 			}
 
-			matched, _ := filepath.Match("for-twenty-readers_*.db", entry.Name())
-			if matched {
+			if backup.IsBackupFile(entry.Name()) {
 				info, _ := entry.Info()
 				path := filepath.Join(dir, entry.Name())
 				fmt.Printf("  %s (%d MB, modified: %s)\n",
@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/gofrs/uuid/v5"
+)
+
+type AuditChangeDB struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// AuditEntryDB is kept in its own bucket, apart from the groups it describes, so that it outlives them
+type AuditEntryDB struct {
+	ID        string          `storm:"id" json:"id"`
+	GroupID   string          `storm:"index" json:"group_id"`
+	Actor     string          `json:"actor"`
+	Source    string          `json:"source"`
+	Action    string          `json:"action"`
+	Changes   []AuditChangeDB `json:"changes"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditLogRepository struct {
+	db *storm.DB
+}
+
+func NewAuditLogRepository(db *storm.DB) *AuditLogRepository {
+	if db == nil {
+		slog.Error("missing db in NewAuditLogRepository")
+		os.Exit(1)
+	}
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	dbEntry := marshalAuditEntry(entry)
+	if err := r.db.Save(&dbEntry); err != nil {
+		return fmt.Errorf("error saving audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var dbEntries []AuditEntryDB
+	var err error
+	if filter.GroupID.IsNil() {
+		err = r.db.All(&dbEntries)
+	} else {
+		err = r.db.Find("GroupID", filter.GroupID.String(), &dbEntries)
+	}
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+
+	entries := make([]domain.AuditEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entries[i] = unmarshalAuditEntry(dbEntry)
+	}
+	return filter.Apply(entries), nil
+}
+
+func marshalAuditEntry(entry *domain.AuditEntry) AuditEntryDB {
+	changes := make([]AuditChangeDB, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = AuditChangeDB{Field: change.Field, Before: change.Before, After: change.After}
+	}
+	return AuditEntryDB{
+		ID:        entry.ID.String(),
+		GroupID:   entry.GroupID.String(),
+		Actor:     entry.Actor,
+		Source:    string(entry.Source),
+		Action:    string(entry.Action),
+		Changes:   changes,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func unmarshalAuditEntry(dbEntry AuditEntryDB) domain.AuditEntry {
+	changes := make([]domain.AuditChange, len(dbEntry.Changes))
+	for i, change := range dbEntry.Changes {
+		changes[i] = domain.AuditChange{Field: change.Field, Before: change.Before, After: change.After}
+	}
+	return domain.AuditEntry{
+		ID:        uuid.FromStringOrNil(dbEntry.ID),
+		GroupID:   uuid.FromStringOrNil(dbEntry.GroupID),
+		Actor:     dbEntry.Actor,
+		Source:    domain.AuditSource(dbEntry.Source),
+		Action:    domain.AuditAction(dbEntry.Action),
+		Changes:   changes,
+		CreatedAt: dbEntry.CreatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+func TestAuditLogRepository_Contract(t *testing.T) {
+	repotest.AuditLogRepository(t, func(t *testing.T) domain.RepositoryAuditLog {
+		return NewAuditLogRepository(openTestDB(t))
+	})
+}
@@ -0,0 +1,443 @@
+// Package backup writes and reads the backup archives of the bolt database: a gzipped tar of a manifest
+// and a snapshot of the database, optionally encrypted with a passphrase or a key file.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	ManifestFormat  = "for-twenty-readers/backup"
+	ManifestVersion = 1
+
+	// FilePrefix starts the names of the backups, the files made before the archives are plain databases
+	FilePrefix   = "for-twenty-readers_"
+	DatabaseExt  = ".db"
+	ArchiveExt   = ".tar.gz"
+	EncryptedExt = ".tar.gz.enc"
+
+	// fileTimeLayout is the local time of the backup in its file name, to the millisecond so that
+	// a scheduled backup and one taken by hand in the same second do not overwrite each other
+	fileTimeLayout = "20060102_150405.000"
+	// secondFileTimeLayout is the time in the names of the backups made before the milliseconds were kept
+	secondFileTimeLayout = "20060102_150405"
+
+	manifestName = "manifest.json"
+	databaseName = "for-twenty-readers.db"
+	// maxManifestSize keeps a broken archive from being read into memory as a manifest
+	maxManifestSize = 1 << 20
+)
+
+// Manifest describes the database in the archive
+type Manifest struct {
+	Format        string    `json:"format"`
+	Version       int       `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Revision      string    `json:"revision"`
+	SchemaVersion int       `json:"schema_version"`
+	Size          int64     `json:"size"`
+	SHA256        string    `json:"sha256"`
+	Groups        int       `json:"groups"`
+	Readers       int       `json:"readers"`
+	Calendars     int       `json:"calendars"`
+}
+
+// Kind is what a backup file holds
+type Kind int
+
+const (
+	KindDatabase Kind = iota
+	KindArchive
+	KindEncryptedArchive
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindArchive:
+		return "archive"
+	case KindEncryptedArchive:
+		return "encrypted archive"
+	default:
+		return "database"
+	}
+}
+
+// VerificationError lists the problems that keep a database from being backed up or restored
+type VerificationError struct {
+	Problems []string
+}
+
+func (e VerificationError) Error() string {
+	return fmt.Sprintf("database has %d problems: %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// IsBackupFile tells whether the file name is one of a backup, an archive or a plain database
+func IsBackupFile(name string) bool {
+	if !strings.HasPrefix(name, FilePrefix) {
+		return false
+	}
+	return strings.HasSuffix(name, DatabaseExt) || strings.HasSuffix(name, ArchiveExt) ||
+		strings.HasSuffix(name, EncryptedExt)
+}
+
+// Detect tells the kind of the backup file by its first bytes
+func Detect(path string) (Kind, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, fmt.Errorf("error opening backup: %w", err)
+	}
+	defer file.Close()
+
+	start := make([]byte, len(encryptedMagic))
+	n, err := io.ReadFull(file, start)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("error reading backup: %w", err)
+	}
+	start = start[:n]
+	switch {
+	case bytes.Equal(start, []byte(encryptedMagic)):
+		return KindEncryptedArchive, nil
+	case bytes.HasPrefix(start, []byte{0x1f, 0x8b}):
+		return KindArchive, nil
+	default:
+		return KindDatabase, nil
+	}
+}
+
+// Create writes a snapshot of the database into an archive in dir and returns its path.
+// The snapshot is verified first, a database with problems is not archived.
+// The archive is encrypted when the secret is not nil.
+func Create(db *bolt.DB, dir, revision string, secret *Secret) (string, *Manifest, error) {
+	snapshot, err := writeSnapshot(db, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(snapshot)
+
+	manifest, err := NewManifest(snapshot, revision)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path := filepath.Join(dir, FileName(manifest, secret))
+	if err := writeArchiveFile(path, snapshot, manifest, secret); err != nil {
+		return "", nil, err
+	}
+	return path, manifest, nil
+}
+
+// FileName names the archive of the manifest after the local time it was created at
+func FileName(manifest *Manifest, secret *Secret) string {
+	ext := ArchiveExt
+	if secret != nil {
+		ext = EncryptedExt
+	}
+	return FilePrefix + manifest.CreatedAt.Local().Format(fileTimeLayout) + ext
+}
+
+// writeSnapshot copies the database in a read transaction into a file in dir and returns its path,
+// so the snapshot is consistent while the database is in use
+func writeSnapshot(db *bolt.DB, dir string) (string, error) {
+	snapshot, err := os.CreateTemp(dir, ".snapshot-*.db")
+	if err != nil {
+		return "", fmt.Errorf("error creating snapshot file: %w", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		_, errWrite := tx.WriteTo(snapshot)
+		return errWrite
+	})
+	if errClose := snapshot.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(snapshot.Name())
+		return "", fmt.Errorf("error writing snapshot: %w", err)
+	}
+	return snapshot.Name(), nil
+}
+
+// NewManifest verifies the database and describes it
+func NewManifest(dbPath, revision string) (*Manifest, error) {
+	report, err := adapters.VerifyDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if !report.OK() {
+		return nil, VerificationError{Problems: report.Problems}
+	}
+
+	file, err := os.Open(filepath.Clean(dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	defer file.Close()
+	digest := sha256.New()
+	size, err := io.Copy(digest, file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading database: %w", err)
+	}
+
+	return &Manifest{
+		Format:        ManifestFormat,
+		Version:       ManifestVersion,
+		CreatedAt:     time.Now().UTC(),
+		Revision:      revision,
+		SchemaVersion: report.SchemaVersion,
+		Size:          size,
+		SHA256:        hex.EncodeToString(digest.Sum(nil)),
+		Groups:        report.Groups,
+		Readers:       report.Readers,
+		Calendars:     report.Calendars,
+	}, nil
+}
+
+// writeArchiveFile writes the archive next to its path and renames it into place,
+// so a failed backup never leaves a file that looks like a complete one
+func writeArchiveFile(path, dbPath string, manifest *Manifest, secret *Secret) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("error creating archive file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	err = WriteArchive(file, dbPath, manifest, secret)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return fmt.Errorf("error writing archive: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error renaming archive: %w", err)
+	}
+	return nil
+}
+
+// WriteArchive writes the manifest and the database the manifest describes to w
+func WriteArchive(w io.Writer, dbPath string, manifest *Manifest, secret *Secret) error {
+	out := w
+	var encrypted *encryptWriter
+	if secret != nil {
+		var err error
+		if encrypted, err = newEncryptWriter(w, secret); err != nil {
+			return err
+		}
+		out = encrypted
+	}
+
+	compressed := gzip.NewWriter(out)
+	archive := tar.NewWriter(compressed)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+	if err := writeEntry(archive, manifestName, int64(len(manifestData)), manifest.CreatedAt,
+		bytes.NewReader(manifestData)); err != nil {
+		return err
+	}
+
+	db, err := os.Open(filepath.Clean(dbPath))
+	if err != nil {
+		return fmt.Errorf("error opening database: %w", err)
+	}
+	defer db.Close()
+	if err := writeEntry(archive, databaseName, manifest.Size, manifest.CreatedAt, db); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("error closing archive: %w", err)
+	}
+	if err := compressed.Close(); err != nil {
+		return fmt.Errorf("error compressing archive: %w", err)
+	}
+	if encrypted != nil {
+		return encrypted.Close()
+	}
+	return nil
+}
+
+func writeEntry(archive *tar.Writer, name string, size int64, modTime time.Time, data io.Reader) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	if _, err := io.CopyN(archive, data, size); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// Extract writes the database of the backup to dst. A plain database is copied as it is and has no manifest,
+// the database of an archive is checked against the size and the SHA-256 of its manifest.
+func Extract(path, dst string, secret *Secret) (*Manifest, error) {
+	kind, err := Detect(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening backup: %w", err)
+	}
+	defer file.Close()
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error creating database file: %w", err)
+	}
+	defer out.Close()
+
+	if kind == KindDatabase {
+		if _, err := io.Copy(out, file); err != nil {
+			return nil, fmt.Errorf("error copying database: %w", err)
+		}
+		return nil, closeDatabase(out)
+	}
+
+	in := io.Reader(file)
+	if kind == KindEncryptedArchive {
+		if in, err = newDecryptReader(file, secret); err != nil {
+			return nil, err
+		}
+	}
+	manifest, err := extractArchive(in, out)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, closeDatabase(out)
+}
+
+func closeDatabase(out *os.File) error {
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error writing database: %w", err)
+	}
+	return nil
+}
+
+func extractArchive(in io.Reader, out io.Writer) (*Manifest, error) {
+	compressed, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	archive := tar.NewReader(compressed)
+
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := archive.Next()
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	if header.Name != databaseName {
+		return nil, fmt.Errorf("unexpected archive entry %s instead of the database", header.Name)
+	}
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, digest), archive)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting database: %w", err)
+	}
+	if err := checkDatabase(manifest, size, digest); err != nil {
+		return nil, err
+	}
+	// reading to the end checks the gzip checksum and, of an encrypted archive, the last chunk
+	if _, err := io.Copy(io.Discard, compressed); err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	return manifest, nil
+}
+
+func readManifest(archive *tar.Reader) (*Manifest, error) {
+	header, err := archive.Next()
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	if header.Name != manifestName {
+		return nil, fmt.Errorf("archive starts with %s instead of the manifest", header.Name)
+	}
+	if header.Size > maxManifestSize {
+		return nil, fmt.Errorf("manifest of %d bytes is too large", header.Size)
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	if manifest.Format != ManifestFormat {
+		return nil, fmt.Errorf("not a backup manifest: format %q", manifest.Format)
+	}
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("backup version %d is newer than the supported %d", manifest.Version, ManifestVersion)
+	}
+	return &manifest, nil
+}
+
+func checkDatabase(manifest *Manifest, size int64, digest hash.Hash) error {
+	if size != manifest.Size {
+		return fmt.Errorf("database is %d bytes, the manifest says %d", size, manifest.Size)
+	}
+	if sum := hex.EncodeToString(digest.Sum(nil)); sum != manifest.SHA256 {
+		return fmt.Errorf("database SHA-256 %s does not match the manifest %s", sum, manifest.SHA256)
+	}
+	return nil
+}
+
+// Verify extracts the database of the backup to dst and verifies it with VerifyExtracted.
+// The extracted database is left at dst for the caller.
+func Verify(path, dst string, secret *Secret) (*Manifest, *adapters.VerifyReport, error) {
+	manifest, err := Extract(path, dst, secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err := VerifyExtracted(dst, manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, report, nil
+}
+
+// VerifyFile verifies the backup with Verify in a temporary directory it removes afterwards
+func VerifyFile(path string, secret *Secret) (*Manifest, *adapters.VerifyReport, error) {
+	tmpDir, err := os.MkdirTemp("", "verify-backup-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	return Verify(path, filepath.Join(tmpDir, databaseName), secret)
+}
+
+// VerifyExtracted runs the checks of adapters.VerifyDatabase on an extracted database,
+// the counts of an archive must match its manifest. The manifest of a plain database is nil.
+func VerifyExtracted(dbPath string, manifest *Manifest) (*adapters.VerifyReport, error) {
+	report, err := adapters.VerifyDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil && (manifest.Groups != report.Groups || manifest.Readers != report.Readers ||
+		manifest.Calendars != report.Calendars) {
+		report.Problems = append(report.Problems, fmt.Sprintf(
+			"manifest counts %d groups, %d readers and %d calendars, the database holds %d, %d and %d",
+			manifest.Groups, manifest.Readers, manifest.Calendars, report.Groups, report.Readers, report.Calendars))
+	}
+	return report, nil
+}
@@ -0,0 +1,197 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTestDB opens a migrated database with a group of one reader and one calendar
+func openTestDB(t *testing.T) *storm.DB {
+	t.Helper()
+	db, err := storm.Open(filepath.Join(t.TempDir(), "test.db"), storm.Codec(json.Codec))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = adapters.Migrate(db, false)
+	require.NoError(t, err)
+
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	reader, err := domain.NewPsalmReader("Иван", 1001, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(reader))
+	require.NoError(t, group.AddCalendar(*domain.NewCalendarOfReader(2026, domain.CalendarYearCivil, 1,
+		domain.CalendarMap{1: {1: {1}}})))
+	require.NoError(t, adapters.NewReaderGroupRepository(db).Create(context.Background(), group))
+	return db
+}
+
+func newTestKeyFile(t *testing.T) *Secret {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789abcdef0123456789abcdef"), 0o600))
+	secret, err := ReadKeyFile(path)
+	require.NoError(t, err)
+	return secret
+}
+
+func TestCreateAndVerify(t *testing.T) {
+	passphrase, err := NewPassphrase("correct horse battery staple")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		secret   *Secret
+		wantKind Kind
+		wantExt  string
+	}{
+		{name: "plain", wantKind: KindArchive, wantExt: ArchiveExt},
+		{name: "passphrase", secret: passphrase, wantKind: KindEncryptedArchive, wantExt: EncryptedExt},
+		{name: "key file", secret: newTestKeyFile(t), wantKind: KindEncryptedArchive, wantExt: EncryptedExt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path, manifest, err := Create(openTestDB(t).Bolt, dir, "abc123", tt.secret)
+			require.NoError(t, err)
+			assert.True(t, IsBackupFile(filepath.Base(path)), path)
+			assert.True(t, strings.HasSuffix(path, tt.wantExt), path)
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1, "the snapshot and the temporary archive are removed")
+
+			kind, err := Detect(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKind, kind)
+
+			assert.Equal(t, "abc123", manifest.Revision)
+			assert.Equal(t, 1, manifest.Groups)
+			assert.Equal(t, 1, manifest.Readers)
+			assert.Equal(t, 1, manifest.Calendars)
+			assert.Len(t, manifest.SHA256, 64)
+
+			extracted, report, err := Verify(path, filepath.Join(t.TempDir(), "restored.db"), tt.secret)
+			require.NoError(t, err)
+			assert.True(t, report.OK(), report.Problems)
+			assert.Equal(t, manifest.SHA256, extracted.SHA256)
+			assert.Equal(t, manifest.SchemaVersion, report.SchemaVersion)
+		})
+	}
+}
+
+func TestExtract_Database(t *testing.T) {
+	db := openTestDB(t)
+	path := filepath.Join(t.TempDir(), FilePrefix+"20260101_000000"+DatabaseExt)
+	require.NoError(t, db.Bolt.View(func(tx *bolt.Tx) error { return tx.CopyFile(path, 0o600) }))
+
+	kind, err := Detect(path)
+	require.NoError(t, err)
+	assert.Equal(t, KindDatabase, kind)
+
+	manifest, report, err := Verify(path, filepath.Join(t.TempDir(), "restored.db"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, manifest, "a plain database has no manifest")
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, 1, report.Groups)
+}
+
+func TestExtract_Errors(t *testing.T) {
+	passphrase, err := NewPassphrase("secret")
+	require.NoError(t, err)
+	otherPassphrase, err := NewPassphrase("other")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		secret      *Secret
+		readSecret  *Secret
+		change      func(t *testing.T, path string)
+		errIs       error
+		errContains string
+	}{
+		{name: "no secret", secret: passphrase, errIs: ErrSecretRequired},
+		{name: "wrong passphrase", secret: passphrase, readSecret: otherPassphrase, errIs: ErrWrongSecret},
+		{
+			name: "key file instead of passphrase", secret: passphrase, readSecret: newTestKeyFile(t),
+			errContains: "encrypted with a passphrase",
+		},
+		{
+			name: "truncated encrypted archive", secret: passphrase, readSecret: passphrase,
+			change: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				require.NoError(t, os.Truncate(path, info.Size()-1))
+			},
+			errIs: ErrWrongSecret,
+		},
+		{
+			name: "changed encrypted archive", secret: passphrase, readSecret: passphrase,
+			change: func(t *testing.T, path string) { flipByte(t, path, headerSize+10) },
+			errIs:  ErrWrongSecret,
+		},
+		{
+			name: "changed archive",
+			change: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				flipByte(t, path, int(info.Size()/2))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _, err := Create(openTestDB(t).Bolt, t.TempDir(), "abc123", tt.secret)
+			require.NoError(t, err)
+			if tt.change != nil {
+				tt.change(t, path)
+			}
+
+			_, err = Extract(path, filepath.Join(t.TempDir(), "restored.db"), tt.readSecret)
+			require.Error(t, err)
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+			}
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func flipByte(t *testing.T, path string, offset int) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[offset] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestIsBackupFile(t *testing.T) {
+	assert.True(t, IsBackupFile("for-twenty-readers_20260101_000000.db"))
+	assert.True(t, IsBackupFile("for-twenty-readers_20260101_000000.tar.gz"))
+	assert.True(t, IsBackupFile("for-twenty-readers_20260101_000000.tar.gz.enc"))
+	assert.False(t, IsBackupFile(".archive-123"))
+	assert.False(t, IsBackupFile("for-twenty-readers.db"))
+}
+
+func TestFileName_SameSecond(t *testing.T) {
+	createdAt := time.Date(2026, time.January, 1, 2, 0, 0, 0, time.Local)
+	first := FileName(&Manifest{CreatedAt: createdAt}, nil)
+	second := FileName(&Manifest{CreatedAt: createdAt.Add(time.Millisecond)}, nil)
+	assert.NotEqual(t, first, second)
+	assert.True(t, IsBackupFile(second))
+
+	parsed, ok := fileTime(second)
+	require.True(t, ok)
+	assert.True(t, createdAt.Add(time.Millisecond).Equal(parsed))
+}
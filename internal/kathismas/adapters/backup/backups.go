@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backups takes the backups of an open database. The application uses it for the scheduled backups and
+// the snapshots it streams, so the backups do not need the database file the application holds.
+type Backups struct {
+	db       *bolt.DB
+	dir      string
+	revision string
+	secret   *Secret
+	policy   RetentionPolicy
+}
+
+// NewBackups keeps the backups in dir by the retention policy and encrypts them when the secret is not nil.
+// The revision is recorded in the manifests.
+func NewBackups(db *bolt.DB, dir, revision string, secret *Secret, policy RetentionPolicy) *Backups {
+	if db == nil {
+		panic("nil db")
+	}
+	return &Backups{db: db, dir: dir, revision: revision, secret: secret, policy: policy}
+}
+
+// CreateBackup writes a backup into the backup directory, reads it back to verify it and prunes
+// the directory by the retention policy. A backup that fails verification is removed.
+func (b *Backups) CreateBackup() (string, error) {
+	if b.dir == "" {
+		return "", errors.New("no backup directory")
+	}
+	if err := os.MkdirAll(b.dir, 0o750); err != nil {
+		return "", fmt.Errorf("error creating backup directory: %w", err)
+	}
+
+	path, manifest, err := Create(b.db, b.dir, b.revision, b.secret)
+	if err != nil {
+		return "", err
+	}
+	// a backup that can not be read back is worse than none, it hides the failure
+	if err := verifyBackup(path, b.secret); err != nil {
+		if errRemove := os.Remove(path); errRemove != nil {
+			slog.Warn("failed to remove unverified backup", "path", path, "error", errRemove)
+		}
+		return "", err
+	}
+	slog.Info("backup created",
+		"path", path,
+		"size", manifest.Size,
+		"sha256", manifest.SHA256,
+		"groups", manifest.Groups,
+		"readers", manifest.Readers,
+		"calendars", manifest.Calendars,
+	)
+
+	if err := Prune(b.dir, b.policy, path); err != nil {
+		slog.Warn("failed to prune old backups", "error", err)
+	}
+	return path, nil
+}
+
+// OpenSnapshot writes an archive of a snapshot of the database into a temporary file and opens it
+// for reading, closing it removes the file. The name is the one the archive gets in the backup directory.
+func (b *Backups) OpenSnapshot() (string, io.ReadCloser, error) {
+	tmpDir, err := os.MkdirTemp("", "backup-snapshot-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	name, archive, err := b.writeSnapshotArchive(tmpDir)
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return "", nil, err
+	}
+	return name, &tempArchive{File: archive, dir: tmpDir}, nil
+}
+
+func (b *Backups) writeSnapshotArchive(dir string) (string, *os.File, error) {
+	snapshot, err := writeSnapshot(b.db, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(snapshot)
+
+	manifest, err := NewManifest(snapshot, b.revision)
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := FileName(manifest, b.secret)
+	archive, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating archive file: %w", err)
+	}
+	if err := WriteArchive(archive, snapshot, manifest, b.secret); err != nil {
+		_ = archive.Close()
+		return "", nil, fmt.Errorf("error writing archive: %w", err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		_ = archive.Close()
+		return "", nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	return name, archive, nil
+}
+
+// tempArchive removes its temporary directory when it is closed
+type tempArchive struct {
+	*os.File
+	dir string
+}
+
+func (a *tempArchive) Close() error {
+	err := a.File.Close()
+	if errRemove := os.RemoveAll(a.dir); err == nil {
+		err = errRemove
+	}
+	if err != nil {
+		return fmt.Errorf("error removing snapshot archive: %w", err)
+	}
+	return nil
+}
+
+// verifyBackup reads the backup back the way restore does, a backup with problems fails with VerificationError
+func verifyBackup(path string, secret *Secret) error {
+	_, report, err := VerifyFile(path, secret)
+	if err != nil {
+		return fmt.Errorf("error verifying backup %s: %w", path, err)
+	}
+	if !report.OK() {
+		return fmt.Errorf("backup %s does not verify: %w", path, VerificationError{Problems: report.Problems})
+	}
+	return nil
+}
+
+// Prune deletes the backups in dir the retention policy does not keep, newestVerified is never deleted
+func Prune(dir string, policy RetentionPolicy, newestVerified string) error {
+	files, err := ListFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	kept, deleted := 0, 0
+	for _, decision := range policy.Plan(files, newestVerified) {
+		if decision.Keep {
+			kept++
+			continue
+		}
+		path := decision.File.Path
+		if err := os.Remove(path); err != nil {
+			slog.Warn("failed to delete old backup", "path", path, "error", err)
+		} else {
+			deleted++
+			slog.Info("deleted old backup", "path", path)
+		}
+	}
+
+	slog.Info("cleanup completed", "kept", kept, "deleted", deleted)
+	return nil
+}
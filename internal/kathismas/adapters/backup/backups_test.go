@@ -0,0 +1,64 @@
+package backup
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackups_CreateBackup(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, FilePrefix+"20200101_020000"+ArchiveExt)
+	require.NoError(t, os.WriteFile(old, []byte("broken"), 0o600))
+
+	backups := NewBackups(openTestDB(t).Bolt, dir, "abc123", nil, RetentionPolicy{Daily: 1})
+	path, err := backups.CreateBackup()
+	require.NoError(t, err)
+
+	files, err := ListFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "the policy prunes the old backup")
+	assert.Equal(t, path, files[0].Path)
+
+	manifest, report, err := VerifyFile(path, nil)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, "abc123", manifest.Revision)
+}
+
+func TestBackups_CreateBackup_NoDir(t *testing.T) {
+	_, err := NewBackups(openTestDB(t).Bolt, "", "abc123", nil, RetentionPolicy{Daily: 1}).CreateBackup()
+	require.Error(t, err)
+}
+
+func TestBackups_OpenSnapshot(t *testing.T) {
+	secret := newTestKeyFile(t)
+	backups := NewBackups(openTestDB(t).Bolt, "", "abc123", secret, RetentionPolicy{Daily: 1})
+
+	name, archive, err := backups.OpenSnapshot()
+	require.NoError(t, err)
+	assert.True(t, IsBackupFile(name), name)
+	assert.True(t, strings.HasSuffix(name, EncryptedExt), name)
+
+	path := filepath.Join(t.TempDir(), name)
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = io.Copy(out, archive)
+	require.NoError(t, err)
+	require.NoError(t, out.Close())
+	require.NoError(t, archive.Close())
+
+	manifest, report, err := VerifyFile(path, secret)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, 1, manifest.Groups)
+
+	tmp, ok := archive.(*tempArchive)
+	require.True(t, ok)
+	assert.NoDirExists(t, tmp.dir, "closing the archive removes the snapshot")
+}
@@ -0,0 +1,304 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// encryptedMagic starts every encrypted archive
+const encryptedMagic = "FTR-ENC1"
+
+const (
+	secretPassphrase byte = 1
+	secretKeyFile    byte = 2
+)
+
+const (
+	saltSize        = 16
+	noncePrefixSize = 7
+	// headerSize is the magic, the secret kind, the salt, the PBKDF2 iterations and the nonce prefix
+	headerSize = len(encryptedMagic) + 1 + saltSize + 4 + noncePrefixSize
+	// chunkSize is how much plaintext one sealed chunk holds
+	chunkSize = 64 << 10
+	// passphraseIterations of PBKDF2-SHA256 follow the OWASP recommendation
+	passphraseIterations = 600_000
+	// maxPassphraseIterations keeps a crafted header from making the key derivation run for hours
+	maxPassphraseIterations = 10 * passphraseIterations
+	// minKeyFileSize keeps a key file from being weaker than the AES-256 key derived from it
+	minKeyFileSize = 32
+	keyFileInfo    = "for-twenty-readers backup key"
+)
+
+// ErrSecretRequired is returned when an encrypted archive is read without a passphrase or a key file
+var ErrSecretRequired = errors.New("backup is encrypted, a passphrase or a key file is required")
+
+// ErrWrongSecret is returned when an archive does not open with the given secret or its data was changed
+var ErrWrongSecret = errors.New("backup does not decrypt: wrong passphrase or key file, or the file was changed")
+
+// Secret is what an archive is encrypted with: a passphrase or the contents of a key file
+type Secret struct {
+	kind     byte
+	material []byte
+}
+
+// PassphraseEnv is the environment variable the backup tools read the passphrase from,
+// so it does not show up in the process list
+const PassphraseEnv = "BACKUP_PASSPHRASE"
+
+// NewSecret returns the secret of a passphrase or of a key file, nil when neither is given
+func NewSecret(passphrase, keyFile string) (*Secret, error) {
+	switch {
+	case passphrase != "" && keyFile != "":
+		return nil, errors.New("either a passphrase or a key file can be used, not both")
+	case passphrase != "":
+		return NewPassphrase(passphrase)
+	case keyFile != "":
+		return ReadKeyFile(keyFile)
+	default:
+		return nil, nil
+	}
+}
+
+func NewPassphrase(passphrase string) (*Secret, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+	return &Secret{kind: secretPassphrase, material: []byte(passphrase)}, nil
+}
+
+// ReadKeyFile reads a key file, any file of random bytes will do, e.g. one made by `openssl rand 32`
+func ReadKeyFile(path string) (*Secret, error) {
+	material, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+	if len(material) < minKeyFileSize {
+		return nil, fmt.Errorf("key file %s is %d bytes, at least %d are required", path, len(material), minKeyFileSize)
+	}
+	return &Secret{kind: secretKeyFile, material: material}, nil
+}
+
+func (s *Secret) key(kind byte, salt []byte, iterations int) ([]byte, error) {
+	if kind != s.kind {
+		if kind == secretPassphrase {
+			return nil, errors.New("backup is encrypted with a passphrase, not a key file")
+		}
+		return nil, errors.New("backup is encrypted with a key file, not a passphrase")
+	}
+	var key []byte
+	var err error
+	if kind == secretPassphrase {
+		key, err = pbkdf2.Key(sha256.New, string(s.material), salt, iterations, 32)
+	} else {
+		key, err = hkdf.Key(sha256.New, s.material, salt, keyFileInfo, 32)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error deriving key: %w", err)
+	}
+	return key, nil
+}
+
+// encryptWriter seals the stream in chunks of chunkSize with AES-256-GCM. The nonce of a chunk is the
+// random prefix, the chunk number and a flag of the last chunk, so chunks can not be reordered and
+// a cut off archive does not decrypt. The header is authenticated with every chunk.
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+func newEncryptWriter(w io.Writer, secret *Secret) (*encryptWriter, error) {
+	header := make([]byte, headerSize)
+	copy(header, encryptedMagic)
+	header[len(encryptedMagic)] = secret.kind
+	salt := header[len(encryptedMagic)+1 : len(encryptedMagic)+1+saltSize]
+	iterations := header[len(encryptedMagic)+1+saltSize : len(encryptedMagic)+1+saltSize+4]
+	noncePrefix := header[headerSize-noncePrefixSize:]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	binary.BigEndian.PutUint32(iterations, passphraseIterations)
+
+	aead, err := newAEAD(secret, header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("error writing encryption header: %w", err)
+	}
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		nonce:  make([]byte, aead.NonceSize()),
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// a full chunk is sealed only when more data follows, so the last chunk is never a full one
+		// that could not tell it is the last
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close seals the last chunk, it does not close the underlying writer
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	chunkNonce(e.nonce, e.header, e.counter, last)
+	if _, err := e.w.Write(e.aead.Seal(nil, e.nonce, e.buf, e.header)); err != nil {
+		return fmt.Errorf("error writing encrypted backup: %w", err)
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptReader opens the chunks sealed by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	counter uint32
+	sealed  []byte
+	plain   []byte
+	done    bool
+}
+
+func newDecryptReader(r io.Reader, secret *Secret) (*decryptReader, error) {
+	if secret == nil {
+		return nil, ErrSecretRequired
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("error reading encryption header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte(encryptedMagic)) {
+		return nil, errors.New("not an encrypted backup")
+	}
+
+	aead, err := newAEAD(secret, header)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:      bufio.NewReaderSize(r, chunkSize+aead.Overhead()+1),
+		aead:   aead,
+		header: header,
+		nonce:  make([]byte, aead.NonceSize()),
+		sealed: make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		d.done = true
+	case err != nil:
+		return fmt.Errorf("error reading encrypted backup: %w", err)
+	default:
+		// a full chunk is the last one only when nothing follows it
+		if _, errPeek := d.r.Peek(1); errors.Is(errPeek, io.EOF) {
+			d.done = true
+		}
+	}
+
+	chunkNonce(d.nonce, d.header, d.counter, d.done)
+	plain, err := d.aead.Open(d.sealed[:0], d.nonce, d.sealed[:n], d.header)
+	if err != nil {
+		return ErrWrongSecret
+	}
+	d.counter++
+	d.plain = plain
+	return nil
+}
+
+func newAEAD(secret *Secret, header []byte) (cipher.AEAD, error) {
+	offset := len(encryptedMagic)
+	kind := header[offset]
+	salt := header[offset+1 : offset+1+saltSize]
+	iterations := binary.BigEndian.Uint32(header[offset+1+saltSize:])
+	if kind != secretPassphrase && kind != secretKeyFile {
+		return nil, fmt.Errorf("unknown backup encryption %d", kind)
+	}
+	if kind == secretPassphrase && (iterations == 0 || iterations > maxPassphraseIterations) {
+		return nil, fmt.Errorf("unsupported number of key derivation iterations %d", iterations)
+	}
+
+	key, err := secret.key(kind, salt, int(iterations))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// chunkNonce is the nonce prefix of the header, the big endian chunk number and 1 for the last chunk
+func chunkNonce(nonce, header []byte, counter uint32, last bool) {
+	copy(nonce, header[headerSize-noncePrefixSize:])
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
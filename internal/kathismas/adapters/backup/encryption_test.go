@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryption_RoundTrip(t *testing.T) {
+	secret := newTestKeyFile(t)
+	// the sizes around the chunk size end the stream with a short, a full and an empty last chunk
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize} {
+		plain := bytes.Repeat([]byte{7}, size)
+
+		var sealed bytes.Buffer
+		encrypted, err := newEncryptWriter(&sealed, secret)
+		require.NoError(t, err)
+		_, err = encrypted.Write(plain)
+		require.NoError(t, err)
+		require.NoError(t, encrypted.Close())
+
+		decrypted, err := newDecryptReader(bytes.NewReader(sealed.Bytes()), secret)
+		require.NoError(t, err)
+		opened, err := io.ReadAll(decrypted)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plain, opened, "size %d", size)
+
+		// cutting off whole chunks is noticed as well
+		if size > chunkSize {
+			cut := headerSize + chunkSize + encrypted.aead.Overhead()
+			decrypted, err = newDecryptReader(bytes.NewReader(sealed.Bytes()[:cut]), secret)
+			require.NoError(t, err)
+			_, err = io.ReadAll(decrypted)
+			assert.ErrorIs(t, err, ErrWrongSecret, "size %d", size)
+		}
+	}
+}
+
+func TestSecret_Errors(t *testing.T) {
+	_, err := NewPassphrase("")
+	assert.Error(t, err)
+
+	_, err = ReadKeyFile(t.TempDir() + "/missing.key")
+	assert.Error(t, err)
+}
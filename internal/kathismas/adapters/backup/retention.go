@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File is a backup in the backup directory
+type File struct {
+	Path string
+	// CreatedAt is the time in the file name, the modification time for a name without one
+	CreatedAt time.Time
+}
+
+// ListFiles returns the backups in dir, the newest first
+func ListFiles(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup directory: %w", err)
+	}
+
+	var files []File
+	for _, entry := range entries {
+		if entry.IsDir() || !IsBackupFile(entry.Name()) {
+			continue
+		}
+		createdAt, ok := fileTime(entry.Name())
+		if !ok {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			createdAt = info.ModTime()
+		}
+		files = append(files, File{Path: filepath.Join(dir, entry.Name()), CreatedAt: createdAt})
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+	return files, nil
+}
+
+func fileTime(name string) (time.Time, bool) {
+	stamp := strings.TrimPrefix(name, FilePrefix)
+	for _, layout := range []string{fileTimeLayout, secondFileTimeLayout} {
+		if len(stamp) < len(layout) {
+			continue
+		}
+		if createdAt, err := time.ParseInLocation(layout, stamp[:len(layout)], time.Local); err == nil {
+			return createdAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// RetentionPolicy is a grandfather-father-son policy: it keeps the newest backup of each of the last
+// Daily days, Weekly ISO weeks, Monthly months and Yearly years that have backups
+type RetentionPolicy struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+	Yearly  int
+}
+
+func (p RetentionPolicy) Validate() error {
+	if p.Daily < 0 || p.Weekly < 0 || p.Monthly < 0 || p.Yearly < 0 {
+		return errors.New("backup counts to keep can not be negative")
+	}
+	if p.Daily+p.Weekly+p.Monthly+p.Yearly == 0 {
+		return errors.New("retention policy keeps no backups")
+	}
+	return nil
+}
+
+// RetentionDecision tells whether a backup is kept and by which rules
+type RetentionDecision struct {
+	File    File
+	Keep    bool
+	Reasons []string
+}
+
+// ReasonNewestVerified keeps the newest backup that passed verification whatever the policy says
+const ReasonNewestVerified = "newest verified"
+
+// Plan decides about every backup, files are expected the newest first as ListFiles returns them.
+// The backup at newestVerified is always kept, an empty path protects none.
+func (p RetentionPolicy) Plan(files []File, newestVerified string) []RetentionDecision {
+	rules := []struct {
+		count int
+		name  string
+		key   func(t time.Time) string
+	}{
+		{count: p.Daily, name: "daily", key: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{count: p.Weekly, name: "weekly", key: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{count: p.Monthly, name: "monthly", key: func(t time.Time) string { return t.Format("2006-01") }},
+		{count: p.Yearly, name: "yearly", key: func(t time.Time) string { return t.Format("2006") }},
+	}
+
+	decisions := make([]RetentionDecision, len(files))
+	for i, file := range files {
+		decisions[i].File = file
+	}
+	for _, rule := range rules {
+		seen := make(map[string]bool)
+		for i := range decisions {
+			key := rule.key(decisions[i].File.CreatedAt.Local())
+			if seen[key] || len(seen) == rule.count {
+				continue
+			}
+			seen[key] = true
+			decisions[i].Keep = true
+			decisions[i].Reasons = append(decisions[i].Reasons, rule.name+" "+key)
+		}
+	}
+	for i := range decisions {
+		if newestVerified != "" && decisions[i].File.Path == newestVerified {
+			decisions[i].Keep = true
+			decisions[i].Reasons = append(decisions[i].Reasons, ReasonNewestVerified)
+		}
+	}
+	return decisions
+}
+
+// NewestVerified returns the path of the newest backup verify accepts, files are expected the newest first.
+// Verification stops at the first accepted backup, so the older ones are not read.
+func NewestVerified(files []File, verify func(path string) error) (string, bool) {
+	for _, file := range files {
+		if verify(file.Path) == nil {
+			return file.Path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dailyFiles returns a backup at 02:00 of each of the days before the day, the newest first
+func dailyFiles(day time.Time, days int) []File {
+	files := make([]File, days)
+	for i := range files {
+		createdAt := day.AddDate(0, 0, -i)
+		files[i] = File{
+			Path:      FilePrefix + createdAt.Format(fileTimeLayout) + ArchiveExt,
+			CreatedAt: createdAt,
+		}
+	}
+	return files
+}
+
+func keptPaths(decisions []RetentionDecision) []string {
+	var kept []string
+	for _, decision := range decisions {
+		if decision.Keep {
+			kept = append(kept, decision.File.Path)
+		}
+	}
+	return kept
+}
+
+func TestRetentionPolicy_Plan(t *testing.T) {
+	// Saturday
+	day := time.Date(2026, time.March, 14, 2, 0, 0, 0, time.Local)
+	files := dailyFiles(day, 400)
+	path := func(year int, month time.Month, d int) string {
+		return FilePrefix + time.Date(year, month, d, 2, 0, 0, 0, time.Local).Format(fileTimeLayout) + ArchiveExt
+	}
+
+	tests := []struct {
+		name           string
+		policy         RetentionPolicy
+		newestVerified string
+		want           []string
+	}{
+		{
+			name:   "daily",
+			policy: RetentionPolicy{Daily: 3},
+			want:   []string{path(2026, 3, 14), path(2026, 3, 13), path(2026, 3, 12)},
+		},
+		{
+			name:   "weekly keeps the newest backup of each week",
+			policy: RetentionPolicy{Weekly: 3},
+			want:   []string{path(2026, 3, 14), path(2026, 3, 8), path(2026, 3, 1)},
+		},
+		{
+			name:   "monthly and yearly",
+			policy: RetentionPolicy{Monthly: 2, Yearly: 3},
+			want:   []string{path(2026, 3, 14), path(2026, 2, 28), path(2025, 12, 31)},
+		},
+		{
+			name:   "rules share the backups they keep",
+			policy: RetentionPolicy{Daily: 2, Weekly: 2, Monthly: 1},
+			want:   []string{path(2026, 3, 14), path(2026, 3, 13), path(2026, 3, 8)},
+		},
+		{
+			name:           "newest verified is kept",
+			policy:         RetentionPolicy{Daily: 1},
+			newestVerified: path(2026, 1, 5),
+			want:           []string{path(2026, 3, 14), path(2026, 1, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, keptPaths(tt.policy.Plan(files, tt.newestVerified)))
+		})
+	}
+}
+
+func TestRetentionPolicy_Plan_Reasons(t *testing.T) {
+	files := dailyFiles(time.Date(2026, time.March, 14, 2, 0, 0, 0, time.Local), 2)
+	decisions := RetentionPolicy{Daily: 1, Weekly: 1}.Plan(files, files[1].Path)
+
+	assert.Equal(t, []string{"daily 2026-03-14", "weekly 2026-W11"}, decisions[0].Reasons)
+	assert.Equal(t, []string{ReasonNewestVerified}, decisions[1].Reasons)
+}
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	require.NoError(t, RetentionPolicy{Daily: 7}.Validate())
+	require.Error(t, RetentionPolicy{}.Validate())
+	require.Error(t, RetentionPolicy{Daily: 7, Weekly: -1}.Validate())
+}
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"for-twenty-readers_20260102_020000.db",
+		"for-twenty-readers_20260103_020000.tar.gz.enc",
+		"for-twenty-readers_20260101_020000.tar.gz",
+		"for-twenty-readers_20260103_020000.250.tar.gz",
+		"for-twenty-readers_20260103_020000.500.tar.gz.enc",
+		"for-twenty-readers.db",
+		".archive-123",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	files, err := ListFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+	assert.Equal(t, filepath.Join(dir, "for-twenty-readers_20260103_020000.500.tar.gz.enc"), files[0].Path)
+	assert.Equal(t, filepath.Join(dir, "for-twenty-readers_20260103_020000.250.tar.gz"), files[1].Path)
+	assert.True(t, time.Date(2026, time.January, 3, 2, 0, 0, 250*int(time.Millisecond), time.Local).Equal(files[1].CreatedAt))
+	assert.Equal(t, filepath.Join(dir, "for-twenty-readers_20260103_020000.tar.gz.enc"), files[2].Path)
+	assert.Equal(t, filepath.Join(dir, "for-twenty-readers_20260101_020000.tar.gz"), files[4].Path)
+	assert.True(t, time.Date(2026, time.January, 2, 2, 0, 0, 0, time.Local).Equal(files[3].CreatedAt))
+}
+
+func TestNewestVerified(t *testing.T) {
+	files := dailyFiles(time.Date(2026, time.March, 14, 2, 0, 0, 0, time.Local), 3)
+	var verified []string
+	path, ok := NewestVerified(files, func(path string) error {
+		verified = append(verified, path)
+		if path == files[0].Path {
+			return errors.New("broken")
+		}
+		return nil
+	})
+	require.True(t, ok)
+	assert.Equal(t, files[1].Path, path)
+	assert.Equal(t, []string{files[0].Path, files[1].Path}, verified, "older backups are not verified")
+
+	_, ok = NewestVerified(files, func(string) error { return errors.New("broken") })
+	assert.False(t, ok)
+}
@@ -0,0 +1,182 @@
+package adapters
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+// KathismasDB holds the kathismas of one reader for one day.
+// Calendars stored before groups could have fewer than 20 readers
+// keep a single number per day instead of a list.
+type KathismasDB []int
+
+func (k *KathismasDB) UnmarshalJSON(data []byte) error {
+	var single int
+	if err := json.Unmarshal(data, &single); err == nil {
+		*k = KathismasDB{single}
+		return nil
+	}
+
+	var list []int
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid kathismas value %s: %w", data, err)
+	}
+	*k = list
+	return nil
+}
+
+// CalendarMapDB is stored in a compact form, see MarshalJSON. Calendars stored as JSON maps
+// before that are still read and are written compactly on the next save.
+type CalendarMapDB map[int]map[int]KathismasDB
+
+// calendarMapCodecVersion is the first byte of a compactly encoded calendar
+const calendarMapCodecVersion = 1
+
+// calendarMapHeaderSize is the codec version, the number of readers and the number of days
+const calendarMapHeaderSize = 4
+
+// MarshalJSON stores the calendar as a base64 string of bytes: the header, then for every reader
+// and every day the number of kathismas read followed by the kathismas, one byte each.
+// A calendar that does not fit into bytes is stored as a JSON map.
+func (c CalendarMapDB) MarshalJSON() ([]byte, error) {
+	data, ok := c.encode()
+	if !ok {
+		return json.Marshal(map[int]map[int]KathismasDB(c))
+	}
+	return json.Marshal(data)
+}
+
+func (c *CalendarMapDB) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '"' {
+		var legacy map[int]map[int]KathismasDB
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return fmt.Errorf("invalid calendar: %w", err)
+		}
+		*c = legacy
+		return nil
+	}
+
+	var encoded []byte
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("invalid calendar: %w", err)
+	}
+	calendar, err := decodeCalendarMap(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid calendar: %w", err)
+	}
+	*c = calendar
+	return nil
+}
+
+// encode reports false when readers are not numbered 1..n or a number does not fit into its bytes
+func (c CalendarMapDB) encode() ([]byte, bool) {
+	readersCount := len(c)
+	if readersCount > math.MaxUint8 {
+		return nil, false
+	}
+
+	numberDays := 0
+	for readerNumber, days := range c {
+		if readerNumber < 1 || readerNumber > readersCount {
+			return nil, false
+		}
+		for day, kathismas := range days {
+			if day < 1 || day > math.MaxUint16 || len(kathismas) > math.MaxUint8 {
+				return nil, false
+			}
+			for _, kathisma := range kathismas {
+				if kathisma < 0 || kathisma > math.MaxUint8 {
+					return nil, false
+				}
+			}
+			numberDays = max(numberDays, day)
+		}
+	}
+
+	data := make([]byte, calendarMapHeaderSize, calendarMapHeaderSize+2*readersCount*numberDays)
+	data[0] = calendarMapCodecVersion
+	data[1] = byte(readersCount)
+	binary.BigEndian.PutUint16(data[2:], uint16(numberDays))
+	for readerNumber := 1; readerNumber <= readersCount; readerNumber++ {
+		days := c[readerNumber]
+		for day := 1; day <= numberDays; day++ {
+			kathismas := days[day]
+			data = append(data, byte(len(kathismas)))
+			for _, kathisma := range kathismas {
+				data = append(data, byte(kathisma))
+			}
+		}
+	}
+	return data, true
+}
+
+// decodeCalendarMap leaves out the days a reader reads nothing
+func decodeCalendarMap(data []byte) (CalendarMapDB, error) {
+	if len(data) < calendarMapHeaderSize {
+		return nil, errors.New("calendar is too short")
+	}
+	if data[0] != calendarMapCodecVersion {
+		return nil, fmt.Errorf("unknown calendar codec version %d", data[0])
+	}
+
+	readersCount := int(data[1])
+	numberDays := int(binary.BigEndian.Uint16(data[2:]))
+	calendar := make(CalendarMapDB, readersCount)
+	pos := calendarMapHeaderSize
+	for readerNumber := 1; readerNumber <= readersCount; readerNumber++ {
+		days := make(map[int]KathismasDB, numberDays)
+		for day := 1; day <= numberDays; day++ {
+			if pos >= len(data) {
+				return nil, errors.New("calendar is truncated")
+			}
+			count := int(data[pos])
+			pos++
+			if count == 0 {
+				continue
+			}
+			if pos+count > len(data) {
+				return nil, errors.New("calendar is truncated")
+			}
+			kathismas := make(KathismasDB, count)
+			for i := range kathismas {
+				kathismas[i] = int(data[pos+i])
+			}
+			pos += count
+			days[day] = kathismas
+		}
+		calendar[readerNumber] = days
+	}
+	if pos != len(data) {
+		return nil, fmt.Errorf("calendar has %d extra bytes", len(data)-pos)
+	}
+	return calendar, nil
+}
+
+func marshalCalendarMap(calendar domain.CalendarMap) CalendarMapDB {
+	dbCalendar := make(CalendarMapDB, len(calendar))
+	for readerNumber, days := range calendar {
+		dbDays := make(map[int]KathismasDB, len(days))
+		for day, kathismas := range days {
+			dbDays[day] = kathismas
+		}
+		dbCalendar[readerNumber] = dbDays
+	}
+	return dbCalendar
+}
+
+func unmarshalCalendarMap(dbCalendar CalendarMapDB) domain.CalendarMap {
+	calendar := make(domain.CalendarMap, len(dbCalendar))
+	for readerNumber, dbDays := range dbCalendar {
+		days := make(map[int][]int, len(dbDays))
+		for day, kathismas := range dbDays {
+			days[day] = kathismas
+		}
+		calendar[readerNumber] = days
+	}
+	return calendar
+}
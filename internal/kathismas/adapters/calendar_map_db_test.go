@@ -0,0 +1,194 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/excel"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyCalendarMapDB is CalendarMapDB as it was stored before the compact encoding
+type legacyCalendarMapDB map[int]map[int]KathismasDB
+
+// saveLegacyGroupCalendar stores the calendar as a JSON map the way it was stored before the compact encoding
+func saveLegacyGroupCalendar(tb testing.TB, db *storm.DB, groupID string, calendar domain.CalendarOfReader) {
+	tb.Helper()
+	ref := marshalCalendarRef(calendar)
+	dbCalendar := GroupCalendarDB{
+		Key:           groupCalendarKey(groupID, calendar.Year, ref.YearMode),
+		GroupID:       groupID,
+		CalendarRefDB: ref,
+	}
+	// Save keeps storm's indexes, the record is then replaced as is
+	require.NoError(tb, db.Save(&dbCalendar))
+	record, err := json.Marshal(dbCalendar)
+	require.NoError(tb, err)
+	compact, err := json.Marshal(ref.Calendar)
+	require.NoError(tb, err)
+	legacy, err := json.Marshal(legacyCalendarMapDB(ref.Calendar))
+	require.NoError(tb, err)
+
+	record = bytes.Replace(record, compact, legacy, 1)
+	require.NoError(tb, db.Set(groupCalendarBucket, dbCalendar.Key, json.RawMessage(record)))
+}
+
+func newGeneratedCalendar(readersCount int, lentRule domain.LentReadingRule) domain.CalendarMap {
+	return excel.NewCalendarGenerator().CalculateForGroup(
+		2026, 1, readersCount, domain.CalendarYearCivil, lentRule, domain.DefaultNoReadingPeriods(),
+	)
+}
+
+func TestCalendarMapDB_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar domain.CalendarMap
+	}{
+		{name: "full group", calendar: newGeneratedCalendar(domain.KathismasCount, domain.LentReadingOrdinary)},
+		{name: "small group with double psalter", calendar: newGeneratedCalendar(3, domain.LentReadingDoublePsalter)},
+		{name: "empty", calendar: domain.CalendarMap{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(marshalCalendarMap(tt.calendar))
+			require.NoError(t, err)
+			assert.Equal(t, byte('"'), data[0], "calendar is stored compactly")
+
+			var decoded CalendarMapDB
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.calendar, unmarshalCalendarMap(decoded))
+		})
+	}
+}
+
+func TestCalendarMapDB_ReadsLegacyJSON(t *testing.T) {
+	calendar := newGeneratedCalendar(domain.KathismasCount, domain.LentReadingOrdinary)
+	legacy, err := json.Marshal(legacyCalendarMapDB(marshalCalendarMap(calendar)))
+	require.NoError(t, err)
+
+	var decoded CalendarMapDB
+	require.NoError(t, json.Unmarshal(legacy, &decoded))
+	assert.Equal(t, calendar, unmarshalCalendarMap(decoded))
+
+	// calendars stored before groups could have fewer than 20 readers keep a single number per day
+	require.NoError(t, json.Unmarshal([]byte(`{"1":{"1":5,"2":[6]}}`), &decoded))
+	assert.Equal(t, domain.CalendarMap{1: {1: {5}, 2: {6}}}, unmarshalCalendarMap(decoded))
+}
+
+func TestCalendarMapDB_FallsBackToJSON(t *testing.T) {
+	// readers are not numbered from 1, so the calendar cannot be stored compactly
+	calendar := CalendarMapDB{2: {1: {1}}}
+	data, err := json.Marshal(calendar)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"2":{"1":[1]}}`, string(data))
+
+	var decoded CalendarMapDB
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, calendar, decoded)
+}
+
+func TestCalendarMapDB_RejectsBrokenData(t *testing.T) {
+	encoded, ok := CalendarMapDB{1: {1: {1, 2}}}.encode()
+	require.True(t, ok)
+
+	tests := map[string][]byte{
+		"too short":       encoded[:2],
+		"unknown version": append([]byte{9}, encoded[1:]...),
+		"truncated":       encoded[:len(encoded)-1],
+		"extra bytes":     append(append([]byte{}, encoded...), 0),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeCalendarMap(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompactCalendarMaps(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	group, err := domain.NewReaderGroup("Legacy Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, group))
+
+	calendar := newTestCalendar(2026)
+	saveLegacyGroupCalendar(t, db, group.ID.String(), calendar)
+
+	compacted, err := runMigration(db, compactCalendarMaps)
+	require.NoError(t, err)
+	assert.Equal(t, 1, compacted)
+
+	compacted, err = runMigration(db, compactCalendarMaps)
+	require.NoError(t, err)
+	assert.Zero(t, compacted)
+
+	stored, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Calendars, 1)
+	assert.Equal(t, calendar.Calendar, stored.Calendars[0].Calendar)
+}
+
+func BenchmarkCalendarMapDB_Size(b *testing.B) {
+	calendar := marshalCalendarMap(newGeneratedCalendar(domain.KathismasCount, domain.LentReadingOrdinary))
+	for _, codec := range []struct {
+		name  string
+		value any
+	}{
+		{name: "json map", value: legacyCalendarMapDB(calendar)},
+		{name: "compact", value: calendar},
+	} {
+		b.Run(codec.name, func(b *testing.B) {
+			var size int
+			for b.Loop() {
+				data, err := json.Marshal(codec.value)
+				require.NoError(b, err)
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/calendar")
+		})
+	}
+}
+
+func BenchmarkReaderGroupRepository_GetByIDWithCalendars(b *testing.B) {
+	ctx := context.Background()
+	calendar := newGeneratedCalendar(domain.KathismasCount, domain.LentReadingOrdinary)
+
+	for _, stored := range []struct {
+		name    string
+		compact bool
+	}{
+		{name: "json map", compact: false},
+		{name: "compact", compact: true},
+	} {
+		b.Run(stored.name, func(b *testing.B) {
+			db := openTestDB(b)
+			repo := NewReaderGroupRepository(db)
+			group, err := domain.NewReaderGroup("Benchmark Group", domain.KathismasCount)
+			require.NoError(b, err)
+			require.NoError(b, repo.Create(ctx, group))
+
+			for year := 2026; year < 2036; year++ {
+				groupCalendar := *domain.NewCalendarOfReader(year, domain.CalendarYearCivil, 1, calendar)
+				if stored.compact {
+					require.NoError(b, saveGroupCalendars(db, group.ID.String(), []domain.CalendarOfReader{groupCalendar}))
+					continue
+				}
+				saveLegacyGroupCalendar(b, db, group.ID.String(), groupCalendar)
+			}
+
+			for b.Loop() {
+				_, err := repo.GetByIDWithCalendars(ctx, group.ID)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
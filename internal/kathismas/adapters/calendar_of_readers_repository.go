@@ -15,8 +15,9 @@ import (
 type CalendarOfReaderDB struct {
 	ID          uuid.UUID `storm:"id"`
 	Year        int
+	YearMode    string
 	StartOffset int
-	Calendar    domain.CalendarMap
+	Calendar    CalendarMapDB
 	CreatedAt   time.Time `storm:"index"`
 	UpdatedAt   time.Time
 }
@@ -42,8 +43,9 @@ func (cr CalendarOfReaderRepository) GetCalendar(id uuid.UUID) (*domain.Calendar
 	CalendarOfReader := domain.UnmarshallCalendarOfReader(
 		CalendarOfReaderFromDB.ID,
 		CalendarOfReaderFromDB.Year,
+		unmarshalYearMode(CalendarOfReaderFromDB.YearMode),
 		CalendarOfReaderFromDB.StartOffset,
-		CalendarOfReaderFromDB.Calendar,
+		unmarshalCalendarMap(CalendarOfReaderFromDB.Calendar),
 		CalendarOfReaderFromDB.CreatedAt,
 		CalendarOfReaderFromDB.UpdatedAt,
 	)
@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	bolt "go.etcd.io/bbolt"
+)
+
+// legacyCalendarMapMarker starts a calendar stored as a JSON map instead of compact bytes
+var legacyCalendarMapMarker = []byte(`"calendar":{`)
+
+// migrateEmbeddedCalendars moves the calendars stored inside group records into their own bucket
+// and returns how many were moved. Groups already migrated are left as they are.
+func migrateEmbeddedCalendars(node storm.Node, _ *bolt.Tx) (int, error) {
+	var dbGroups []ReaderGroupDB
+	if err := node.All(&dbGroups); err != nil {
+		return 0, fmt.Errorf("error getting reader groups: %w", err)
+	}
+
+	moved := 0
+	for i := range dbGroups {
+		dbGroup := &dbGroups[i]
+		if len(dbGroup.Calendars) == 0 {
+			continue
+		}
+		if err := migrateGroupCalendars(node, dbGroup); err != nil {
+			return moved, fmt.Errorf("error migrating calendars of reader group %s: %w", dbGroup.ID, err)
+		}
+		moved += len(dbGroup.Calendars)
+	}
+	return moved, nil
+}
+
+func migrateGroupCalendars(node storm.Node, dbGroup *ReaderGroupDB) error {
+	for _, dbCalendar := range dbGroup.Calendars {
+		yearMode := string(unmarshalYearMode(dbCalendar.YearMode))
+		dbCalendar.YearMode = yearMode
+		if err := node.Save(&GroupCalendarDB{
+			Key:           groupCalendarKey(dbGroup.ID, dbCalendar.Year, yearMode),
+			GroupID:       dbGroup.ID,
+			CalendarRefDB: dbCalendar,
+		}); err != nil {
+			return fmt.Errorf("error saving calendar %d: %w", dbCalendar.Year, err)
+		}
+	}
+
+	// Save replaces the whole record, Update would keep the calendars
+	migrated := *dbGroup
+	migrated.Calendars = nil
+	if err := node.Save(&migrated); err != nil {
+		return fmt.Errorf("error saving reader group: %w", err)
+	}
+	return nil
+}
+
+// compactCalendarMaps rewrites the group calendars stored as JSON maps in the compact form
+// and returns how many were rewritten
+func compactCalendarMaps(node storm.Node, tx *bolt.Tx) (int, error) {
+	bucket := tx.Bucket([]byte(groupCalendarBucket))
+	if bucket == nil {
+		return 0, nil
+	}
+	var keys []string
+	err := bucket.ForEach(func(key, value []byte) error {
+		// nested buckets hold storm indexes
+		if value != nil && bytes.Contains(value, legacyCalendarMapMarker) {
+			keys = append(keys, string(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error looking for calendars to compact: %w", err)
+	}
+
+	for _, key := range keys {
+		var dbCalendar GroupCalendarDB
+		if err := node.One("Key", key, &dbCalendar); err != nil {
+			return 0, fmt.Errorf("error getting calendar %s: %w", key, err)
+		}
+		if err := node.Save(&dbCalendar); err != nil {
+			return 0, fmt.Errorf("error saving calendar %s: %w", key, err)
+		}
+	}
+	return len(keys), nil
+}
+
+// repairCalendarTimestamps replaces the calendar timestamps which do not parse with the time of the migration,
+// otherwise every read falls back to the time of the read
+func repairCalendarTimestamps(node storm.Node, _ *bolt.Tx) (int, error) {
+	var dbCalendars []GroupCalendarDB
+	if err := node.All(&dbCalendars); err != nil {
+		return 0, fmt.Errorf("error getting calendars: %w", err)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	repaired := 0
+	for i := range dbCalendars {
+		dbCalendar := &dbCalendars[i]
+		changed := false
+		for _, timestamp := range []*string{&dbCalendar.CreatedAt, &dbCalendar.UpdatedAt} {
+			if _, err := time.Parse(time.RFC3339, *timestamp); err != nil {
+				*timestamp = now
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := node.Save(dbCalendar); err != nil {
+			return 0, fmt.Errorf("error saving calendar %s: %w", dbCalendar.Key, err)
+		}
+		repaired++
+	}
+	return repaired, nil
+}
@@ -0,0 +1,162 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	bolt "go.etcd.io/bbolt"
+)
+
+// psalmReaderBucket is the bucket storm keeps PsalmReaderTGDB records in
+const psalmReaderBucket = "PsalmReaderTGDB"
+
+// CopyStats counts the records CopyToRepositories wrote
+type CopyStats struct {
+	Groups       int
+	Calendars    int
+	PsalmReaders int
+	// ClearedTelegramIDs counts the readers that lost a Telegram ID registered in several groups
+	ClearedTelegramIDs int
+}
+
+// CopyToRepositories copies everything stored in the bolt database into the repositories of another backend.
+// Groups are copied with their calendars and start over from version 1 in the new backend.
+// A Telegram ID registered in several groups before the index stays with the group the index
+// points it at, the other groups are copied without it.
+func CopyToRepositories(
+	ctx context.Context,
+	db *storm.DB,
+	groups domain.RepositoryReaderGroup,
+	readers domain.RepositoryPsalmReader,
+	calendars domain.RepositoryCalendarOfReaders,
+) (CopyStats, error) {
+	var stats CopyStats
+
+	source := NewReaderGroupRepository(db)
+	stored, err := source.GetAll(ctx)
+	if err != nil {
+		return stats, err
+	}
+	owners := make(map[int64]bool)
+	for i := range stored {
+		group, err := source.GetByIDWithCalendars(ctx, stored[i].ID)
+		if err != nil {
+			return stats, err
+		}
+		cleared, err := clearDuplicateTelegramIDs(ctx, source, group, owners)
+		if err != nil {
+			return stats, err
+		}
+		stats.ClearedTelegramIDs += cleared
+		if err := groups.Create(ctx, group); err != nil {
+			return stats, fmt.Errorf("error copying reader group %s: %w", group.ID, err)
+		}
+		stats.Groups++
+		stats.Calendars += len(group.Calendars)
+	}
+
+	dbReaders, err := allPsalmReaders(db)
+	if err != nil {
+		return stats, err
+	}
+	for _, dbReader := range dbReaders {
+		reader := domain.UnmarshallPsalmReader(
+			dbReader.ID,
+			dbReader.ReaderNumber,
+			dbReader.Username,
+			dbReader.TelegramID,
+			dbReader.Phone,
+			dbReader.CreatedAt,
+			dbReader.UpdatedAt,
+		)
+		if err := readers.CreatePsalmReaderTG(ctx, reader); err != nil {
+			return stats, fmt.Errorf("error copying psalm reader %s: %w", dbReader.ID, err)
+		}
+		stats.PsalmReaders++
+	}
+
+	var dbCalendars []CalendarOfReaderDB
+	if err := db.All(&dbCalendars); err != nil {
+		return stats, fmt.Errorf("error getting calendars of readers: %w", err)
+	}
+	for _, dbCalendar := range dbCalendars {
+		calendar := domain.UnmarshallCalendarOfReader(
+			dbCalendar.ID,
+			dbCalendar.Year,
+			unmarshalYearMode(dbCalendar.YearMode),
+			dbCalendar.StartOffset,
+			unmarshalCalendarMap(dbCalendar.Calendar),
+			dbCalendar.CreatedAt,
+			dbCalendar.UpdatedAt,
+		)
+		if err := calendars.CreateCalendarOfReader(calendar); err != nil {
+			return stats, fmt.Errorf("error copying calendar %s: %w", dbCalendar.ID, err)
+		}
+		stats.Calendars++
+	}
+
+	return stats, nil
+}
+
+// clearDuplicateTelegramIDs clears the Telegram IDs of the readers of the group that another group keeps
+// and returns how many were cleared. The owners are the Telegram IDs copied so far.
+func clearDuplicateTelegramIDs(
+	ctx context.Context,
+	source *ReaderGroupRepository,
+	group *domain.ReaderGroup,
+	owners map[int64]bool,
+) (int, error) {
+	cleared := 0
+	for i := range group.Readers {
+		telegramID := group.Readers[i].TelegramID
+		if telegramID == 0 {
+			continue
+		}
+		owner, err := source.GetByTelegramID(ctx, telegramID)
+		var notFound domain.TelegramReaderNotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return cleared, err
+		}
+		keep := !owners[telegramID] && (err != nil || owner.ID == group.ID)
+		if keep {
+			owners[telegramID] = true
+			continue
+		}
+		slog.Warn("telegram ID is registered in several groups, the copy keeps it in one",
+			"telegram_id", telegramID, "group_id", group.ID, "reader_id", group.Readers[i].ID)
+		group.Readers[i].TelegramID = 0
+		cleared++
+	}
+	return cleared, nil
+}
+
+// allPsalmReaders reads the bucket directly, storm refuses the tags of PsalmReaderTGDB
+func allPsalmReaders(db *storm.DB) ([]PsalmReaderTGDB, error) {
+	var dbReaders []PsalmReaderTGDB
+	err := db.Bolt.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(psalmReaderBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(key, value []byte) error {
+			// nested buckets hold the storm metadata and indexes
+			if value == nil {
+				return nil
+			}
+			var dbReader PsalmReaderTGDB
+			if err := db.Codec().Unmarshal(value, &dbReader); err != nil {
+				return fmt.Errorf("invalid psalm reader %q: %w", key, err)
+			}
+			dbReaders = append(dbReaders, dbReader)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting psalm readers: %w", err)
+	}
+	return dbReaders, nil
+}
@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/sqlite"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyToRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	source := NewReaderGroupRepository(db)
+
+	group := newTestGroupWithReader(t, "Test Group", 1001)
+	require.NoError(t, group.AddCalendar(newTestCalendar(2026)))
+	require.NoError(t, group.AddCalendar(newTestCalendar(2027)))
+	require.NoError(t, source.Create(ctx, group))
+	require.NoError(t, group.UpdateName("Renamed"))
+	require.NoError(t, source.Update(ctx, group))
+
+	reader, err := domain.NewPsalmReader("Петр", 1002, "", 2)
+	require.NoError(t, err)
+	require.NoError(t, db.Set(psalmReaderBucket, reader.ID.String(),
+		&PsalmReaderTGDB{ID: reader.ID, ReaderNumber: 2, Username: reader.Username}))
+
+	sqliteDB, err := sqlite.Open(filepath.Join(t.TempDir(), "test.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqliteDB.Close() })
+	groups := sqlite.NewReaderGroupRepository(sqliteDB)
+
+	stats, err := CopyToRepositories(ctx, db, groups,
+		sqlite.NewPsalmReaderRepository(sqliteDB), sqlite.NewCalendarOfReaderRepository(sqliteDB))
+	require.NoError(t, err)
+	assert.Equal(t, CopyStats{Groups: 1, Calendars: 2, PsalmReaders: 1}, stats)
+
+	copied, err := groups.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", copied.Name)
+	require.Len(t, copied.Readers, 1)
+	assert.Equal(t, int64(1001), copied.Readers[0].TelegramID)
+	require.Len(t, copied.Calendars, 2)
+	assert.Equal(t, group.Calendars[1].Calendar, copied.Calendars[1].Calendar)
+
+	copiedReader, err := sqlite.NewPsalmReaderRepository(sqliteDB).GetPsalmReaderTG(ctx, reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Петр", copiedReader.Username)
+}
+
+func TestCopyToRepositories_DuplicateTelegramID(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	source := NewReaderGroupRepository(db)
+
+	// groups stored before the index, the same account registered in both of them
+	first := source.marshalToDB(newTestGroupWithReader(t, "First Group", 1001))
+	second := source.marshalToDB(newTestGroupWithReader(t, "Second Group", 1001))
+	require.NoError(t, db.Save(&first))
+	require.NoError(t, db.Save(&second))
+	_, err := runMigration(db, indexTelegramReaders)
+	require.NoError(t, err)
+
+	sqliteDB, err := sqlite.Open(filepath.Join(t.TempDir(), "test.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqliteDB.Close() })
+	groups := sqlite.NewReaderGroupRepository(sqliteDB)
+
+	stats, err := CopyToRepositories(ctx, db, groups,
+		sqlite.NewPsalmReaderRepository(sqliteDB), sqlite.NewCalendarOfReaderRepository(sqliteDB))
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Groups)
+	assert.Equal(t, 1, stats.ClearedTelegramIDs)
+
+	found, err := groups.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID.String(), "the group the index points at keeps the account")
+
+	copied, err := groups.GetByID(ctx, uuid.FromStringOrNil(second.ID))
+	require.NoError(t, err)
+	require.Len(t, copied.Readers, 1)
+	assert.Zero(t, copied.Readers[0].TelegramID)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
@@ -44,11 +45,20 @@ func addKathismaNumbersToXLS(xls *excelize.File, number int, sheetName string) e
 	return nil
 }
 
-func addHeaderOfMonthToWs(xls *excelize.File, sheetName string) error {
-	cellAddressMonth := map[string]string{
-		"B2": "ЯНВ", "C2": "ФЕВ", "D2": "МАРТ", "E2": "АПР",
-		"F2": "МАЙ", "G2": "ИЮН", "H2": "ИЮЛ", "I2": "АВГ",
-		"J2": "СЕН", "K2": "ОКТ", "L2": "НОЯ", "M2": "ДЕК",
+var monthNames = [12]string{"ЯНВ", "ФЕВ", "МАРТ", "АПР", "МАЙ", "ИЮН", "ИЮЛ", "АВГ", "СЕН", "ОКТ", "НОЯ", "ДЕК"}
+
+// monthColumns are the sheet columns of the twelve months in the order they follow in the calendar
+var monthColumns = [12]string{"B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M"}
+
+// monthColumn returns the column of the month in a calendar whose first month is firstMonth
+func monthColumn(month, firstMonth time.Month) string {
+	return monthColumns[(int(month)-int(firstMonth)+12)%12]
+}
+
+func addHeaderOfMonthToWs(xls *excelize.File, sheetName string, firstMonth time.Month) error {
+	cellAddressMonth := make(map[string]string, len(monthNames))
+	for i, name := range monthNames {
+		cellAddressMonth[monthColumn(time.Month(i+1), firstMonth)+"2"] = name
 	}
 	style, err := xls.NewStyle(&excelize.Style{
 		Border: []excelize.Border{
@@ -120,8 +130,8 @@ func getFrameNumberDay(symbol string, start, end int) map[int]string {
 func CreateCalendarForReaderToXLS(
 	xls *excelize.File,
 	calendarTable map[int][]int,
-	allKathisma map[int]int,
-	year int,
+	allKathisma map[int][]int,
+	startDate time.Time,
 	sheetName string,
 ) error {
 	style, _ := xls.NewStyle(&excelize.Style{
@@ -140,9 +150,6 @@ func CreateCalendarForReaderToXLS(
 	})
 
 	cellStep := 1
-	frameMonth := map[int]string{
-		1: "B", 2: "C", 3: "D", 4: "E", 5: "F", 6: "G", 7: "H", 8: "I", 9: "J", 10: "K", 11: "L", 12: "M",
-	}
 	frameNumberDayA := getFrameNumberDay("A", 3, 33) // A = 1
 	frameNumberDayN := getFrameNumberDay("N", 3, 33) // N = 1
 	var errs []error
@@ -162,21 +169,25 @@ func CreateCalendarForReaderToXLS(
 	}
 
 	for month, days := range calendarTable {
-		cellMonth := frameMonth[month]
+		cellMonth := monthColumn(time.Month(month), startDate.Month())
+		year := startDate.Year()
+		if time.Month(month) < startDate.Month() {
+			year++
+		}
 		cellNameIndex := 2
 		var keyDayStr string
 		for _, day := range days {
 			cellNameIndex += cellStep
 			cellName := cellMonth + strconv.Itoa(cellNameIndex)
 			targetDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-			dayNow := targetDate.YearDay()
+			dayNow := domain.DayNumber(startDate, targetDate)
 
 			var cellStyle int
 			if keyDay, ok := allKathisma[dayNow]; !ok {
 				keyDayStr = ""
 				cellStyle = pinkStyle
 			} else {
-				keyDayStr = strconv.Itoa(keyDay)
+				keyDayStr = formatKathismas(keyDay)
 				cellStyle = style
 			}
 
@@ -196,6 +207,14 @@ func CreateCalendarForReaderToXLS(
 	return nil
 }
 
+func formatKathismas(kathismas []int) string {
+	numbers := make([]string, 0, len(kathismas))
+	for _, kathisma := range kathismas {
+		numbers = append(numbers, strconv.Itoa(kathisma))
+	}
+	return strings.Join(numbers, ", ")
+}
+
 func CreateXlSCalendar(startDate time.Time, startKathisma, year int) (*bytes.Buffer, error) {
 	if year == 0 {
 		year = startDate.Year()
@@ -219,7 +238,7 @@ func CreateXlSCalendar(startDate time.Time, startKathisma, year int) (*bytes.Buf
 		if err1 != nil {
 			return nil, fmt.Errorf("failed add kafismas number %v", err1)
 		}
-		err2 := addHeaderOfMonthToWs(xls, sheetName)
+		err2 := addHeaderOfMonthToWs(xls, sheetName, time.January)
 		if err2 != nil {
 			return nil, fmt.Errorf("failed create header of months %v", err2)
 		}
@@ -227,7 +246,9 @@ func CreateXlSCalendar(startDate time.Time, startKathisma, year int) (*bytes.Buf
 		if err3 != nil {
 			return nil, fmt.Errorf("failed add column with number day %v", err3)
 		}
-		err4 := CreateCalendarForReaderToXLS(xls, calendarTable, pair.Value, year, sheetName)
+		err4 := CreateCalendarForReaderToXLS(
+			xls, calendarTable, pair.Value, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), sheetName,
+		)
 		if err4 != nil {
 			return nil, fmt.Errorf("failed create calendar %v", err4)
 		}
@@ -255,18 +276,46 @@ func NewCalendarGenerator() *CalendarGeneratorImpl {
 }
 
 func (g *CalendarGeneratorImpl) GenerateForGroup(
-	year, startOffset int,
+	year, startOffset, readersCount int,
+	yearMode domain.CalendarYearMode,
+	lentRule domain.LentReadingRule,
+	noReadingPeriods []domain.NoReadingPeriod,
 ) (*bytes.Buffer, domain.CalendarMap, error) {
 	if year == 0 {
 		year = time.Now().Year()
 	}
 
-	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
-	calendarTable := services.GetCalendarYear(startDate, year)
-	calendarKathismas := services.CreateCalendarForGroup(startOffset, year)
+	calendarData := g.CalculateForGroup(year, startOffset, readersCount, yearMode, lentRule, noReadingPeriods)
+	calendar := domain.CalendarOfReader{
+		Year:        year,
+		YearMode:    yearMode,
+		StartOffset: startOffset,
+		Calendar:    calendarData,
+	}
 
-	calendarData := convertToCalendarMap(calendarKathismas)
+	result, err := g.RenderForGroup([]domain.CalendarOfReader{calendar})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, calendarData, nil
+}
+
+// CalculateForGroup distributes the kathismas of the year over the readers without building a workbook
+func (g *CalendarGeneratorImpl) CalculateForGroup(
+	year, startOffset, readersCount int,
+	yearMode domain.CalendarYearMode,
+	lentRule domain.LentReadingRule,
+	noReadingPeriods []domain.NoReadingPeriod,
+) domain.CalendarMap {
+	calendarKathismas := services.CreateCalendarForGroup(
+		startOffset, year, readersCount, yearMode, lentRule, noReadingPeriods,
+	)
+	return convertToCalendarMap(calendarKathismas)
+}
 
+// RenderForGroup builds one workbook with a sheet per reader for each of the calendars.
+// With several calendars the sheet names are prefixed with the year.
+func (g *CalendarGeneratorImpl) RenderForGroup(calendars []domain.CalendarOfReader) (*bytes.Buffer, error) {
 	xls := excelize.NewFile()
 	defer func() {
 		if err := xls.Close(); err != nil {
@@ -274,45 +323,55 @@ func (g *CalendarGeneratorImpl) GenerateForGroup(
 		}
 	}()
 
-	for pair := calendarKathismas.Oldest(); pair != nil; pair = pair.Next() {
-		sheetName := fmt.Sprintf("Чтец %d", pair.Key)
+	for _, calendar := range calendars {
+		startDate := calendar.StartDate()
+		calendarTable := services.GetCalendarMonths(startDate, calendar.YearMode.NumberDays(calendar.Year))
 
-		if _, err := xls.NewSheet(sheetName); err != nil {
-			return nil, nil, fmt.Errorf("failed create sheet %v", err)
-		}
-		err1 := addKathismaNumbersToXLS(xls, pair.Key, sheetName)
-		if err1 != nil {
-			return nil, nil, fmt.Errorf("failed add kafismas number %v", err1)
-		}
-		err2 := addHeaderOfMonthToWs(xls, sheetName)
-		if err2 != nil {
-			return nil, nil, fmt.Errorf("failed create header of months %v", err2)
-		}
-		err3 := addColumnWithNumberDayToWs(xls, sheetName)
-		if err3 != nil {
-			return nil, nil, fmt.Errorf("failed add column with number day %v", err3)
-		}
-		err4 := CreateCalendarForReaderToXLS(xls, calendarTable, pair.Value, year, sheetName)
-		if err4 != nil {
-			return nil, nil, fmt.Errorf("failed create calendar %v", err4)
+		for readerNumber := 1; readerNumber <= len(calendar.Calendar); readerNumber++ {
+			sheetName := fmt.Sprintf("Чтец %d", readerNumber)
+			if len(calendars) > 1 {
+				// sheet names may not contain a slash
+				yearLabel := strings.ReplaceAll(calendar.YearMode.Label(calendar.Year), "/", "-")
+				sheetName = fmt.Sprintf("%s Чтец %d", yearLabel, readerNumber)
+			}
+
+			if _, err := xls.NewSheet(sheetName); err != nil {
+				return nil, fmt.Errorf("failed create sheet %v", err)
+			}
+			err1 := addKathismaNumbersToXLS(xls, readerNumber, sheetName)
+			if err1 != nil {
+				return nil, fmt.Errorf("failed add kafismas number %v", err1)
+			}
+			err2 := addHeaderOfMonthToWs(xls, sheetName, startDate.Month())
+			if err2 != nil {
+				return nil, fmt.Errorf("failed create header of months %v", err2)
+			}
+			err3 := addColumnWithNumberDayToWs(xls, sheetName)
+			if err3 != nil {
+				return nil, fmt.Errorf("failed add column with number day %v", err3)
+			}
+			err4 := CreateCalendarForReaderToXLS(xls, calendarTable, calendar.Calendar[readerNumber], startDate, sheetName)
+			if err4 != nil {
+				return nil, fmt.Errorf("failed create calendar %v", err4)
+			}
 		}
 	}
 
 	p := getPathForFile()
 	err := xls.SaveAs(p.outFile)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed save %v", err)
+		return nil, fmt.Errorf("failed save %v", err)
 	}
 
 	result, err := xls.WriteToBuffer()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed write to buffer %v", err)
+		return nil, fmt.Errorf("failed write to buffer %v", err)
 	}
 
-	return result, calendarData, nil
+	return result, nil
 }
 
-func convertToCalendarMap(orderedMap *orderedmap.OrderedMap[int, map[int]int]) domain.CalendarMap {
+func convertToCalendarMap(orderedMap *orderedmap.OrderedMap[int, map[int][]int]) domain.CalendarMap {
 	calendarMap := make(domain.CalendarMap)
 	for pair := orderedMap.Oldest(); pair != nil; pair = pair.Next() {
 		calendarMap[pair.Key] = pair.Value
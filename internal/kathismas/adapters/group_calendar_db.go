@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/gofrs/uuid/v5"
+	bolt "go.etcd.io/bbolt"
+)
+
+// groupCalendarBucket is the bucket storm keeps GroupCalendarDB records in
+const groupCalendarBucket = "GroupCalendarDB"
+
+// GroupCalendarDB is a calendar of a group stored apart from the group record, so that groups
+// are read and written without years of calendars. Key is "<group id>/<year>/<year mode>".
+type GroupCalendarDB struct {
+	Key     string `storm:"id" json:"key"`
+	GroupID string `json:"group_id"`
+	CalendarRefDB
+}
+
+func groupCalendarKey(groupID string, year int, yearMode string) string {
+	return fmt.Sprintf("%s/%04d/%s", groupID, year, yearMode)
+}
+
+func groupCalendarsPrefix(groupID string) string {
+	return groupID + "/"
+}
+
+func marshalCalendarRef(calendar domain.CalendarOfReader) CalendarRefDB {
+	return CalendarRefDB{
+		ID:          calendar.ID.String(),
+		Year:        calendar.Year,
+		YearMode:    string(calendar.YearMode),
+		StartOffset: calendar.StartOffset,
+		Calendar:    marshalCalendarMap(calendar.Calendar),
+		CreatedAt:   calendar.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   calendar.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func unmarshalCalendarRef(dbCalendar CalendarRefDB) (*domain.CalendarOfReader, error) {
+	calendarID, err := uuid.FromString(dbCalendar.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar ID: %w", err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, dbCalendar.CreatedAt)
+	if err != nil {
+		slog.Warn("failed to parse calendar created_at", "error", err)
+		createdAt = time.Now()
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339, dbCalendar.UpdatedAt)
+	if err != nil {
+		slog.Warn("failed to parse calendar updated_at", "error", err)
+		updatedAt = time.Now()
+	}
+
+	return domain.UnmarshallCalendarOfReader(
+		calendarID,
+		dbCalendar.Year,
+		unmarshalYearMode(dbCalendar.YearMode),
+		dbCalendar.StartOffset,
+		unmarshalCalendarMap(dbCalendar.Calendar),
+		createdAt,
+		updatedAt,
+	), nil
+}
+
+// saveGroupCalendars writes the calendars over the stored ones of the same year and year mode
+func saveGroupCalendars(node storm.Node, groupID string, calendars []domain.CalendarOfReader) error {
+	for _, calendar := range calendars {
+		dbCalendar := GroupCalendarDB{
+			Key:           groupCalendarKey(groupID, calendar.Year, string(calendar.YearMode)),
+			GroupID:       groupID,
+			CalendarRefDB: marshalCalendarRef(calendar),
+		}
+		if err := node.Save(&dbCalendar); err != nil {
+			return fmt.Errorf("error saving calendar %d of reader group: %w", calendar.Year, err)
+		}
+	}
+	return nil
+}
+
+// loadGroupCalendars returns the calendars of the group ordered by year
+func loadGroupCalendars(node storm.Node, groupID string) ([]domain.CalendarOfReader, error) {
+	var dbCalendars []GroupCalendarDB
+	err := node.Prefix("Key", groupCalendarsPrefix(groupID), &dbCalendars)
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return nil, fmt.Errorf("error getting calendars of reader group: %w", err)
+	}
+
+	calendars := make([]domain.CalendarOfReader, 0, len(dbCalendars))
+	for _, dbCalendar := range dbCalendars {
+		calendar, err := unmarshalCalendarRef(dbCalendar.CalendarRefDB)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, *calendar)
+	}
+	return calendars, nil
+}
+
+func deleteGroupCalendars(node storm.Node, groupID string) error {
+	var dbCalendars []GroupCalendarDB
+	err := node.Prefix("Key", groupCalendarsPrefix(groupID), &dbCalendars)
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return fmt.Errorf("error getting calendars of reader group: %w", err)
+	}
+
+	for i := range dbCalendars {
+		if err := node.DeleteStruct(&dbCalendars[i]); err != nil {
+			return fmt.Errorf("error deleting calendar of reader group: %w", err)
+		}
+	}
+	return nil
+}
+
+// countGroupCalendars walks the keys only, without decoding the calendars
+func countGroupCalendars(db *storm.DB, groupID string) (int, error) {
+	prefix := []byte(groupCalendarsPrefix(groupID))
+	count := 0
+	err := db.Bolt.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(groupCalendarBucket))
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			// nested buckets hold storm indexes
+			if value != nil {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error counting calendars of reader group: %w", err)
+	}
+	return count, nil
+}
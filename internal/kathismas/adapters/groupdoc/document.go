@@ -0,0 +1,260 @@
+// Package groupdoc keeps one reader group, with its readers, no reading periods and calendars,
+// as a portable JSON document that can be moved between instances and storage backends.
+package groupdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// Format names the documents of this package, a JSON file of anything else is refused on import
+const Format = "for-twenty-readers/reader-group"
+
+// Version is the version of the document written by Encode.
+// A change of the document shape bumps it, Decode keeps reading every older version.
+const Version = 1
+
+// header is read first to learn how to read the rest of the document
+type header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+type documentV1 struct {
+	header
+	ExportedAt time.Time `json:"exported_at"`
+	Group      groupV1   `json:"group"`
+}
+
+type groupV1 struct {
+	ID               uuid.UUID           `json:"id"`
+	Name             string              `json:"name"`
+	Size             int                 `json:"size"`
+	StartOffset      int                 `json:"start_offset"`
+	YearMode         string              `json:"year_mode"`
+	LentRule         string              `json:"lent_rule"`
+	Readers          []readerV1          `json:"readers"`
+	NoReadingPeriods []noReadingPeriodV1 `json:"no_reading_periods"`
+	Calendars        []calendarV1        `json:"calendars"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+type readerV1 struct {
+	ID           uuid.UUID `json:"id"`
+	ReaderNumber int8      `json:"reader_number"`
+	Username     string    `json:"username"`
+	TelegramID   int64     `json:"telegram_id,omitempty"`
+	Phone        string    `json:"phone,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// noReadingPeriodV1 has the Pascha offsets or the fixed dates, depending on the anchor
+type noReadingPeriodV1 struct {
+	ID         uuid.UUID   `json:"id"`
+	Name       string      `json:"name"`
+	Anchor     string      `json:"anchor"`
+	PaschaFrom int         `json:"pascha_from,omitempty"`
+	PaschaTo   int         `json:"pascha_to,omitempty"`
+	FixedFrom  *monthDayV1 `json:"fixed_from,omitempty"`
+	FixedTo    *monthDayV1 `json:"fixed_to,omitempty"`
+}
+
+type monthDayV1 struct {
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// calendarV1 keeps the readings as reader number to day of the year to kathismas
+type calendarV1 struct {
+	ID          uuid.UUID             `json:"id"`
+	Year        int                   `json:"year"`
+	YearMode    string                `json:"year_mode"`
+	StartOffset int                   `json:"start_offset"`
+	Readings    map[int]map[int][]int `json:"readings"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// Document encodes and decodes reader groups, it is stateless
+type Document struct{}
+
+func NewDocument() Document {
+	return Document{}
+}
+
+// Encode writes the group with the calendars it holds as a document of the current version
+func (Document) Encode(group *domain.ReaderGroup) ([]byte, error) {
+	doc := documentV1{
+		header:     header{Format: Format, Version: Version},
+		ExportedAt: time.Now().UTC(),
+		Group: groupV1{
+			ID:               group.ID,
+			Name:             group.Name,
+			Size:             group.Size,
+			StartOffset:      group.StartOffset,
+			YearMode:         string(group.YearMode),
+			LentRule:         string(group.LentRule),
+			Readers:          make([]readerV1, 0, len(group.Readers)),
+			NoReadingPeriods: make([]noReadingPeriodV1, 0, len(group.NoReadingPeriods)),
+			Calendars:        make([]calendarV1, 0, len(group.Calendars)),
+			CreatedAt:        group.CreatedAt,
+			UpdatedAt:        group.UpdatedAt,
+		},
+	}
+
+	for _, reader := range group.Readers {
+		doc.Group.Readers = append(doc.Group.Readers, readerV1{
+			ID:           reader.ID,
+			ReaderNumber: reader.ReaderNumber,
+			Username:     reader.Username,
+			TelegramID:   reader.TelegramID,
+			Phone:        reader.Phone,
+			CreatedAt:    reader.CreatedAt,
+			UpdatedAt:    reader.UpdatedAt,
+		})
+	}
+
+	for _, period := range group.NoReadingPeriods {
+		periodDoc := noReadingPeriodV1{
+			ID:     period.ID,
+			Name:   period.Name,
+			Anchor: string(period.Anchor),
+		}
+		if period.Anchor == domain.NoReadingAnchorFixed {
+			periodDoc.FixedFrom = &monthDayV1{Month: int(period.FixedFrom.Month), Day: period.FixedFrom.Day}
+			periodDoc.FixedTo = &monthDayV1{Month: int(period.FixedTo.Month), Day: period.FixedTo.Day}
+		} else {
+			periodDoc.PaschaFrom, periodDoc.PaschaTo = period.PaschaFrom, period.PaschaTo
+		}
+		doc.Group.NoReadingPeriods = append(doc.Group.NoReadingPeriods, periodDoc)
+	}
+
+	for _, calendar := range group.Calendars {
+		doc.Group.Calendars = append(doc.Group.Calendars, calendarV1{
+			ID:          calendar.ID,
+			Year:        calendar.Year,
+			YearMode:    string(calendar.YearMode),
+			StartOffset: calendar.StartOffset,
+			Readings:    calendar.Calendar,
+			CreatedAt:   calendar.CreatedAt,
+			UpdatedAt:   calendar.UpdatedAt,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling reader group document: %w", err)
+	}
+	return data, nil
+}
+
+// Decode reads a document of any supported version. The group is only checked to be readable,
+// whether its values are valid is up to the domain.
+func (Document) Decode(data []byte) (*domain.ReaderGroup, error) {
+	var h header
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("error reading reader group document: %w", err)
+	}
+	if h.Format != Format {
+		return nil, fmt.Errorf("not a reader group document: format is %q, expected %q", h.Format, Format)
+	}
+
+	switch {
+	case h.Version == 1:
+		return decodeV1(data)
+	case h.Version > Version:
+		return nil, fmt.Errorf("reader group document version %d is newer than the supported %d", h.Version, Version)
+	default:
+		return nil, fmt.Errorf("unknown reader group document version %d", h.Version)
+	}
+}
+
+func decodeV1(data []byte) (*domain.ReaderGroup, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// a misspelled field would otherwise be dropped without a word
+	decoder.DisallowUnknownFields()
+	var doc documentV1
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error reading reader group document: %w", err)
+	}
+	if doc.Group.ID == uuid.Nil {
+		return nil, fmt.Errorf("reader group document has no group id")
+	}
+
+	readers := make([]domain.PsalmReader, 0, len(doc.Group.Readers))
+	for _, reader := range doc.Group.Readers {
+		if reader.ID == uuid.Nil {
+			return nil, fmt.Errorf("reader %d has no id", reader.ReaderNumber)
+		}
+		readers = append(readers, *domain.UnmarshallPsalmReader(
+			reader.ID,
+			reader.ReaderNumber,
+			reader.Username,
+			reader.TelegramID,
+			reader.Phone,
+			reader.CreatedAt,
+			reader.UpdatedAt,
+		))
+	}
+
+	periods := make([]domain.NoReadingPeriod, 0, len(doc.Group.NoReadingPeriods))
+	for _, period := range doc.Group.NoReadingPeriods {
+		if period.ID == uuid.Nil {
+			return nil, fmt.Errorf("no reading period %q has no id", period.Name)
+		}
+		var fixedFrom, fixedTo domain.MonthDay
+		if period.FixedFrom != nil {
+			fixedFrom = domain.MonthDay{Month: time.Month(period.FixedFrom.Month), Day: period.FixedFrom.Day}
+		}
+		if period.FixedTo != nil {
+			fixedTo = domain.MonthDay{Month: time.Month(period.FixedTo.Month), Day: period.FixedTo.Day}
+		}
+		periods = append(periods, *domain.UnmarshallNoReadingPeriod(
+			period.ID,
+			period.Name,
+			domain.NoReadingAnchor(period.Anchor),
+			period.PaschaFrom,
+			period.PaschaTo,
+			fixedFrom,
+			fixedTo,
+		))
+	}
+
+	calendars := make([]domain.CalendarOfReader, 0, len(doc.Group.Calendars))
+	for _, calendar := range doc.Group.Calendars {
+		if calendar.ID == uuid.Nil {
+			return nil, fmt.Errorf("calendar for year %d has no id", calendar.Year)
+		}
+		calendars = append(calendars, *domain.UnmarshallCalendarOfReader(
+			calendar.ID,
+			calendar.Year,
+			domain.CalendarYearMode(calendar.YearMode),
+			calendar.StartOffset,
+			calendar.Readings,
+			calendar.CreatedAt,
+			calendar.UpdatedAt,
+		))
+	}
+
+	return domain.UnmarshallReaderGroup(
+		doc.Group.ID,
+		doc.Group.Name,
+		readers,
+		doc.Group.Size,
+		doc.Group.StartOffset,
+		domain.CalendarYearMode(doc.Group.YearMode),
+		domain.LentReadingRule(doc.Group.LentRule),
+		periods,
+		calendars,
+		0,
+		doc.Group.CreatedAt,
+		doc.Group.UpdatedAt,
+	), nil
+}
@@ -0,0 +1,100 @@
+package groupdoc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGroup(t *testing.T) *domain.ReaderGroup {
+	t.Helper()
+	group, err := domain.NewReaderGroup("Храм Покрова", 3)
+	require.NoError(t, err)
+	require.NoError(t, group.UpdateSize(2))
+	require.NoError(t, group.UpdateYearMode(domain.CalendarYearChurch))
+
+	reader, err := domain.NewPsalmReader("Иван", 1001, "+79990000000", 2)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(reader))
+
+	christmas, err := domain.NewFixedNoReadingPeriod("Рождество",
+		domain.MonthDay{Month: time.January, Day: 7}, domain.MonthDay{Month: time.January, Day: 8})
+	require.NoError(t, err)
+	require.NoError(t, group.AddNoReadingPeriod(*christmas))
+
+	require.NoError(t, group.AddCalendar(*domain.NewCalendarOfReader(2026, domain.CalendarYearChurch, 3,
+		domain.CalendarMap{1: {1: {3, 4}}, 2: {1: {5}}})))
+	return group
+}
+
+func TestDocument_RoundTrip(t *testing.T) {
+	group := newGroup(t)
+	document := NewDocument()
+
+	data, err := document.Encode(group)
+	require.NoError(t, err)
+
+	decoded, err := document.Decode(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, group.ID, decoded.ID)
+	assert.Equal(t, group.Name, decoded.Name)
+	assert.Equal(t, group.Size, decoded.Size)
+	assert.Equal(t, group.StartOffset, decoded.StartOffset)
+	assert.Equal(t, group.YearMode, decoded.YearMode)
+	assert.Equal(t, group.LentRule, decoded.LentRule)
+	assert.True(t, group.CreatedAt.Equal(decoded.CreatedAt))
+
+	require.Len(t, decoded.Readers, 1)
+	assert.Equal(t, group.Readers[0].ID, decoded.Readers[0].ID)
+	assert.Equal(t, group.Readers[0].ReaderNumber, decoded.Readers[0].ReaderNumber)
+	assert.Equal(t, group.Readers[0].Username, decoded.Readers[0].Username)
+	assert.Equal(t, group.Readers[0].TelegramID, decoded.Readers[0].TelegramID)
+	assert.Equal(t, group.Readers[0].Phone, decoded.Readers[0].Phone)
+
+	assert.Equal(t, group.NoReadingPeriods, decoded.NoReadingPeriods)
+
+	require.Len(t, decoded.Calendars, 1)
+	assert.Equal(t, group.Calendars[0].ID, decoded.Calendars[0].ID)
+	assert.Equal(t, group.Calendars[0].Year, decoded.Calendars[0].Year)
+	assert.Equal(t, group.Calendars[0].YearMode, decoded.Calendars[0].YearMode)
+	assert.Equal(t, group.Calendars[0].StartOffset, decoded.Calendars[0].StartOffset)
+	assert.Equal(t, group.Calendars[0].Calendar, decoded.Calendars[0].Calendar)
+}
+
+func TestDocument_Decode_Errors(t *testing.T) {
+	valid, err := NewDocument().Encode(newGroup(t))
+	require.NoError(t, err)
+
+	withField := func(key string, value any) []byte {
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(valid, &doc))
+		doc[key] = value
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		return data
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		errContains string
+	}{
+		{name: "not json", data: []byte("not json"), errContains: "error reading reader group document"},
+		{name: "other format", data: withField("format", "other"), errContains: "not a reader group document"},
+		{name: "newer version", data: withField("version", Version+1), errContains: "is newer than the supported"},
+		{name: "unknown version", data: withField("version", 0), errContains: "unknown reader group document version"},
+		{name: "unknown field", data: withField("extra", true), errContains: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDocument().Decode(tt.data)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
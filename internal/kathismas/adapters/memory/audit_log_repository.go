@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+type AuditLogRepository struct {
+	mu      sync.RWMutex
+	entries []domain.AuditEntry
+}
+
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+func (r *AuditLogRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *entry
+	stored.Changes = slices.Clone(entry.Changes)
+	r.entries = append(r.entries, stored)
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := filter.Apply(r.entries)
+	for i := range entries {
+		entries[i].Changes = slices.Clone(entries[i].Changes)
+	}
+	return entries, nil
+}
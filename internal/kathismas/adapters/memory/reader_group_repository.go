@@ -0,0 +1,219 @@
+// Package memory keeps the kathismas domain in memory, for tests which need a working repository
+// without a database file.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type calendarKey struct {
+	year     int
+	yearMode domain.CalendarYearMode
+}
+
+type storedGroup struct {
+	group     domain.ReaderGroup
+	calendars map[calendarKey]domain.CalendarOfReader
+}
+
+// ReaderGroupRepository keeps copies of the groups, changes of a returned group are only stored by Update
+type ReaderGroupRepository struct {
+	mu       sync.RWMutex
+	groups   map[uuid.UUID]*storedGroup
+	telegram map[int64]uuid.UUID
+}
+
+func NewReaderGroupRepository() *ReaderGroupRepository {
+	return &ReaderGroupRepository{
+		groups:   make(map[uuid.UUID]*storedGroup),
+		telegram: make(map[int64]uuid.UUID),
+	}
+}
+
+func (r *ReaderGroupRepository) Create(ctx context.Context, group *domain.ReaderGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[group.ID]; ok {
+		return fmt.Errorf("reader group already exists: %s", group.ID)
+	}
+	if err := r.checkTelegramIDs(group); err != nil {
+		return err
+	}
+
+	stored := &storedGroup{calendars: make(map[calendarKey]domain.CalendarOfReader)}
+	group.Version = 1
+	r.save(stored, group)
+	r.groups[group.ID] = stored
+	return nil
+}
+
+func (r *ReaderGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.groups[id]
+	if !ok {
+		return nil, domain.ReaderGroupNotFoundError{GroupID: id}
+	}
+	return copyGroup(&stored.group), nil
+}
+
+// GetByIDWithCalendars returns the group along with all of its calendars
+func (r *ReaderGroupRepository) GetByIDWithCalendars(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.groups[id]
+	if !ok {
+		return nil, domain.ReaderGroupNotFoundError{GroupID: id}
+	}
+	group := copyGroup(&stored.group)
+	for _, calendar := range stored.calendars {
+		group.Calendars = append(group.Calendars, copyCalendar(calendar))
+	}
+	slices.SortFunc(group.Calendars, func(a, b domain.CalendarOfReader) int {
+		return cmp.Or(cmp.Compare(a.Year, b.Year), cmp.Compare(a.YearMode, b.YearMode))
+	})
+	return group, nil
+}
+
+func (r *ReaderGroupRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groupID, ok := r.telegram[telegramID]
+	if !ok {
+		return nil, domain.TelegramReaderNotFoundError{TelegramID: telegramID}
+	}
+	return copyGroup(&r.groups[groupID].group), nil
+}
+
+func (r *ReaderGroupRepository) CountCalendars(ctx context.Context, id uuid.UUID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.groups[id]
+	if !ok {
+		return 0, nil
+	}
+	return len(stored.calendars), nil
+}
+
+func (r *ReaderGroupRepository) GetAll(ctx context.Context) ([]domain.ReaderGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := slices.SortedFunc(maps.Keys(r.groups), func(a, b uuid.UUID) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	groups := make([]domain.ReaderGroup, 0, len(ids))
+	for _, id := range ids {
+		groups = append(groups, *copyGroup(&r.groups[id].group))
+	}
+	return groups, nil
+}
+
+// Update saves the group and the calendars it holds, calendars which were not loaded stay as they are
+func (r *ReaderGroupRepository) Update(ctx context.Context, group *domain.ReaderGroup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.groups[group.ID]
+	if !ok {
+		return domain.ReaderGroupNotFoundError{GroupID: group.ID}
+	}
+	if stored.group.Version != group.Version {
+		return domain.VersionConflictError{GroupID: group.ID, Version: group.Version, StoredVersion: stored.group.Version}
+	}
+	if err := r.checkTelegramIDs(group); err != nil {
+		return err
+	}
+
+	r.unindexReaders(stored.group.Readers)
+	group.Version++
+	r.save(stored, group)
+	return nil
+}
+
+func (r *ReaderGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.groups[id]
+	if !ok {
+		return domain.ReaderGroupNotFoundError{GroupID: id}
+	}
+	r.unindexReaders(stored.group.Readers)
+	delete(r.groups, id)
+	return nil
+}
+
+// checkTelegramIDs fails when a reader of the group has the Telegram ID of a reader of another group
+func (r *ReaderGroupRepository) checkTelegramIDs(group *domain.ReaderGroup) error {
+	for _, reader := range group.Readers {
+		if reader.TelegramID == 0 {
+			continue
+		}
+		if groupID, ok := r.telegram[reader.TelegramID]; ok && groupID != group.ID {
+			return domain.TelegramIDTakenError{TelegramID: reader.TelegramID}
+		}
+	}
+	return nil
+}
+
+func (r *ReaderGroupRepository) unindexReaders(readers []domain.PsalmReader) {
+	for _, reader := range readers {
+		delete(r.telegram, reader.TelegramID)
+	}
+}
+
+// save keeps a copy of the group and of the calendars it holds, which replace the ones of the same year and year mode
+func (r *ReaderGroupRepository) save(stored *storedGroup, group *domain.ReaderGroup) {
+	stored.group = *copyGroup(group)
+	stored.group.Calendars = nil
+	for _, calendar := range group.Calendars {
+		stored.calendars[calendarKey{year: calendar.Year, yearMode: calendar.YearMode}] = copyCalendar(calendar)
+	}
+	for _, reader := range group.Readers {
+		if reader.TelegramID != 0 {
+			r.telegram[reader.TelegramID] = group.ID
+		}
+	}
+}
+
+// copyGroup copies the group with its calendars, so that neither side sees the changes of the other
+func copyGroup(group *domain.ReaderGroup) *domain.ReaderGroup {
+	copied := *group
+	copied.Readers = slices.Clone(group.Readers)
+	if copied.Readers == nil {
+		copied.Readers = make([]domain.PsalmReader, 0)
+	}
+	copied.NoReadingPeriods = slices.Clone(group.NoReadingPeriods)
+	copied.Calendars = make([]domain.CalendarOfReader, 0, len(group.Calendars))
+	for _, calendar := range group.Calendars {
+		copied.Calendars = append(copied.Calendars, copyCalendar(calendar))
+	}
+	return &copied
+}
+
+func copyCalendar(calendar domain.CalendarOfReader) domain.CalendarOfReader {
+	calendarData := make(domain.CalendarMap, len(calendar.Calendar))
+	for readerNumber, days := range calendar.Calendar {
+		copiedDays := make(map[int][]int, len(days))
+		for day, kathismas := range days {
+			copiedDays[day] = slices.Clone(kathismas)
+		}
+		calendarData[readerNumber] = copiedDays
+	}
+	calendar.Calendar = calendarData
+	return calendar
+}
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+func TestReaderGroupRepository_Contract(t *testing.T) {
+	repotest.ReaderGroupRepository(t, func(t *testing.T) domain.RepositoryReaderGroup {
+		return NewReaderGroupRepository()
+	})
+}
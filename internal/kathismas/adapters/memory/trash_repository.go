@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type TrashRepository struct {
+	mu    sync.RWMutex
+	items map[uuid.UUID]domain.TrashItem
+}
+
+func NewTrashRepository() *TrashRepository {
+	return &TrashRepository{items: make(map[uuid.UUID]domain.TrashItem)}
+}
+
+func (r *TrashRepository) Put(ctx context.Context, item *domain.TrashItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[item.ID] = copyTrashItem(*item)
+	return nil
+}
+
+func (r *TrashRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.TrashItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, domain.TrashItemNotFoundError{ItemID: id}
+	}
+	copied := copyTrashItem(item)
+	return &copied, nil
+}
+
+func (r *TrashRepository) List(ctx context.Context) ([]domain.TrashItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]domain.TrashItem, 0, len(r.items))
+	for _, item := range r.items {
+		items = append(items, copyTrashItem(item))
+	}
+	slices.SortFunc(items, func(a, b domain.TrashItem) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(b.ID.String(), a.ID.String()))
+	})
+	return items, nil
+}
+
+func (r *TrashRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return domain.TrashItemNotFoundError{ItemID: id}
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func copyTrashItem(item domain.TrashItem) domain.TrashItem {
+	if item.Group != nil {
+		item.Group = copyGroup(item.Group)
+	}
+	if item.Reader != nil {
+		reader := *item.Reader
+		item.Reader = &reader
+	}
+	return item
+}
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+func TestTrashRepository_Contract(t *testing.T) {
+	repotest.TrashRepository(t, func(t *testing.T) domain.RepositoryTrash {
+		return NewTrashRepository()
+	})
+}
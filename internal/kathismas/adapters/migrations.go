@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/asdine/storm/v3"
+	bolt "go.etcd.io/bbolt"
+)
+
+// metaBucket keeps the schema version of the database under schemaVersionKey
+const metaBucket = "meta"
+
+var schemaVersionKey = []byte("schema_version")
+
+// migration changes the shape of the stored records inside the transaction and returns how many records
+// it changed. node is a storm node over the same transaction.
+type migration struct {
+	version int
+	name    string
+	apply   func(node storm.Node, tx *bolt.Tx) (int, error)
+}
+
+// migrations are applied in order, a new migration goes to the end with the next version
+var migrations = []migration{
+	{version: 1, name: "move calendars out of reader group records", apply: migrateEmbeddedCalendars},
+	{version: 2, name: "store calendars in the compact encoding", apply: compactCalendarMaps},
+	{version: 3, name: "index readers by telegram ID", apply: indexTelegramReaders},
+	{version: 4, name: "repair unreadable calendar timestamps", apply: repairCalendarTimestamps},
+}
+
+// MigrationResult tells what a migration changed or, in a dry run, would change
+type MigrationResult struct {
+	Version int
+	Name    string
+	Changed int
+}
+
+// Migrate applies the migrations newer than the schema version of the database and returns what they changed.
+// All of them run in one transaction, so either the database reaches the latest version or nothing changes.
+// A dry run rolls the transaction back.
+func Migrate(db *storm.DB, dryRun bool) ([]MigrationResult, error) {
+	tx, err := db.Bolt.Begin(true)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	version, err := schemaVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+	latest := migrations[len(migrations)-1].version
+	if version > latest {
+		return nil, fmt.Errorf("database schema version %d is newer than the supported %d", version, latest)
+	}
+
+	node := db.WithTransaction(tx)
+	var results []MigrationResult
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		changed, err := m.apply(node, tx)
+		if err != nil {
+			return nil, fmt.Errorf("error applying migration %d (%s): %w", m.version, m.name, err)
+		}
+		if err := setSchemaVersion(tx, m.version); err != nil {
+			return nil, err
+		}
+		results = append(results, MigrationResult{Version: m.version, Name: m.name, Changed: changed})
+	}
+
+	if dryRun || len(results) == 0 {
+		return results, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing migrations: %w", err)
+	}
+	return results, nil
+}
+
+// schemaVersion returns 0 for databases created before the version was kept
+func schemaVersion(tx *bolt.Tx) (int, error) {
+	bucket := tx.Bucket([]byte(metaBucket))
+	if bucket == nil {
+		return 0, nil
+	}
+	value := bucket.Get(schemaVersionKey)
+	if value == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", value, err)
+	}
+	return version, nil
+}
+
+func setSchemaVersion(tx *bolt.Tx, version int) error {
+	bucket, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
+	if err != nil {
+		return fmt.Errorf("error creating meta bucket: %w", err)
+	}
+	if err := bucket.Put(schemaVersionKey, []byte(strconv.Itoa(version))); err != nil {
+		return fmt.Errorf("error saving schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// runMigration applies a single migration in its own transaction
+func runMigration(db *storm.DB, apply func(node storm.Node, tx *bolt.Tx) (int, error)) (int, error) {
+	var changed int
+	err := db.Bolt.Update(func(tx *bolt.Tx) error {
+		var err error
+		changed, err = apply(db.WithTransaction(tx), tx)
+		return err
+	})
+	return changed, err
+}
+
+func storedSchemaVersion(t *testing.T, db *storm.DB) int {
+	t.Helper()
+	var version int
+	require.NoError(t, db.Bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = schemaVersion(tx)
+		return err
+	}))
+	return version
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	// a group stored before any migration: calendars inside the record and no telegram ID index
+	legacy := repo.marshalToDB(newTestGroupWithReader(t, "Legacy Group", 1001))
+	legacy.Calendars = []CalendarRefDB{marshalCalendarRef(newTestCalendar(2026))}
+	require.NoError(t, db.Save(&legacy))
+
+	results, err := Migrate(db, true)
+	require.NoError(t, err)
+	require.Len(t, results, len(migrations))
+	assert.Equal(t, MigrationResult{Version: 1, Name: migrations[0].name, Changed: 1}, results[0])
+	assert.Equal(t, 1, results[2].Changed)
+
+	// a dry run leaves the database as it was
+	assert.Zero(t, storedSchemaVersion(t, db))
+	var stored ReaderGroupDB
+	require.NoError(t, db.One("ID", legacy.ID, &stored))
+	assert.Len(t, stored.Calendars, 1)
+
+	results, err = Migrate(db, false)
+	require.NoError(t, err)
+	require.Len(t, results, len(migrations))
+	assert.Equal(t, migrations[len(migrations)-1].version, storedSchemaVersion(t, db))
+
+	group, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	migrated, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, migrated.Calendars, 1)
+
+	results, err = Migrate(db, false)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestMigrate_RefusesNewerSchema(t *testing.T) {
+	db := openTestDB(t)
+	newer := migrations[len(migrations)-1].version + 1
+	require.NoError(t, db.Bolt.Update(func(tx *bolt.Tx) error {
+		return setSchemaVersion(tx, newer)
+	}))
+
+	_, err := Migrate(db, false)
+	require.ErrorContains(t, err, strconv.Itoa(newer))
+}
+
+func TestRepairCalendarTimestamps(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.AddCalendar(newTestCalendar(2026)))
+	require.NoError(t, repo.Create(ctx, group))
+
+	var dbCalendar GroupCalendarDB
+	require.NoError(t, db.One("GroupID", group.ID.String(), &dbCalendar))
+	dbCalendar.UpdatedAt = "01.01.2026"
+	require.NoError(t, db.Save(&dbCalendar))
+
+	repaired, err := runMigration(db, repairCalendarTimestamps)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repaired)
+
+	stored, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Calendars, 1)
+	assert.WithinDuration(t, time.Now(), stored.Calendars[0].UpdatedAt, time.Minute)
+	assert.WithinDuration(t, group.Calendars[0].CreatedAt, stored.Calendars[0].CreatedAt, time.Second)
+
+	repaired, err = runMigration(db, repairCalendarTimestamps)
+	require.NoError(t, err)
+	assert.Zero(t, repaired)
+}
@@ -14,22 +14,40 @@ import (
 )
 
 type CalendarRefDB struct {
-	ID          string             `json:"id"`
-	Year        int                `json:"year"`
-	StartOffset int                `json:"start_offset"`
-	Calendar    domain.CalendarMap `json:"calendar"`
-	CreatedAt   string             `json:"created_at"`
-	UpdatedAt   string             `json:"updated_at"`
+	ID          string        `json:"id"`
+	Year        int           `json:"year"`
+	YearMode    string        `json:"year_mode"`
+	StartOffset int           `json:"start_offset"`
+	Calendar    CalendarMapDB `json:"calendar"`
+	CreatedAt   string        `json:"created_at"`
+	UpdatedAt   string        `json:"updated_at"`
 }
 
+type NoReadingPeriodDB struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Anchor     string `json:"anchor"`
+	PaschaFrom int    `json:"pascha_from"`
+	PaschaTo   int    `json:"pascha_to"`
+	FixedFrom  string `json:"fixed_from"`
+	FixedTo    string `json:"fixed_to"`
+}
+
+// ReaderGroupDB is a group record without its calendars, which are stored as GroupCalendarDB.
+// Calendars is only read by migrateEmbeddedCalendars from records written before that.
 type ReaderGroupDB struct {
-	ID          string            `storm:"id" json:"id"`
-	Name        string            `storm:"index" json:"name"`
-	Readers     []PsalmReaderTGDB `json:"readers"`
-	StartOffset int               `json:"start_offset"`
-	Calendars   []CalendarRefDB   `json:"calendars"`
-	CreatedAt   time.Time         `storm:"index" json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID               string              `storm:"id" json:"id"`
+	Name             string              `storm:"index" json:"name"`
+	Readers          []PsalmReaderTGDB   `json:"readers"`
+	Size             int                 `json:"size"`
+	StartOffset      int                 `json:"start_offset"`
+	YearMode         string              `json:"year_mode"`
+	LentRule         string              `json:"lent_rule"`
+	NoReadingPeriods []NoReadingPeriodDB `json:"no_reading_periods"`
+	Calendars        []CalendarRefDB     `json:"calendars,omitempty"`
+	Version          int                 `json:"version"`
+	CreatedAt        time.Time           `storm:"index" json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
 }
 
 type ReaderGroupRepository struct {
@@ -45,14 +63,41 @@ func NewReaderGroupRepository(db *storm.DB) *ReaderGroupRepository {
 }
 
 func (r *ReaderGroupRepository) Create(ctx context.Context, group *domain.ReaderGroup) error {
-	dbGroup := r.marshalToDB(group)
-	err := r.db.Save(&dbGroup)
+	tx, err := r.db.Begin(true)
 	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Save overwrites a record with the same ID
+	var stored ReaderGroupDB
+	err = tx.One("ID", group.ID.String(), &stored)
+	if err == nil {
+		return fmt.Errorf("reader group already exists: %s", group.ID)
+	}
+	if !errors.Is(err, storm.ErrNotFound) {
+		return fmt.Errorf("error creating reader group: %w", err)
+	}
+
+	dbGroup := r.marshalToDB(group)
+	dbGroup.Version = 1
+	if err := tx.Save(&dbGroup); err != nil {
 		if errors.Is(err, storm.ErrAlreadyExists) {
 			return fmt.Errorf("reader group already exists: %w", err)
 		}
 		return fmt.Errorf("error creating reader group: %w", err)
 	}
+	if err := saveGroupCalendars(tx, dbGroup.ID, group.Calendars); err != nil {
+		return err
+	}
+	if err := indexGroupReaders(tx, dbGroup.ID, nil, dbGroup.Readers); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error creating reader group: %w", err)
+	}
+	group.Version = dbGroup.Version
 	return nil
 }
 
@@ -61,7 +106,7 @@ func (r *ReaderGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 	err := r.db.One("ID", id.String(), &dbGroup)
 	if err != nil {
 		if errors.Is(err, storm.ErrNotFound) {
-			return nil, fmt.Errorf("reader group with ID %s not found", id)
+			return nil, domain.ReaderGroupNotFoundError{GroupID: id}
 		}
 		return nil, fmt.Errorf("error getting reader group: %w", err)
 	}
@@ -69,6 +114,42 @@ func (r *ReaderGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 	return r.unmarshalFromDB(&dbGroup)
 }
 
+// GetByIDWithCalendars returns the group along with all of its calendars
+func (r *ReaderGroupRepository) GetByIDWithCalendars(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	group, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := loadGroupCalendars(r.db, id.String())
+	if err != nil {
+		return nil, err
+	}
+	group.Calendars = calendars
+	return group, nil
+}
+
+func (r *ReaderGroupRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error) {
+	var indexed TelegramReaderDB
+	err := r.db.One("TelegramID", telegramID, &indexed)
+	if err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return nil, domain.TelegramReaderNotFoundError{TelegramID: telegramID}
+		}
+		return nil, fmt.Errorf("error getting reader by telegram ID: %w", err)
+	}
+
+	var dbGroup ReaderGroupDB
+	if err := r.db.One("ID", indexed.GroupID, &dbGroup); err != nil {
+		return nil, fmt.Errorf("error getting reader group of telegram ID %d: %w", telegramID, err)
+	}
+	return r.unmarshalFromDB(&dbGroup)
+}
+
+func (r *ReaderGroupRepository) CountCalendars(ctx context.Context, id uuid.UUID) (int, error) {
+	return countGroupCalendars(r.db, id.String())
+}
+
 func (r *ReaderGroupRepository) GetAll(ctx context.Context) ([]domain.ReaderGroup, error) {
 	var dbGroups []ReaderGroupDB
 	err := r.db.All(&dbGroups)
@@ -91,20 +172,70 @@ func (r *ReaderGroupRepository) GetAll(ctx context.Context) ([]domain.ReaderGrou
 	return groups, nil
 }
 
+// Update saves the group and the calendars it holds, calendars which were not loaded stay as they are.
+// The stored version is checked in the same transaction, bolt runs one writing transaction at a time.
 func (r *ReaderGroupRepository) Update(ctx context.Context, group *domain.ReaderGroup) error {
-	dbGroup := r.marshalToDB(group)
-	err := r.db.Update(&dbGroup)
+	tx, err := r.db.Begin(true)
 	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var stored ReaderGroupDB
+	if err := tx.One("ID", group.ID.String(), &stored); err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return domain.ReaderGroupNotFoundError{GroupID: group.ID}
+		}
+		return fmt.Errorf("error getting reader group: %w", err)
+	}
+	if stored.Version != group.Version {
+		return domain.VersionConflictError{GroupID: group.ID, Version: group.Version, StoredVersion: stored.Version}
+	}
+
+	dbGroup := r.marshalToDB(group)
+	dbGroup.Version = group.Version + 1
+	if err := tx.Update(&dbGroup); err != nil {
 		return fmt.Errorf("error updating reader group: %w", err)
 	}
+	if err := saveGroupCalendars(tx, dbGroup.ID, group.Calendars); err != nil {
+		return err
+	}
+	if err := indexGroupReaders(tx, dbGroup.ID, stored.Readers, dbGroup.Readers); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error updating reader group: %w", err)
+	}
+	group.Version = dbGroup.Version
 	return nil
 }
 
 func (r *ReaderGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	var dbGroup ReaderGroupDB
-	dbGroup.ID = id.String()
-	err := r.db.DeleteStruct(&dbGroup)
+	tx, err := r.db.Begin(true)
 	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var dbGroup ReaderGroupDB
+	if err := tx.One("ID", id.String(), &dbGroup); err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return domain.ReaderGroupNotFoundError{GroupID: id}
+		}
+		return fmt.Errorf("error getting reader group: %w", err)
+	}
+	if err := tx.DeleteStruct(&dbGroup); err != nil {
+		return fmt.Errorf("error deleting reader group: %w", err)
+	}
+	if err := deleteGroupCalendars(tx, dbGroup.ID); err != nil {
+		return err
+	}
+	if err := indexGroupReaders(tx, dbGroup.ID, dbGroup.Readers, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error deleting reader group: %w", err)
 	}
 	return nil
@@ -124,26 +255,30 @@ func (r *ReaderGroupRepository) marshalToDB(group *domain.ReaderGroup) ReaderGro
 		})
 	}
 
-	calendars := make([]CalendarRefDB, 0, len(group.Calendars))
-	for _, calendar := range group.Calendars {
-		calendars = append(calendars, CalendarRefDB{
-			ID:          calendar.ID.String(),
-			Year:        calendar.Year,
-			StartOffset: calendar.StartOffset,
-			Calendar:    calendar.Calendar,
-			CreatedAt:   calendar.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   calendar.UpdatedAt.Format(time.RFC3339),
+	periods := make([]NoReadingPeriodDB, 0, len(group.NoReadingPeriods))
+	for _, period := range group.NoReadingPeriods {
+		periods = append(periods, NoReadingPeriodDB{
+			ID:         period.ID.String(),
+			Name:       period.Name,
+			Anchor:     string(period.Anchor),
+			PaschaFrom: period.PaschaFrom,
+			PaschaTo:   period.PaschaTo,
+			FixedFrom:  marshalMonthDay(period.FixedFrom),
+			FixedTo:    marshalMonthDay(period.FixedTo),
 		})
 	}
 
 	return ReaderGroupDB{
-		ID:          group.ID.String(),
-		Name:        group.Name,
-		Readers:     readers,
-		StartOffset: group.StartOffset,
-		Calendars:   calendars,
-		CreatedAt:   group.CreatedAt,
-		UpdatedAt:   group.UpdatedAt,
+		ID:               group.ID.String(),
+		Name:             group.Name,
+		Readers:          readers,
+		Size:             group.Size,
+		StartOffset:      group.StartOffset,
+		YearMode:         string(group.YearMode),
+		LentRule:         string(group.LentRule),
+		NoReadingPeriods: periods,
+		CreatedAt:        group.CreatedAt,
+		UpdatedAt:        group.UpdatedAt,
 	}
 }
 
@@ -167,42 +302,99 @@ func (r *ReaderGroupRepository) unmarshalFromDB(dbGroup *ReaderGroupDB) (*domain
 		))
 	}
 
-	calendars := make([]domain.CalendarOfReader, 0, len(dbGroup.Calendars))
-	for _, dbCalendar := range dbGroup.Calendars {
-		calendarID, err := uuid.FromString(dbCalendar.ID)
-		if err != nil {
-			return nil, fmt.Errorf("invalid calendar ID: %w", err)
-		}
-
-		createdAt, err := time.Parse(time.RFC3339, dbCalendar.CreatedAt)
-		if err != nil {
-			slog.Warn("failed to parse calendar created_at", "error", err)
-			createdAt = time.Now()
-		}
-
-		updatedAt, err := time.Parse(time.RFC3339, dbCalendar.UpdatedAt)
-		if err != nil {
-			slog.Warn("failed to parse calendar updated_at", "error", err)
-			updatedAt = time.Now()
-		}
+	periods, err := unmarshalNoReadingPeriods(dbGroup.NoReadingPeriods)
+	if err != nil {
+		return nil, err
+	}
 
-		calendars = append(calendars, *domain.UnmarshallCalendarOfReader(
-			calendarID,
-			dbCalendar.Year,
-			dbCalendar.StartOffset,
-			dbCalendar.Calendar,
-			createdAt,
-			updatedAt,
-		))
+	// groups stored before the size became configurable always had 20 readers
+	size := dbGroup.Size
+	if size == 0 {
+		size = domain.KathismasCount
 	}
 
 	return domain.UnmarshallReaderGroup(
 		id,
 		dbGroup.Name,
 		readers,
+		size,
 		dbGroup.StartOffset,
-		calendars,
+		unmarshalYearMode(dbGroup.YearMode),
+		unmarshalLentRule(dbGroup.LentRule),
+		periods,
+		make([]domain.CalendarOfReader, 0),
+		dbGroup.Version,
 		dbGroup.CreatedAt,
 		dbGroup.UpdatedAt,
 	), nil
 }
+
+func unmarshalNoReadingPeriods(dbPeriods []NoReadingPeriodDB) ([]domain.NoReadingPeriod, error) {
+	// groups stored before the periods became configurable have none at all
+	if dbPeriods == nil {
+		return domain.DefaultNoReadingPeriods(), nil
+	}
+
+	periods := make([]domain.NoReadingPeriod, 0, len(dbPeriods))
+	for _, dbPeriod := range dbPeriods {
+		periodID, err := uuid.FromString(dbPeriod.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no reading period ID: %w", err)
+		}
+
+		fixedFrom, err := unmarshalMonthDay(dbPeriod.FixedFrom)
+		if err != nil {
+			return nil, err
+		}
+		fixedTo, err := unmarshalMonthDay(dbPeriod.FixedTo)
+		if err != nil {
+			return nil, err
+		}
+
+		periods = append(periods, *domain.UnmarshallNoReadingPeriod(
+			periodID,
+			dbPeriod.Name,
+			domain.NoReadingAnchor(dbPeriod.Anchor),
+			dbPeriod.PaschaFrom,
+			dbPeriod.PaschaTo,
+			fixedFrom,
+			fixedTo,
+		))
+	}
+	return periods, nil
+}
+
+// groups and calendars stored before the church year mode existed have no mode and run January to December
+func unmarshalYearMode(value string) domain.CalendarYearMode {
+	if value == "" {
+		return domain.CalendarYearCivil
+	}
+	return domain.CalendarYearMode(value)
+}
+
+// groups stored before the lent reading rule existed keep the ordinary load
+func unmarshalLentRule(value string) domain.LentReadingRule {
+	if value == "" {
+		return domain.LentReadingOrdinary
+	}
+	return domain.LentReadingRule(value)
+}
+
+// month-days are stored as "MM-DD", an empty string stands for an unused bound
+func marshalMonthDay(md domain.MonthDay) string {
+	if md.Month == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d-%02d", md.Month, md.Day)
+}
+
+func unmarshalMonthDay(value string) (domain.MonthDay, error) {
+	if value == "" {
+		return domain.MonthDay{}, nil
+	}
+	var month, day int
+	if _, err := fmt.Sscanf(value, "%02d-%02d", &month, &day); err != nil {
+		return domain.MonthDay{}, fmt.Errorf("invalid month-day %q: %w", value, err)
+	}
+	return domain.MonthDay{Month: time.Month(month), Day: day}, nil
+}
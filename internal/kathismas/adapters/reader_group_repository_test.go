@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(tb testing.TB) *storm.DB {
+	tb.Helper()
+	db, err := storm.Open(filepath.Join(tb.TempDir(), "test.db"), storm.Codec(json.Codec))
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newTestCalendar(year int) domain.CalendarOfReader {
+	return *domain.NewCalendarOfReader(year, domain.CalendarYearCivil, 1, domain.CalendarMap{1: {1: {1}}})
+}
+
+func TestReaderGroupRepository_Contract(t *testing.T) {
+	repotest.ReaderGroupRepository(t, func(t *testing.T) domain.RepositoryReaderGroup {
+		return NewReaderGroupRepository(openTestDB(t))
+	})
+}
+
+func TestReaderGroupRepository_CalendarsAreLoadedLazily(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReaderGroupRepository(openTestDB(t))
+
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, group))
+	require.NoError(t, group.AddCalendar(newTestCalendar(2026)))
+	require.NoError(t, group.AddCalendar(newTestCalendar(2027)))
+	require.NoError(t, repo.Update(ctx, group))
+
+	withoutCalendars, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Empty(t, withoutCalendars.Calendars)
+
+	// saving a group loaded without calendars keeps them
+	require.NoError(t, withoutCalendars.UpdateName("Renamed"))
+	require.NoError(t, repo.Update(ctx, withoutCalendars))
+
+	withCalendars, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", withCalendars.Name)
+	require.Len(t, withCalendars.Calendars, 2)
+	assert.Equal(t, 2026, withCalendars.Calendars[0].Year)
+	assert.Equal(t, domain.CalendarMap{1: {1: {1}}}, withCalendars.Calendars[1].Calendar)
+
+	count, err := repo.CountCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repo.Delete(ctx, group.ID))
+	count, err = repo.CountCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestReaderGroupRepository_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReaderGroupRepository(openTestDB(t))
+
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, group))
+	assert.Equal(t, 1, group.Version)
+
+	first, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+
+	reader, err := domain.NewPsalmReader("Иван", 0, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, first.AddReader(reader))
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, 2, first.Version)
+
+	// the second copy was loaded before the reader was added and would drop it
+	require.NoError(t, second.UpdateName("Renamed"))
+	err = repo.Update(ctx, second)
+	require.Error(t, err)
+	var conflict domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 1, conflict.Version)
+	assert.Equal(t, 2, conflict.StoredVersion)
+
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Group", stored.Name)
+	assert.Len(t, stored.Readers, 1)
+
+	// a record stored before versions is at version zero
+	var dbGroup ReaderGroupDB
+	require.NoError(t, repo.db.One("ID", group.ID.String(), &dbGroup))
+	dbGroup.Version = 0
+	require.NoError(t, repo.db.Save(&dbGroup))
+	legacy, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(ctx, legacy))
+	assert.Equal(t, 1, legacy.Version)
+}
+
+func TestMigrateEmbeddedCalendars(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	group, err := domain.NewReaderGroup("Legacy Group", 1)
+	require.NoError(t, err)
+	legacy := repo.marshalToDB(group)
+	legacy.Calendars = []CalendarRefDB{
+		marshalCalendarRef(newTestCalendar(2025)),
+		marshalCalendarRef(newTestCalendar(2026)),
+	}
+	// calendars written before the church year mode have no mode
+	legacy.Calendars[0].YearMode = ""
+	require.NoError(t, db.Save(&legacy))
+
+	moved, err := runMigration(db, migrateEmbeddedCalendars)
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	var stored ReaderGroupDB
+	require.NoError(t, db.One("ID", group.ID.String(), &stored))
+	assert.Empty(t, stored.Calendars)
+
+	migrated, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, migrated.Calendars, 2)
+	assert.Equal(t, domain.CalendarYearCivil, migrated.Calendars[0].YearMode)
+
+	moved, err = runMigration(db, migrateEmbeddedCalendars)
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+}
@@ -0,0 +1,127 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AuditLogRepository runs the contract of domain.RepositoryAuditLog,
+// newRepo returns an empty repository for every subtest
+func AuditLogRepository(t *testing.T, newRepo func(t *testing.T) domain.RepositoryAuditLog) {
+	t.Run("append and list", func(t *testing.T) { testAuditAppendAndList(t, newRepo(t)) })
+	t.Run("newest first", func(t *testing.T) { testAuditNewestFirst(t, newRepo(t)) })
+	t.Run("filter", func(t *testing.T) { testAuditFilter(t, newRepo(t)) })
+}
+
+func appendAuditEntry(
+	t *testing.T,
+	repo domain.RepositoryAuditLog,
+	groupID uuid.UUID,
+	actor string,
+	source domain.AuditSource,
+	action domain.AuditAction,
+	createdAt time.Time,
+) domain.AuditEntry {
+	t.Helper()
+	entry, err := domain.NewAuditEntry(actor, source, action, groupID, []domain.AuditChange{
+		{Field: "name", Before: "Old", After: "New"},
+		{Field: "reader 1", After: "Иван"},
+	})
+	require.NoError(t, err)
+	entry.CreatedAt = createdAt
+	require.NoError(t, repo.Append(context.Background(), entry))
+	return *entry
+}
+
+func testAuditAppendAndList(t *testing.T, repo domain.RepositoryAuditLog) {
+	entries, err := repo.List(context.Background(), domain.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	groupID := uuid.Must(uuid.NewV7())
+	entry := appendAuditEntry(t, repo, groupID, "127.0.0.1", domain.AuditSourceWeb, domain.AuditActionUpdateGroup,
+		time.Date(2026, 3, 1, 10, 0, 0, 123456789, time.UTC))
+
+	entries, err = repo.List(context.Background(), domain.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+	assert.Equal(t, groupID, entries[0].GroupID)
+	assert.Equal(t, "127.0.0.1", entries[0].Actor)
+	assert.Equal(t, domain.AuditSourceWeb, entries[0].Source)
+	assert.Equal(t, domain.AuditActionUpdateGroup, entries[0].Action)
+	assert.Equal(t, entry.Changes, entries[0].Changes)
+	assert.True(t, entry.CreatedAt.Equal(entries[0].CreatedAt))
+}
+
+func testAuditNewestFirst(t *testing.T, repo domain.RepositoryAuditLog) {
+	groupID := uuid.Must(uuid.NewV7())
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := appendAuditEntry(t, repo, groupID, "a", domain.AuditSourceWeb, domain.AuditActionAddReader,
+		start.Add(time.Minute))
+	first := appendAuditEntry(t, repo, groupID, "a", domain.AuditSourceWeb, domain.AuditActionCreateGroup, start)
+	third := appendAuditEntry(t, repo, groupID, "a", domain.AuditSourceWeb, domain.AuditActionRemoveReader,
+		start.Add(time.Hour))
+
+	entries, err := repo.List(context.Background(), domain.AuditFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{third.ID, second.ID, first.ID}, auditEntryIDs(entries))
+
+	entries, err = repo.List(context.Background(), domain.AuditFilter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{third.ID, second.ID}, auditEntryIDs(entries))
+}
+
+func testAuditFilter(t *testing.T, repo domain.RepositoryAuditLog) {
+	ctx := context.Background()
+	groupID, otherGroupID := uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7())
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	web := appendAuditEntry(t, repo, groupID, "127.0.0.1", domain.AuditSourceWeb, domain.AuditActionCreateGroup,
+		start)
+	telegram := appendAuditEntry(t, repo, groupID, "@ivan", domain.AuditSourceTelegram, domain.AuditActionAddReader,
+		start.Add(time.Hour))
+	other := appendAuditEntry(t, repo, otherGroupID, "127.0.0.1", domain.AuditSourceWeb,
+		domain.AuditActionDeleteGroup, start.Add(2*time.Hour))
+
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   []uuid.UUID
+	}{
+		{name: "group", filter: domain.AuditFilter{GroupID: groupID}, want: []uuid.UUID{telegram.ID, web.ID}},
+		{name: "actor", filter: domain.AuditFilter{Actor: "127.0.0.1"}, want: []uuid.UUID{other.ID, web.ID}},
+		{name: "source", filter: domain.AuditFilter{Source: domain.AuditSourceTelegram}, want: []uuid.UUID{telegram.ID}},
+		{name: "action", filter: domain.AuditFilter{Action: domain.AuditActionDeleteGroup}, want: []uuid.UUID{other.ID}},
+		{
+			name:   "since and exclusive until",
+			filter: domain.AuditFilter{Since: start.Add(time.Hour), Until: start.Add(2 * time.Hour)},
+			want:   []uuid.UUID{telegram.ID},
+		},
+		{
+			name:   "nothing matches",
+			filter: domain.AuditFilter{GroupID: otherGroupID, Source: domain.AuditSourceTelegram},
+			want:   []uuid.UUID{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.List(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, auditEntryIDs(entries))
+		})
+	}
+}
+
+func auditEntryIDs(entries []domain.AuditEntry) []uuid.UUID {
+	ids := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
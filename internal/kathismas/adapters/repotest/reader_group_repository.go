@@ -0,0 +1,341 @@
+// Package repotest holds the contract every storage backend of the kathismas domain has to follow.
+// A backend is checked by calling the suite from its own tests with a constructor of empty repositories.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ReaderGroupRepository runs the contract of domain.RepositoryReaderGroup,
+// newRepo returns an empty repository for every subtest
+func ReaderGroupRepository(t *testing.T, newRepo func(t *testing.T) domain.RepositoryReaderGroup) {
+	t.Run("create and get", func(t *testing.T) { testCreateAndGet(t, newRepo(t)) })
+	t.Run("create twice", func(t *testing.T) { testCreateTwice(t, newRepo(t)) })
+	t.Run("not found", func(t *testing.T) { testNotFound(t, newRepo(t)) })
+	t.Run("get all ordered by ID", func(t *testing.T) { testGetAllOrder(t, newRepo(t)) })
+	t.Run("update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("version conflict", func(t *testing.T) { testVersionConflict(t, newRepo(t)) })
+	t.Run("calendars", func(t *testing.T) { testCalendars(t, newRepo(t)) })
+	t.Run("delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("telegram ID", func(t *testing.T) { testTelegramID(t, newRepo(t)) })
+	t.Run("returned groups are copies", func(t *testing.T) { testCopies(t, newRepo(t)) })
+}
+
+func newGroup(t *testing.T, name string, telegramIDs ...int64) *domain.ReaderGroup {
+	t.Helper()
+	group, err := domain.NewReaderGroup(name, 1)
+	require.NoError(t, err)
+	for i, telegramID := range telegramIDs {
+		reader, err := domain.NewPsalmReader("Чтец", telegramID, "", int8(i+1))
+		require.NoError(t, err)
+		require.NoError(t, group.AddReader(reader))
+	}
+	return group
+}
+
+func newCalendar(year int, yearMode domain.CalendarYearMode, kathisma int) domain.CalendarOfReader {
+	return *domain.NewCalendarOfReader(year, yearMode, 1, domain.CalendarMap{
+		1: {1: {kathisma}, 2: {kathisma + 1, kathisma + 2}},
+		2: {1: {kathisma + 3}},
+	})
+}
+
+func testCreateAndGet(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001, 0)
+	require.NoError(t, group.UpdateLentRule(domain.LentReadingDoublePsalter))
+	require.NoError(t, group.UpdateYearMode(domain.CalendarYearChurch))
+	require.NoError(t, group.UpdateSize(10))
+	period, err := domain.NewPaschaNoReadingPeriod("Светлая седмица", 0, 6)
+	require.NoError(t, err)
+	require.NoError(t, group.AddNoReadingPeriod(*period))
+	require.NoError(t, repo.Create(ctx, group))
+	assert.Equal(t, 1, group.Version)
+
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, stored.ID)
+	assert.Equal(t, "Test Group", stored.Name)
+	assert.Equal(t, 10, stored.Size)
+	assert.Equal(t, 1, stored.StartOffset)
+	assert.Equal(t, domain.CalendarYearChurch, stored.YearMode)
+	assert.Equal(t, domain.LentReadingDoublePsalter, stored.LentRule)
+	assert.Equal(t, 1, stored.Version)
+	assert.WithinDuration(t, group.CreatedAt, stored.CreatedAt, time.Second)
+	assert.Equal(t, group.NoReadingPeriods, stored.NoReadingPeriods)
+
+	require.Len(t, stored.Readers, 2)
+	for i, reader := range stored.Readers {
+		assert.Equal(t, group.Readers[i].ID, reader.ID)
+		assert.Equal(t, group.Readers[i].ReaderNumber, reader.ReaderNumber)
+		assert.Equal(t, group.Readers[i].Username, reader.Username)
+		assert.Equal(t, group.Readers[i].TelegramID, reader.TelegramID)
+	}
+}
+
+func testCreateTwice(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group")
+	require.NoError(t, repo.Create(ctx, group))
+	require.Error(t, repo.Create(ctx, newGroupWithID(t, group, "Other Group")))
+
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Group", stored.Name)
+}
+
+func newGroupWithID(t *testing.T, group *domain.ReaderGroup, name string) *domain.ReaderGroup {
+	t.Helper()
+	other := newGroup(t, name)
+	other.ID = group.ID
+	return other
+}
+
+func testNotFound(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+	group := newGroup(t, "Never Stored")
+
+	var notFound domain.ReaderGroupNotFoundError
+	_, err := repo.GetByID(ctx, group.ID)
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, group.ID, notFound.GroupID)
+	_, err = repo.GetByIDWithCalendars(ctx, group.ID)
+	require.ErrorAs(t, err, &notFound)
+	require.ErrorAs(t, repo.Update(ctx, group), &notFound)
+	require.ErrorAs(t, repo.Delete(ctx, group.ID), &notFound)
+
+	var readerNotFound domain.TelegramReaderNotFoundError
+	_, err = repo.GetByTelegramID(ctx, 1001)
+	require.ErrorAs(t, err, &readerNotFound)
+	assert.Equal(t, int64(1001), readerNotFound.TelegramID)
+
+	count, err := repo.CountCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func testGetAllOrder(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	// IDs grow with time, the groups are created in the reverse order
+	groups := []*domain.ReaderGroup{newGroup(t, "First"), newGroup(t, "Second"), newGroup(t, "Third")}
+	for i := len(groups) - 1; i >= 0; i-- {
+		require.NoError(t, repo.Create(ctx, groups[i]))
+	}
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	for i, group := range all {
+		assert.Equal(t, groups[i].ID, group.ID)
+		assert.Empty(t, group.Calendars)
+	}
+}
+
+func testUpdate(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001)
+	require.NoError(t, repo.Create(ctx, group))
+
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.NoError(t, stored.UpdateName("Renamed"))
+	require.NoError(t, stored.UpdateStartOffset(5))
+	require.NoError(t, stored.RemoveReader(stored.Readers[0].ID))
+	reader, err := domain.NewPsalmReader("Петр", 1002, "+70000000000", 3)
+	require.NoError(t, err)
+	require.NoError(t, stored.AddReader(reader))
+	require.NoError(t, stored.RemoveNoReadingPeriod(stored.NoReadingPeriods[0].ID))
+	require.NoError(t, repo.Update(ctx, stored))
+	assert.Equal(t, 2, stored.Version)
+
+	updated, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name)
+	assert.Equal(t, 5, updated.StartOffset)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, stored.NoReadingPeriods, updated.NoReadingPeriods)
+	require.Len(t, updated.Readers, 1)
+	assert.Equal(t, reader.ID, updated.Readers[0].ID)
+	assert.Equal(t, "+70000000000", updated.Readers[0].Phone)
+}
+
+func testVersionConflict(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group")
+	require.NoError(t, repo.Create(ctx, group))
+
+	first, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, first.UpdateName("First"))
+	require.NoError(t, repo.Update(ctx, first))
+
+	require.NoError(t, second.UpdateName("Second"))
+	var conflict domain.VersionConflictError
+	require.ErrorAs(t, repo.Update(ctx, second), &conflict)
+	assert.Equal(t, group.ID, conflict.GroupID)
+	assert.Equal(t, 1, conflict.Version)
+	assert.Equal(t, 2, conflict.StoredVersion)
+	assert.Equal(t, 1, second.Version)
+
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "First", stored.Name)
+	assert.Equal(t, 2, stored.Version)
+}
+
+func testCalendars(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group")
+	require.NoError(t, group.AddCalendar(newCalendar(2027, domain.CalendarYearCivil, 1)))
+	require.NoError(t, repo.Create(ctx, group))
+
+	// calendars are not loaded by GetByID and stay when a group loaded without them is saved
+	stored, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Calendars)
+	require.NoError(t, stored.AddCalendar(newCalendar(2026, domain.CalendarYearCivil, 2)))
+	require.NoError(t, repo.Update(ctx, stored))
+
+	count, err := repo.CountCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	withCalendars, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, withCalendars.Calendars, 2)
+	assert.Equal(t, 2026, withCalendars.Calendars[0].Year)
+	assert.Equal(t, 2027, withCalendars.Calendars[1].Year)
+	assert.Equal(t, group.Calendars[0].ID, withCalendars.Calendars[1].ID)
+	assert.Equal(t, group.Calendars[0].Calendar, withCalendars.Calendars[1].Calendar)
+
+	// a new calendar of the same year and year mode replaces the stored one
+	replacement := newCalendar(2027, domain.CalendarYearCivil, 5)
+	withCalendars.Calendars = []domain.CalendarOfReader{replacement}
+	require.NoError(t, repo.Update(ctx, withCalendars))
+
+	reloaded, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, reloaded.Calendars, 2)
+	assert.Equal(t, replacement.ID, reloaded.Calendars[1].ID)
+	assert.Equal(t, replacement.Calendar, reloaded.Calendars[1].Calendar)
+}
+
+func testDelete(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001)
+	require.NoError(t, group.AddCalendar(newCalendar(2026, domain.CalendarYearCivil, 1)))
+	require.NoError(t, repo.Create(ctx, group))
+	other := newGroup(t, "Other Group")
+	require.NoError(t, repo.Create(ctx, other))
+
+	require.NoError(t, repo.Delete(ctx, group.ID))
+
+	var notFound domain.ReaderGroupNotFoundError
+	_, err := repo.GetByID(ctx, group.ID)
+	require.ErrorAs(t, err, &notFound)
+	count, err := repo.CountCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	var readerNotFound domain.TelegramReaderNotFoundError
+	_, err = repo.GetByTelegramID(ctx, 1001)
+	require.ErrorAs(t, err, &readerNotFound)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, other.ID, all[0].ID)
+
+	// the Telegram account is free again
+	require.NoError(t, repo.Create(ctx, newGroup(t, "New Group", 1001)))
+}
+
+func testTelegramID(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	first := newGroup(t, "First Group", 1001, 0)
+	require.NoError(t, repo.Create(ctx, first))
+
+	found, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+
+	// a failed Create stores nothing
+	var taken domain.TelegramIDTakenError
+	rejected := newGroup(t, "Rejected Group", 1002, 1001)
+	require.ErrorAs(t, repo.Create(ctx, rejected), &taken)
+	assert.Equal(t, int64(1001), taken.TelegramID)
+	var notFound domain.ReaderGroupNotFoundError
+	_, err = repo.GetByID(ctx, rejected.ID)
+	require.ErrorAs(t, err, &notFound)
+	_, err = repo.GetByTelegramID(ctx, 1002)
+	require.Error(t, err)
+
+	// readers without a Telegram account do not collide
+	second := newGroup(t, "Second Group", 0)
+	require.NoError(t, repo.Create(ctx, second))
+
+	// a failed Update stores nothing either
+	reader, err := domain.NewPsalmReader("Петр", 1001, "", 2)
+	require.NoError(t, err)
+	require.NoError(t, second.AddReader(reader))
+	require.ErrorAs(t, repo.Update(ctx, second), &taken)
+	stored, err := repo.GetByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Readers, 1)
+	assert.Equal(t, 1, stored.Version)
+
+	// once the reader leaves the first group the account can join another one
+	require.NoError(t, first.RemoveReader(first.Readers[0].ID))
+	require.NoError(t, repo.Update(ctx, first))
+	require.NoError(t, repo.Update(ctx, second))
+
+	found, err = repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+}
+
+func testCopies(t *testing.T, repo domain.RepositoryReaderGroup) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001)
+	require.NoError(t, group.AddCalendar(newCalendar(2026, domain.CalendarYearCivil, 1)))
+	require.NoError(t, repo.Create(ctx, group))
+
+	// changes of the created group and of the loaded ones are only stored by Update
+	group.Name = "Changed"
+	group.Readers[0].Username = "Changed"
+	group.Calendars[0].Calendar[1][1][0] = 20
+
+	stored, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Group", stored.Name)
+	assert.Equal(t, "Чтец", stored.Readers[0].Username)
+	assert.Equal(t, []int{1}, stored.Calendars[0].Calendar[1][1])
+
+	stored.Readers[0].Username = "Changed"
+	stored.Calendars[0].Calendar[1][1][0] = 20
+	reloaded, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Чтец", reloaded.Readers[0].Username)
+	assert.Equal(t, []int{1}, reloaded.Calendars[0].Calendar[1][1])
+}
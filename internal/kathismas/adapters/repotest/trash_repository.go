@@ -0,0 +1,116 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TrashRepository runs the contract of domain.RepositoryTrash,
+// newRepo returns an empty repository for every subtest
+func TrashRepository(t *testing.T, newRepo func(t *testing.T) domain.RepositoryTrash) {
+	t.Run("group keeps calendars", func(t *testing.T) { testTrashGroup(t, newRepo(t)) })
+	t.Run("reader", func(t *testing.T) { testTrashReader(t, newRepo(t)) })
+	t.Run("not found", func(t *testing.T) { testTrashNotFound(t, newRepo(t)) })
+	t.Run("list newest first", func(t *testing.T) { testTrashList(t, newRepo(t)) })
+}
+
+func testTrashGroup(t *testing.T, repo domain.RepositoryTrash) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001, 0)
+	group.Version = 3
+	require.NoError(t, group.UpdateYearMode(domain.CalendarYearChurch))
+	require.NoError(t, group.AddCalendar(newCalendar(2026, domain.CalendarYearChurch, 1)))
+	require.NoError(t, group.AddCalendar(newCalendar(2027, domain.CalendarYearChurch, 5)))
+	item, err := domain.NewGroupTrashItem(group, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.Put(ctx, item))
+
+	stored, err := repo.GetByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TrashKindGroup, stored.Kind)
+	assert.Equal(t, group.ID, stored.GroupID)
+	assert.Equal(t, "Test Group", stored.GroupName)
+	assert.Nil(t, stored.Reader)
+	assert.WithinDuration(t, item.ExpiresAt, stored.ExpiresAt, time.Millisecond)
+
+	require.NotNil(t, stored.Group)
+	assert.Equal(t, group.ID, stored.Group.ID)
+	assert.Equal(t, domain.CalendarYearChurch, stored.Group.YearMode)
+	require.Len(t, stored.Group.Readers, 2)
+	assert.Equal(t, group.Readers[0].ID, stored.Group.Readers[0].ID)
+	assert.Equal(t, int64(1001), stored.Group.Readers[0].TelegramID)
+	require.Len(t, stored.Group.Calendars, 2)
+	for i, calendar := range stored.Group.Calendars {
+		assert.Equal(t, group.Calendars[i].ID, calendar.ID)
+		assert.Equal(t, group.Calendars[i].Year, calendar.Year)
+		assert.Equal(t, group.Calendars[i].Calendar, calendar.Calendar)
+	}
+}
+
+func testTrashReader(t *testing.T, repo domain.RepositoryTrash) {
+	ctx := context.Background()
+
+	group := newGroup(t, "Test Group", 1001)
+	item, err := domain.NewReaderTrashItem(group, group.Readers[0], time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.Put(ctx, item))
+
+	stored, err := repo.GetByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TrashKindReader, stored.Kind)
+	assert.Equal(t, group.ID, stored.GroupID)
+	assert.Nil(t, stored.Group)
+	require.NotNil(t, stored.Reader)
+	assert.Equal(t, group.Readers[0].ID, stored.Reader.ID)
+	assert.Equal(t, group.Readers[0].ReaderNumber, stored.Reader.ReaderNumber)
+	assert.Equal(t, group.Readers[0].Username, stored.Reader.Username)
+	assert.Equal(t, int64(1001), stored.Reader.TelegramID)
+}
+
+func testTrashNotFound(t *testing.T, repo domain.RepositoryTrash) {
+	ctx := context.Background()
+	missing := uuid.Must(uuid.NewV7())
+
+	_, err := repo.GetByID(ctx, missing)
+	require.ErrorAs(t, err, &domain.TrashItemNotFoundError{})
+	require.ErrorAs(t, repo.Delete(ctx, missing), &domain.TrashItemNotFoundError{})
+
+	group := newGroup(t, "Test Group")
+	item, err := domain.NewGroupTrashItem(group, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.Put(ctx, item))
+	require.NoError(t, repo.Delete(ctx, item.ID))
+	_, err = repo.GetByID(ctx, item.ID)
+	require.ErrorAs(t, err, &domain.TrashItemNotFoundError{})
+}
+
+func testTrashList(t *testing.T, repo domain.RepositoryTrash) {
+	ctx := context.Background()
+
+	items, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	var ids []uuid.UUID
+	for i, name := range []string{"First", "Second", "Third"} {
+		item, err := domain.NewGroupTrashItem(newGroup(t, name), time.Hour)
+		require.NoError(t, err)
+		item.DeletedAt = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Put(ctx, item))
+		ids = append(ids, item.ID)
+	}
+
+	items, err = repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, []uuid.UUID{ids[2], ids[1], ids[0]}, []uuid.UUID{items[0].ID, items[1].ID, items[2].ID})
+	assert.Equal(t, "Third", items[0].GroupName)
+}
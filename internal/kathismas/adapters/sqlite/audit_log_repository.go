@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type AuditLogRepository struct {
+	db *sql.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
+	if db == nil {
+		slog.Error("missing db in NewAuditLogRepository")
+		os.Exit(1)
+	}
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_entries (id, group_id, actor, source, action, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.ID.String(), entry.GroupID.String(), entry.Actor, string(entry.Source), string(entry.Action),
+		entry.CreatedAt.UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving audit entry: %w", err)
+	}
+	for i, change := range entry.Changes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO audit_changes (entry_id, position, field, before, after) VALUES (?, ?, ?, ?, ?)`,
+			entry.ID.String(), i, change.Field, change.Before, change.After,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving audit change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error saving audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var conditions []string
+	var args []any
+	if !filter.GroupID.IsNil() {
+		conditions = append(conditions, "group_id = ?")
+		args = append(args, filter.GroupID.String())
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Source != "" {
+		conditions = append(conditions, "source = ?")
+		args = append(args, string(filter.Source))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, string(filter.Action))
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UnixNano())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until.UnixNano())
+	}
+
+	query := "SELECT id, group_id, actor, source, action, created_at FROM audit_entries"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var id, groupID, source, action string
+		var createdAt int64
+		var entry domain.AuditEntry
+		if err := rows.Scan(&id, &groupID, &entry.Actor, &source, &action, &createdAt); err != nil {
+			return nil, fmt.Errorf("error reading audit entry: %w", err)
+		}
+		entry.ID = uuid.FromStringOrNil(id)
+		entry.GroupID = uuid.FromStringOrNil(groupID)
+		entry.Source = domain.AuditSource(source)
+		entry.Action = domain.AuditAction(action)
+		entry.CreatedAt = time.Unix(0, createdAt)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+
+	// the changes are read once the entries are, the single connection is busy until then
+	for i := range entries {
+		changes, err := r.changes(ctx, entries[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Changes = changes
+	}
+	return entries, nil
+}
+
+func (r *AuditLogRepository) changes(ctx context.Context, entryID uuid.UUID) ([]domain.AuditChange, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT field, before, after FROM audit_changes WHERE entry_id = ? ORDER BY position", entryID.String())
+	if err != nil {
+		return nil, fmt.Errorf("error reading audit changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []domain.AuditChange
+	for rows.Next() {
+		var change domain.AuditChange
+		if err := rows.Scan(&change.Field, &change.Before, &change.After); err != nil {
+			return nil, fmt.Errorf("error reading audit change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading audit changes: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRepository_Contract(t *testing.T) {
+	repotest.AuditLogRepository(t, func(t *testing.T) domain.RepositoryAuditLog {
+		return NewAuditLogRepository(openTestDB(t))
+	})
+}
+
+func TestOpen_UpgradesSchema(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.sqlite")
+
+	// a database created before the audit log
+	old, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = old.ExecContext(ctx, initialSchema+"PRAGMA user_version = 1;")
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	var version int
+	require.NoError(t, db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version))
+	assert.Equal(t, len(schemas), version)
+
+	entries, err := NewAuditLogRepository(db).List(ctx, domain.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
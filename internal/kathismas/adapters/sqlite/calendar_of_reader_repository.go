@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// CalendarOfReaderRepository keeps the calendars which belong to no group
+type CalendarOfReaderRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarOfReaderRepository(db *sql.DB) *CalendarOfReaderRepository {
+	if db == nil {
+		slog.Error("missing db in NewCalendarOfReaderRepository")
+		os.Exit(1)
+	}
+	return &CalendarOfReaderRepository{db: db}
+}
+
+func (cr CalendarOfReaderRepository) GetCalendar(id uuid.UUID) (*domain.CalendarOfReader, error) {
+	return getCalendar(context.Background(), cr.db, id)
+}
+
+func (cr CalendarOfReaderRepository) CreateCalendarOfReader(calendarOfReader *domain.CalendarOfReader) error {
+	ctx := context.Background()
+	tx, err := cr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := saveCalendar(ctx, tx, sql.NullString{}, *calendarOfReader); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed created calendar of reader: %w", err)
+	}
+	return nil
+}
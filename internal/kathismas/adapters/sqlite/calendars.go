@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// saveCalendar writes the calendar over the stored one of the same group, year and year mode.
+// A calendar stored under the same ID is left as it is, calendars are replaced as a whole.
+func saveCalendar(ctx context.Context, q querier, groupID sql.NullString, calendar domain.CalendarOfReader) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM calendars WHERE id = ?)", calendar.ID.String()).
+		Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error getting calendar %d: %w", calendar.Year, err)
+	}
+	if exists {
+		return nil
+	}
+
+	if groupID.Valid {
+		_, err = q.ExecContext(ctx, "DELETE FROM calendars WHERE group_id = ? AND year = ? AND year_mode = ?",
+			groupID, calendar.Year, string(calendar.YearMode))
+		if err != nil {
+			return fmt.Errorf("error replacing calendar %d: %w", calendar.Year, err)
+		}
+	}
+
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO calendars (id, group_id, year, year_mode, start_offset, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		calendar.ID.String(), groupID, calendar.Year, string(calendar.YearMode), calendar.StartOffset,
+		formatTime(calendar.CreatedAt), formatTime(calendar.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving calendar %d: %w", calendar.Year, err)
+	}
+
+	insert, err := q.PrepareContext(ctx, `
+		INSERT INTO calendar_readings (calendar_id, reader_number, day, position, kathisma)
+		VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("error saving readings of calendar %d: %w", calendar.Year, err)
+	}
+	defer insert.Close()
+
+	for readerNumber, days := range calendar.Calendar {
+		for day, kathismas := range days {
+			for position, kathisma := range kathismas {
+				if _, err := insert.ExecContext(ctx, calendar.ID.String(), readerNumber, day, position, kathisma); err != nil {
+					return fmt.Errorf("error saving readings of calendar %d: %w", calendar.Year, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type calendarRow struct {
+	id          string
+	year        int
+	yearMode    string
+	startOffset int
+	createdAt   string
+	updatedAt   string
+}
+
+// loadGroupCalendars returns the calendars of the group ordered by year
+func loadGroupCalendars(ctx context.Context, q querier, groupID string) ([]domain.CalendarOfReader, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, year, year_mode, start_offset, created_at, updated_at
+		FROM calendars WHERE group_id = ? ORDER BY year, year_mode`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting calendars of reader group: %w", err)
+	}
+	calendarRows, err := scanCalendarRows(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error getting calendars of reader group: %w", err)
+	}
+
+	calendars := make([]domain.CalendarOfReader, 0, len(calendarRows))
+	for _, row := range calendarRows {
+		calendar, err := loadCalendar(ctx, q, row)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, *calendar)
+	}
+	return calendars, nil
+}
+
+// getCalendar returns the calendar stored apart from any group
+func getCalendar(ctx context.Context, q querier, id uuid.UUID) (*domain.CalendarOfReader, error) {
+	var row calendarRow
+	err := q.QueryRowContext(ctx, `
+		SELECT id, year, year_mode, start_offset, created_at, updated_at
+		FROM calendars WHERE id = ?`, id.String()).
+		Scan(&row.id, &row.year, &row.yearMode, &row.startOffset, &row.createdAt, &row.updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("calendar with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("error getting calendar: %w", err)
+	}
+	return loadCalendar(ctx, q, row)
+}
+
+func scanCalendarRows(rows *sql.Rows) ([]calendarRow, error) {
+	defer rows.Close()
+	var calendarRows []calendarRow
+	for rows.Next() {
+		var row calendarRow
+		if err := rows.Scan(&row.id, &row.year, &row.yearMode, &row.startOffset, &row.createdAt, &row.updatedAt); err != nil {
+			return nil, err
+		}
+		calendarRows = append(calendarRows, row)
+	}
+	return calendarRows, rows.Err()
+}
+
+func loadCalendar(ctx context.Context, q querier, row calendarRow) (*domain.CalendarOfReader, error) {
+	id, err := uuid.FromString(row.id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar ID: %w", err)
+	}
+	yearMode, err := domain.ParseCalendarYearMode(row.yearMode)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := parseTime(row.createdAt)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt, err := parseTime(row.updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT reader_number, day, kathisma FROM calendar_readings
+		WHERE calendar_id = ? ORDER BY reader_number, day, position`, row.id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting readings of calendar %d: %w", row.year, err)
+	}
+	defer rows.Close()
+
+	calendarData := make(domain.CalendarMap)
+	for rows.Next() {
+		var readerNumber, day, kathisma int
+		if err := rows.Scan(&readerNumber, &day, &kathisma); err != nil {
+			return nil, fmt.Errorf("error getting readings of calendar %d: %w", row.year, err)
+		}
+		days, ok := calendarData[readerNumber]
+		if !ok {
+			days = make(map[int][]int)
+			calendarData[readerNumber] = days
+		}
+		days[day] = append(days[day], kathisma)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting readings of calendar %d: %w", row.year, err)
+	}
+
+	return domain.UnmarshallCalendarOfReader(id, row.year, yearMode, row.startOffset, calendarData, createdAt, updatedAt), nil
+}
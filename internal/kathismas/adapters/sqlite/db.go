@@ -0,0 +1,205 @@
+// Package sqlite stores the kathismas domain in SQLite through the pure Go driver, in normalized tables
+// that can be queried with plain SQL. Calendars are kept one row per reader, day and kathisma.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// schemas[i] moves the database from version i to i+1, the version is kept in PRAGMA user_version.
+// A change of the schema goes to the end of the list.
+var schemas = []string{initialSchema, auditSchema, trashSchema}
+
+const initialSchema = `
+CREATE TABLE reader_groups (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL,
+	size         INTEGER NOT NULL,
+	start_offset INTEGER NOT NULL,
+	year_mode    TEXT NOT NULL,
+	lent_rule    TEXT NOT NULL,
+	version      INTEGER NOT NULL,
+	created_at   TEXT NOT NULL,
+	updated_at   TEXT NOT NULL
+);
+CREATE INDEX reader_groups_name ON reader_groups (name);
+
+CREATE TABLE group_readers (
+	id            TEXT PRIMARY KEY,
+	group_id      TEXT NOT NULL REFERENCES reader_groups (id) ON DELETE CASCADE,
+	position      INTEGER NOT NULL,
+	reader_number INTEGER NOT NULL,
+	username      TEXT NOT NULL,
+	telegram_id   INTEGER,
+	phone         TEXT NOT NULL,
+	created_at    TEXT NOT NULL,
+	updated_at    TEXT NOT NULL,
+	UNIQUE (group_id, reader_number)
+);
+-- a Telegram account reads in one group only, readers without one have NULL
+CREATE UNIQUE INDEX group_readers_telegram_id ON group_readers (telegram_id);
+
+CREATE TABLE no_reading_periods (
+	id               TEXT PRIMARY KEY,
+	group_id         TEXT NOT NULL REFERENCES reader_groups (id) ON DELETE CASCADE,
+	position         INTEGER NOT NULL,
+	name             TEXT NOT NULL,
+	anchor           TEXT NOT NULL,
+	pascha_from      INTEGER NOT NULL,
+	pascha_to        INTEGER NOT NULL,
+	fixed_from_month INTEGER NOT NULL,
+	fixed_from_day   INTEGER NOT NULL,
+	fixed_to_month   INTEGER NOT NULL,
+	fixed_to_day     INTEGER NOT NULL
+);
+CREATE INDEX no_reading_periods_group_id ON no_reading_periods (group_id);
+
+-- group_id is NULL for the calendars stored apart from any group
+CREATE TABLE calendars (
+	id           TEXT PRIMARY KEY,
+	group_id     TEXT REFERENCES reader_groups (id) ON DELETE CASCADE,
+	year         INTEGER NOT NULL,
+	year_mode    TEXT NOT NULL,
+	start_offset INTEGER NOT NULL,
+	created_at   TEXT NOT NULL,
+	updated_at   TEXT NOT NULL,
+	UNIQUE (group_id, year, year_mode)
+);
+
+CREATE TABLE calendar_readings (
+	calendar_id   TEXT NOT NULL REFERENCES calendars (id) ON DELETE CASCADE,
+	reader_number INTEGER NOT NULL,
+	day           INTEGER NOT NULL,
+	position      INTEGER NOT NULL,
+	kathisma      INTEGER NOT NULL,
+	PRIMARY KEY (calendar_id, reader_number, day, position)
+) WITHOUT ROWID;
+
+CREATE TABLE psalm_readers (
+	id            TEXT PRIMARY KEY,
+	reader_number INTEGER NOT NULL,
+	username      TEXT NOT NULL,
+	telegram_id   INTEGER UNIQUE,
+	phone         TEXT NOT NULL,
+	created_at    TEXT NOT NULL,
+	updated_at    TEXT NOT NULL
+);
+`
+
+// auditSchema keeps the audit log without a foreign key, the entries outlive the groups they describe
+const auditSchema = `
+CREATE TABLE audit_entries (
+	id         TEXT PRIMARY KEY,
+	group_id   TEXT NOT NULL,
+	actor      TEXT NOT NULL,
+	source     TEXT NOT NULL,
+	action     TEXT NOT NULL,
+	-- unix nanoseconds, so that the entries sort and filter by time exactly
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX audit_entries_group_id ON audit_entries (group_id, created_at);
+CREATE INDEX audit_entries_created_at ON audit_entries (created_at);
+
+CREATE TABLE audit_changes (
+	entry_id TEXT NOT NULL REFERENCES audit_entries (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	field    TEXT NOT NULL,
+	before   TEXT NOT NULL,
+	after    TEXT NOT NULL,
+	PRIMARY KEY (entry_id, position)
+) WITHOUT ROWID;
+`
+
+// trashSchema keeps a deleted group or removed reader whole, as the JSON of the domain value in payload,
+// since it is only ever read back to be restored
+const trashSchema = `
+CREATE TABLE trash_items (
+	id         TEXT PRIMARY KEY,
+	kind       TEXT NOT NULL,
+	group_id   TEXT NOT NULL,
+	group_name TEXT NOT NULL,
+	payload    TEXT NOT NULL,
+	deleted_at INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL
+);
+CREATE INDEX trash_items_deleted_at ON trash_items (deleted_at);
+`
+
+// Open opens the database at the path, creating or upgrading its schema
+func Open(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// a single connection serializes the writes the way bolt does
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(context.Background(), db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	switch {
+	case version == len(schemas):
+		return nil
+	case version > len(schemas):
+		return fmt.Errorf("database schema version %d is newer than the supported %d", version, len(schemas))
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for i, schema := range schemas[version:] {
+		if _, err := tx.ExecContext(ctx, schema); err != nil {
+			return fmt.Errorf("failed to create schema version %d: %w", version+i+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", len(schemas))); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
+
+// querier is a database or a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	return t, nil
+}
+
+// nullTelegramID keeps readers without a Telegram account out of the unique index
+func nullTelegramID(telegramID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: telegramID, Valid: telegramID != 0}
+}
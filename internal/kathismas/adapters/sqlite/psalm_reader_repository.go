@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type PsalmReaderRepository struct {
+	db *sql.DB
+}
+
+func NewPsalmReaderRepository(db *sql.DB) *PsalmReaderRepository {
+	if db == nil {
+		slog.Error("missing db in NewPsalmReaderRepository")
+		os.Exit(1)
+	}
+	return &PsalmReaderRepository{db: db}
+}
+
+func (pr PsalmReaderRepository) GetPsalmReaderTG(ctx context.Context, id uuid.UUID) (*domain.PsalmReader, error) {
+	row := pr.db.QueryRowContext(ctx, `
+		SELECT id, reader_number, username, telegram_id, phone, created_at, updated_at
+		FROM psalm_readers WHERE id = ?`, id.String())
+	psalmReader, err := scanPsalmReader(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NotFoundError{PsalmReaderUUID: id.String()}
+		}
+		return nil, fmt.Errorf("error getting psalm reader: %w", err)
+	}
+	return psalmReader, nil
+}
+
+func (pr PsalmReaderRepository) CreatePsalmReaderTG(ctx context.Context, psalmReader *domain.PsalmReader) error {
+	_, err := pr.db.ExecContext(ctx, `
+		INSERT INTO psalm_readers (id, reader_number, username, telegram_id, phone, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		psalmReader.ID.String(), psalmReader.ReaderNumber, psalmReader.Username, nullTelegramID(psalmReader.TelegramID),
+		psalmReader.Phone, formatTime(psalmReader.CreatedAt), formatTime(psalmReader.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating psalm reader: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type ReaderGroupRepository struct {
+	db *sql.DB
+}
+
+func NewReaderGroupRepository(db *sql.DB) *ReaderGroupRepository {
+	if db == nil {
+		slog.Error("missing db in NewReaderGroupRepository")
+		os.Exit(1)
+	}
+	return &ReaderGroupRepository{db: db}
+}
+
+func (r *ReaderGroupRepository) Create(ctx context.Context, group *domain.ReaderGroup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM reader_groups WHERE id = ?)", group.ID.String()).
+		Scan(&exists); err != nil {
+		return fmt.Errorf("error creating reader group: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("reader group already exists: %s", group.ID)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reader_groups (id, name, size, start_offset, year_mode, lent_rule, version, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+		group.ID.String(), group.Name, group.Size, group.StartOffset, string(group.YearMode), string(group.LentRule),
+		formatTime(group.CreatedAt), formatTime(group.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating reader group: %w", err)
+	}
+	if err := saveGroupDetails(ctx, tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error creating reader group: %w", err)
+	}
+	group.Version = 1
+	return nil
+}
+
+func (r *ReaderGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	return getGroup(ctx, r.db, id.String())
+}
+
+// GetByIDWithCalendars returns the group along with all of its calendars
+func (r *ReaderGroupRepository) GetByIDWithCalendars(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	group, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := loadGroupCalendars(ctx, r.db, id.String())
+	if err != nil {
+		return nil, err
+	}
+	group.Calendars = calendars
+	return group, nil
+}
+
+func (r *ReaderGroupRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error) {
+	var groupID string
+	err := r.db.QueryRowContext(ctx, "SELECT group_id FROM group_readers WHERE telegram_id = ?", telegramID).
+		Scan(&groupID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.TelegramReaderNotFoundError{TelegramID: telegramID}
+		}
+		return nil, fmt.Errorf("error getting reader by telegram ID: %w", err)
+	}
+	return getGroup(ctx, r.db, groupID)
+}
+
+func (r *ReaderGroupRepository) CountCalendars(ctx context.Context, id uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM calendars WHERE group_id = ?", id.String()).
+		Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting calendars of reader group: %w", err)
+	}
+	return count, nil
+}
+
+func (r *ReaderGroupRepository) GetAll(ctx context.Context) ([]domain.ReaderGroup, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM reader_groups ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error getting all reader groups: %w", err)
+	}
+	ids, err := scanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all reader groups: %w", err)
+	}
+
+	groups := make([]domain.ReaderGroup, 0, len(ids))
+	for _, id := range ids {
+		group, err := getGroup(ctx, r.db, id)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling reader group: %w", err)
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
+}
+
+// Update saves the group and the calendars it holds, calendars which were not loaded stay as they are.
+// The version is compared and bumped by the same statement.
+func (r *ReaderGroupRepository) Update(ctx context.Context, group *domain.ReaderGroup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE reader_groups
+		SET name = ?, size = ?, start_offset = ?, year_mode = ?, lent_rule = ?, version = version + 1, updated_at = ?
+		WHERE id = ? AND version = ?`,
+		group.Name, group.Size, group.StartOffset, string(group.YearMode), string(group.LentRule),
+		formatTime(group.UpdatedAt), group.ID.String(), group.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating reader group: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return versionError(ctx, tx, group)
+	}
+
+	if err := saveGroupDetails(ctx, tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error updating reader group: %w", err)
+	}
+	group.Version++
+	return nil
+}
+
+func (r *ReaderGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	// readers, periods and calendars go along by ON DELETE CASCADE
+	result, err := r.db.ExecContext(ctx, "DELETE FROM reader_groups WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("error deleting reader group: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return domain.ReaderGroupNotFoundError{GroupID: id}
+	}
+	return nil
+}
+
+// versionError tells a missing group from one saved since it was loaded
+func versionError(ctx context.Context, q querier, group *domain.ReaderGroup) error {
+	var stored int
+	err := q.QueryRowContext(ctx, "SELECT version FROM reader_groups WHERE id = ?", group.ID.String()).Scan(&stored)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReaderGroupNotFoundError{GroupID: group.ID}
+		}
+		return fmt.Errorf("error getting reader group: %w", err)
+	}
+	return domain.VersionConflictError{GroupID: group.ID, Version: group.Version, StoredVersion: stored}
+}
+
+// saveGroupDetails replaces the readers and the no reading periods of the group and saves its calendars
+func saveGroupDetails(ctx context.Context, q querier, group *domain.ReaderGroup) error {
+	groupID := group.ID.String()
+
+	if _, err := q.ExecContext(ctx, "DELETE FROM group_readers WHERE group_id = ?", groupID); err != nil {
+		return fmt.Errorf("error saving readers of reader group: %w", err)
+	}
+	for position, reader := range group.Readers {
+		if reader.TelegramID != 0 {
+			var otherGroupID string
+			err := q.QueryRowContext(ctx, "SELECT group_id FROM group_readers WHERE telegram_id = ?", reader.TelegramID).
+				Scan(&otherGroupID)
+			if err == nil {
+				return domain.TelegramIDTakenError{TelegramID: reader.TelegramID}
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("error getting reader by telegram ID: %w", err)
+			}
+		}
+
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO group_readers
+				(id, group_id, position, reader_number, username, telegram_id, phone, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			reader.ID.String(), groupID, position, reader.ReaderNumber, reader.Username,
+			nullTelegramID(reader.TelegramID), reader.Phone, formatTime(reader.CreatedAt), formatTime(reader.UpdatedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("error saving reader %d of reader group: %w", reader.ReaderNumber, err)
+		}
+	}
+
+	if _, err := q.ExecContext(ctx, "DELETE FROM no_reading_periods WHERE group_id = ?", groupID); err != nil {
+		return fmt.Errorf("error saving no reading periods of reader group: %w", err)
+	}
+	for position, period := range group.NoReadingPeriods {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO no_reading_periods
+				(id, group_id, position, name, anchor, pascha_from, pascha_to,
+				 fixed_from_month, fixed_from_day, fixed_to_month, fixed_to_day)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			period.ID.String(), groupID, position, period.Name, string(period.Anchor), period.PaschaFrom, period.PaschaTo,
+			int(period.FixedFrom.Month), period.FixedFrom.Day, int(period.FixedTo.Month), period.FixedTo.Day,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving no reading period %q of reader group: %w", period.Name, err)
+		}
+	}
+
+	for _, calendar := range group.Calendars {
+		if err := saveCalendar(ctx, q, sql.NullString{String: groupID, Valid: true}, calendar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getGroup returns the group without its calendars
+func getGroup(ctx context.Context, q querier, id string) (*domain.ReaderGroup, error) {
+	var (
+		name, yearMode, lentRule, createdAt, updatedAt string
+		size, startOffset, version                     int
+	)
+	err := q.QueryRowContext(ctx, `
+		SELECT id, name, size, start_offset, year_mode, lent_rule, version, created_at, updated_at
+		FROM reader_groups WHERE id = ?`, id).
+		Scan(&id, &name, &size, &startOffset, &yearMode, &lentRule, &version, &createdAt, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ReaderGroupNotFoundError{GroupID: uuid.FromStringOrNil(id)}
+		}
+		return nil, fmt.Errorf("error getting reader group: %w", err)
+	}
+
+	groupID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID: %w", err)
+	}
+	mode, err := domain.ParseCalendarYearMode(yearMode)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := domain.ParseLentReadingRule(lentRule)
+	if err != nil {
+		return nil, err
+	}
+	created, err := parseTime(createdAt)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := parseTime(updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	readers, err := loadGroupReaders(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := loadNoReadingPeriods(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.UnmarshallReaderGroup(
+		groupID,
+		name,
+		readers,
+		size,
+		startOffset,
+		mode,
+		rule,
+		periods,
+		make([]domain.CalendarOfReader, 0),
+		version,
+		created,
+		updated,
+	), nil
+}
+
+func loadGroupReaders(ctx context.Context, q querier, groupID string) ([]domain.PsalmReader, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, reader_number, username, telegram_id, phone, created_at, updated_at
+		FROM group_readers WHERE group_id = ? ORDER BY position`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting readers of reader group: %w", err)
+	}
+	defer rows.Close()
+
+	readers := make([]domain.PsalmReader, 0)
+	for rows.Next() {
+		reader, err := scanPsalmReader(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error getting readers of reader group: %w", err)
+		}
+		readers = append(readers, *reader)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting readers of reader group: %w", err)
+	}
+	return readers, nil
+}
+
+func loadNoReadingPeriods(ctx context.Context, q querier, groupID string) ([]domain.NoReadingPeriod, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, anchor, pascha_from, pascha_to, fixed_from_month, fixed_from_day, fixed_to_month, fixed_to_day
+		FROM no_reading_periods WHERE group_id = ? ORDER BY position`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting no reading periods of reader group: %w", err)
+	}
+	defer rows.Close()
+
+	periods := make([]domain.NoReadingPeriod, 0)
+	for rows.Next() {
+		var (
+			id, name, anchor                                       string
+			paschaFrom, paschaTo                                   int
+			fixedFromMonth, fixedFromDay, fixedToMonth, fixedToDay int
+		)
+		if err := rows.Scan(&id, &name, &anchor, &paschaFrom, &paschaTo,
+			&fixedFromMonth, &fixedFromDay, &fixedToMonth, &fixedToDay); err != nil {
+			return nil, fmt.Errorf("error getting no reading periods of reader group: %w", err)
+		}
+		periodID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no reading period ID: %w", err)
+		}
+		periods = append(periods, *domain.UnmarshallNoReadingPeriod(
+			periodID,
+			name,
+			domain.NoReadingAnchor(anchor),
+			paschaFrom,
+			paschaTo,
+			domain.MonthDay{Month: time.Month(fixedFromMonth), Day: fixedFromDay},
+			domain.MonthDay{Month: time.Month(fixedToMonth), Day: fixedToDay},
+		))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting no reading periods of reader group: %w", err)
+	}
+	return periods, nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPsalmReader(row rowScanner) (*domain.PsalmReader, error) {
+	var (
+		id, username, phone, createdAt, updatedAt string
+		readerNumber                              int8
+		telegramID                                sql.NullInt64
+	)
+	if err := row.Scan(&id, &readerNumber, &username, &telegramID, &phone, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	readerID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reader ID: %w", err)
+	}
+	created, err := parseTime(createdAt)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := parseTime(updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return domain.UnmarshallPsalmReader(readerID, readerNumber, username, telegramID.Int64, phone, created, updated), nil
+}
+
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/repotest"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	db, err := Open(filepath.Join(tb.TempDir(), "test.sqlite"))
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newTestCalendar(year int) domain.CalendarOfReader {
+	return *domain.NewCalendarOfReader(year, domain.CalendarYearCivil, 1, domain.CalendarMap{1: {1: {1}, 2: {2, 3}}, 2: {1: {4}}})
+}
+
+func TestReaderGroupRepository_Contract(t *testing.T) {
+	repotest.ReaderGroupRepository(t, func(t *testing.T) domain.RepositoryReaderGroup {
+		return NewReaderGroupRepository(openTestDB(t))
+	})
+}
+
+func TestCalendarOfReaderRepository(t *testing.T) {
+	repo := NewCalendarOfReaderRepository(openTestDB(t))
+
+	calendar := newTestCalendar(2026)
+	require.NoError(t, repo.CreateCalendarOfReader(&calendar))
+
+	stored, err := repo.GetCalendar(calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, calendar.Year, stored.Year)
+	assert.Equal(t, calendar.Calendar, stored.Calendar)
+}
+
+func TestPsalmReaderRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPsalmReaderRepository(openTestDB(t))
+
+	reader, err := domain.NewPsalmReader("Иван", 1001, "+70000000000", 3)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePsalmReaderTG(ctx, reader))
+
+	stored, err := repo.GetPsalmReaderTG(ctx, reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reader.Username, stored.Username)
+	assert.Equal(t, reader.Phone, stored.Phone)
+	assert.Equal(t, reader.ReaderNumber, stored.ReaderNumber)
+
+	other, err := domain.NewPsalmReader("Петр", 0, "", 4)
+	require.NoError(t, err)
+	var notFound domain.NotFoundError
+	_, err = repo.GetPsalmReaderTG(ctx, other.ID)
+	require.ErrorAs(t, err, &notFound)
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type TrashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	if db == nil {
+		slog.Error("missing db in NewTrashRepository")
+		os.Exit(1)
+	}
+	return &TrashRepository{db: db}
+}
+
+func (r *TrashRepository) Put(ctx context.Context, item *domain.TrashItem) error {
+	var payload any = item.Group
+	if item.Reader != nil {
+		payload = item.Reader
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding trash item: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO trash_items (id, kind, group_id, group_name, payload, deleted_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		item.ID.String(), string(item.Kind), item.GroupID.String(), item.GroupName, string(data),
+		item.DeletedAt.UnixNano(), item.ExpiresAt.UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving trash item: %w", err)
+	}
+	return nil
+}
+
+const trashItemColumns = "id, kind, group_id, group_name, payload, deleted_at, expires_at"
+
+func (r *TrashRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.TrashItem, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+trashItemColumns+" FROM trash_items WHERE id = ?", id.String())
+	item, err := scanTrashItem(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.TrashItemNotFoundError{ItemID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *TrashRepository) List(ctx context.Context) ([]domain.TrashItem, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+trashItemColumns+" FROM trash_items ORDER BY deleted_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("error listing trash: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.TrashItem
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing trash: %w", err)
+	}
+	return items, nil
+}
+
+func (r *TrashRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM trash_items WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("error deleting trash item: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting trash item: %w", err)
+	}
+	if deleted == 0 {
+		return domain.TrashItemNotFoundError{ItemID: id}
+	}
+	return nil
+}
+
+func scanTrashItem(row rowScanner) (*domain.TrashItem, error) {
+	var id, kind, groupID, payload string
+	var deletedAt, expiresAt int64
+	item := &domain.TrashItem{}
+	if err := row.Scan(&id, &kind, &groupID, &item.GroupName, &payload, &deletedAt, &expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading trash item: %w", err)
+	}
+	item.ID = uuid.FromStringOrNil(id)
+	item.Kind = domain.TrashKind(kind)
+	item.GroupID = uuid.FromStringOrNil(groupID)
+	item.DeletedAt = time.Unix(0, deletedAt)
+	item.ExpiresAt = time.Unix(0, expiresAt)
+
+	var target any
+	switch item.Kind {
+	case domain.TrashKindGroup:
+		item.Group = &domain.ReaderGroup{}
+		target = item.Group
+	case domain.TrashKindReader:
+		item.Reader = &domain.PsalmReader{}
+		target = item.Reader
+	default:
+		return nil, fmt.Errorf("unknown trash item kind %q", kind)
+	}
+	if err := json.Unmarshal([]byte(payload), target); err != nil {
+		return nil, fmt.Errorf("error decoding trash item %s: %w", id, err)
+	}
+	return item, nil
+}
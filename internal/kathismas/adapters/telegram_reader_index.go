@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/gofrs/uuid/v5"
+	bolt "go.etcd.io/bbolt"
+)
+
+// telegramReaderBucket is the bucket storm keeps TelegramReaderDB records in
+const telegramReaderBucket = "TelegramReaderDB"
+
+// TelegramReaderDB points a Telegram ID at the group and the reader it belongs to.
+// The records are kept by the reader group repository along with the groups.
+type TelegramReaderDB struct {
+	TelegramID int64  `storm:"id" json:"telegram_id"`
+	GroupID    string `json:"group_id"`
+	ReaderID   string `json:"reader_id"`
+}
+
+// indexGroupReaders points the Telegram IDs of the readers at the group and drops the ones of the previous
+// readers which are gone. A Telegram ID of another group fails with a TelegramIDTakenError, unless the reader
+// is unchanged from the previous one: a duplicate left from before the index must not block editing the group.
+func indexGroupReaders(node storm.Node, groupID string, previous, readers []PsalmReaderTGDB) error {
+	unchanged := make(map[int64]uuid.UUID, len(previous))
+	for _, reader := range previous {
+		if reader.TelegramID != 0 {
+			unchanged[reader.TelegramID] = reader.ID
+		}
+	}
+
+	current := make(map[int64]bool, len(readers))
+	for _, reader := range readers {
+		if reader.TelegramID == 0 {
+			continue
+		}
+		current[reader.TelegramID] = true
+
+		var indexed TelegramReaderDB
+		err := node.One("TelegramID", reader.TelegramID, &indexed)
+		if err != nil && !errors.Is(err, storm.ErrNotFound) {
+			return fmt.Errorf("error getting reader by telegram ID: %w", err)
+		}
+		if err == nil && indexed.GroupID != groupID {
+			if readerID, ok := unchanged[reader.TelegramID]; ok && readerID == reader.ID {
+				continue
+			}
+			return domain.TelegramIDTakenError{TelegramID: reader.TelegramID}
+		}
+
+		if err := node.Save(&TelegramReaderDB{
+			TelegramID: reader.TelegramID,
+			GroupID:    groupID,
+			ReaderID:   reader.ID.String(),
+		}); err != nil {
+			return fmt.Errorf("error indexing reader by telegram ID: %w", err)
+		}
+	}
+
+	for _, reader := range previous {
+		if reader.TelegramID == 0 || current[reader.TelegramID] {
+			continue
+		}
+		var indexed TelegramReaderDB
+		err := node.One("TelegramID", reader.TelegramID, &indexed)
+		if errors.Is(err, storm.ErrNotFound) || (err == nil && indexed.GroupID != groupID) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error getting reader by telegram ID: %w", err)
+		}
+		if err := node.DeleteStruct(&indexed); err != nil {
+			return fmt.Errorf("error removing reader from telegram ID index: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexTelegramReaders builds the Telegram ID index of the readers stored before it existed
+// and returns how many readers were indexed. Databases indexed before the schema version was kept
+// already have the index, it is left as it is.
+func indexTelegramReaders(node storm.Node, tx *bolt.Tx) (int, error) {
+	if tx.Bucket([]byte(telegramReaderBucket)) != nil {
+		return 0, nil
+	}
+
+	var dbGroups []ReaderGroupDB
+	if err := node.All(&dbGroups); err != nil {
+		return 0, fmt.Errorf("error getting reader groups: %w", err)
+	}
+
+	// the bucket marks the index as built even when nobody has a Telegram ID yet
+	if err := node.Init(&TelegramReaderDB{}); err != nil {
+		return 0, fmt.Errorf("error creating telegram ID index: %w", err)
+	}
+
+	indexed := 0
+	for i := range dbGroups {
+		for _, reader := range dbGroups[i].Readers {
+			if reader.TelegramID == 0 {
+				continue
+			}
+			err := indexGroupReaders(node, dbGroups[i].ID, nil, []PsalmReaderTGDB{reader})
+			var taken domain.TelegramIDTakenError
+			if errors.As(err, &taken) {
+				// registered in several groups before the index, the first group keeps the reader,
+				// the others can still be updated as long as the reader stays as it is
+				slog.Warn("telegram ID is registered in several groups",
+					"telegram_id", reader.TelegramID, "group_id", dbGroups[i].ID)
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			indexed++
+		}
+	}
+	return indexed, nil
+}
@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGroupWithReader(t *testing.T, name string, telegramID int64) *domain.ReaderGroup {
+	t.Helper()
+	group, err := domain.NewReaderGroup(name, 1)
+	require.NoError(t, err)
+	reader, err := domain.NewPsalmReader("Иван", telegramID, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(reader))
+	return group
+}
+
+func TestReaderGroupRepository_GetByTelegramID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReaderGroupRepository(openTestDB(t))
+
+	group := newTestGroupWithReader(t, "First Group", 1001)
+	require.NoError(t, repo.Create(ctx, group))
+
+	found, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, found.ID)
+
+	// the index follows readers added and removed later
+	reader, err := domain.NewPsalmReader("Петр", 1002, "", 2)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(reader))
+	require.NoError(t, group.RemoveReader(group.Readers[0].ID))
+	require.NoError(t, repo.Update(ctx, group))
+
+	_, err = repo.GetByTelegramID(ctx, 1001)
+	require.Error(t, err)
+	found, err = repo.GetByTelegramID(ctx, 1002)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, found.ID)
+
+	require.NoError(t, repo.Delete(ctx, group.ID))
+	_, err = repo.GetByTelegramID(ctx, 1002)
+	require.Error(t, err)
+}
+
+func TestReaderGroupRepository_TelegramIDIsUniqueAcrossGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReaderGroupRepository(openTestDB(t))
+
+	first := newTestGroupWithReader(t, "First Group", 1001)
+	require.NoError(t, repo.Create(ctx, first))
+
+	var taken domain.TelegramIDTakenError
+	err := repo.Create(ctx, newTestGroupWithReader(t, "Second Group", 1001))
+	require.ErrorAs(t, err, &taken)
+	assert.Equal(t, int64(1001), taken.TelegramID)
+
+	second, err := domain.NewReaderGroup("Second Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, second))
+	reader, err := domain.NewPsalmReader("Иван", 1001, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, second.AddReader(reader))
+	require.ErrorAs(t, repo.Update(ctx, second), &taken)
+
+	stored, err := repo.GetByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Readers)
+
+	// once the reader leaves the first group the account can join another one
+	require.NoError(t, first.RemoveReader(first.Readers[0].ID))
+	require.NoError(t, repo.Update(ctx, first))
+	require.NoError(t, repo.Update(ctx, second))
+
+	found, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+}
+
+func TestIndexTelegramReaders(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	// groups stored before the index, the same account registered in both of them
+	first := repo.marshalToDB(newTestGroupWithReader(t, "First Group", 1001))
+	second := repo.marshalToDB(newTestGroupWithReader(t, "Second Group", 1001))
+	second.Readers = append(second.Readers, PsalmReaderTGDB{ID: uuid.Must(uuid.NewV7()), ReaderNumber: 2, TelegramID: 1002})
+	require.NoError(t, db.Save(&first))
+	require.NoError(t, db.Save(&second))
+
+	indexed, err := runMigration(db, indexTelegramReaders)
+	require.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+
+	found, err := repo.GetByTelegramID(ctx, 1002)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID.String())
+
+	indexed, err = runMigration(db, indexTelegramReaders)
+	require.NoError(t, err)
+	assert.Zero(t, indexed)
+}
+
+func TestIndexTelegramReaders_DuplicateDoesNotBlockUpdate(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewReaderGroupRepository(db)
+
+	first := repo.marshalToDB(newTestGroupWithReader(t, "First Group", 1001))
+	second := repo.marshalToDB(newTestGroupWithReader(t, "Second Group", 1001))
+	require.NoError(t, db.Save(&first))
+	require.NoError(t, db.Save(&second))
+	_, err := runMigration(db, indexTelegramReaders)
+	require.NoError(t, err)
+
+	group, err := repo.GetByIDWithCalendars(ctx, uuid.FromStringOrNil(second.ID))
+	require.NoError(t, err)
+	require.NoError(t, group.UpdateName("Renamed Group"))
+	require.NoError(t, repo.Update(ctx, group))
+
+	found, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID.String(), "the first group keeps the reader")
+
+	// a reader changed in the second group still cannot take the account
+	group.Readers[0].ID = uuid.Must(uuid.NewV7())
+	var taken domain.TelegramIDTakenError
+	require.ErrorAs(t, repo.Update(ctx, group), &taken)
+}
@@ -0,0 +1,163 @@
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/asdine/storm/v3"
+	"github.com/gofrs/uuid/v5"
+)
+
+// TrashItemDB keeps a deleted group together with its calendars in Group.Calendars,
+// or a removed reader in Reader
+type TrashItemDB struct {
+	ID        string           `storm:"id" json:"id"`
+	Kind      string           `json:"kind"`
+	GroupID   string           `json:"group_id"`
+	GroupName string           `json:"group_name"`
+	Group     *ReaderGroupDB   `json:"group,omitempty"`
+	Reader    *PsalmReaderTGDB `json:"reader,omitempty"`
+	DeletedAt time.Time        `json:"deleted_at"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+type TrashRepository struct {
+	db     *storm.DB
+	groups *ReaderGroupRepository
+}
+
+func NewTrashRepository(db *storm.DB) *TrashRepository {
+	if db == nil {
+		slog.Error("missing db in NewTrashRepository")
+		os.Exit(1)
+	}
+	return &TrashRepository{db: db, groups: NewReaderGroupRepository(db)}
+}
+
+func (r *TrashRepository) Put(ctx context.Context, item *domain.TrashItem) error {
+	dbItem := r.marshalToDB(item)
+	if err := r.db.Save(&dbItem); err != nil {
+		return fmt.Errorf("error saving trash item: %w", err)
+	}
+	return nil
+}
+
+func (r *TrashRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.TrashItem, error) {
+	var dbItem TrashItemDB
+	if err := r.db.One("ID", id.String(), &dbItem); err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return nil, domain.TrashItemNotFoundError{ItemID: id}
+		}
+		return nil, fmt.Errorf("error getting trash item: %w", err)
+	}
+	return r.unmarshalFromDB(&dbItem)
+}
+
+func (r *TrashRepository) List(ctx context.Context) ([]domain.TrashItem, error) {
+	var dbItems []TrashItemDB
+	if err := r.db.All(&dbItems); err != nil {
+		return nil, fmt.Errorf("error listing trash: %w", err)
+	}
+
+	items := make([]domain.TrashItem, 0, len(dbItems))
+	for i := range dbItems {
+		item, err := r.unmarshalFromDB(&dbItems[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	slices.SortFunc(items, func(a, b domain.TrashItem) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(b.ID.String(), a.ID.String()))
+	})
+	return items, nil
+}
+
+func (r *TrashRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.DeleteStruct(&TrashItemDB{ID: id.String()}); err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return domain.TrashItemNotFoundError{ItemID: id}
+		}
+		return fmt.Errorf("error deleting trash item: %w", err)
+	}
+	return nil
+}
+
+func (r *TrashRepository) marshalToDB(item *domain.TrashItem) TrashItemDB {
+	dbItem := TrashItemDB{
+		ID:        item.ID.String(),
+		Kind:      string(item.Kind),
+		GroupID:   item.GroupID.String(),
+		GroupName: item.GroupName,
+		DeletedAt: item.DeletedAt,
+		ExpiresAt: item.ExpiresAt,
+	}
+	if item.Group != nil {
+		dbGroup := r.groups.marshalToDB(item.Group)
+		dbGroup.Calendars = make([]CalendarRefDB, 0, len(item.Group.Calendars))
+		for _, calendar := range item.Group.Calendars {
+			dbGroup.Calendars = append(dbGroup.Calendars, marshalCalendarRef(calendar))
+		}
+		dbItem.Group = &dbGroup
+	}
+	if item.Reader != nil {
+		dbItem.Reader = &PsalmReaderTGDB{
+			ID:           item.Reader.ID,
+			ReaderNumber: item.Reader.ReaderNumber,
+			Username:     item.Reader.Username,
+			TelegramID:   item.Reader.TelegramID,
+			Phone:        item.Reader.Phone,
+			CreatedAt:    item.Reader.CreatedAt,
+			UpdatedAt:    item.Reader.UpdatedAt,
+		}
+	}
+	return dbItem
+}
+
+func (r *TrashRepository) unmarshalFromDB(dbItem *TrashItemDB) (*domain.TrashItem, error) {
+	id, err := uuid.FromString(dbItem.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trash item ID: %w", err)
+	}
+	item := &domain.TrashItem{
+		ID:        id,
+		Kind:      domain.TrashKind(dbItem.Kind),
+		GroupID:   uuid.FromStringOrNil(dbItem.GroupID),
+		GroupName: dbItem.GroupName,
+		DeletedAt: dbItem.DeletedAt,
+		ExpiresAt: dbItem.ExpiresAt,
+	}
+	if dbItem.Group != nil {
+		group, err := r.groups.unmarshalFromDB(dbItem.Group)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbCalendar := range dbItem.Group.Calendars {
+			calendar, err := unmarshalCalendarRef(dbCalendar)
+			if err != nil {
+				return nil, err
+			}
+			group.Calendars = append(group.Calendars, *calendar)
+		}
+		item.Group = group
+	}
+	if dbItem.Reader != nil {
+		item.Reader = domain.UnmarshallPsalmReader(
+			dbItem.Reader.ID,
+			dbItem.Reader.ReaderNumber,
+			dbItem.Reader.Username,
+			dbItem.Reader.TelegramID,
+			dbItem.Reader.Phone,
+			dbItem.Reader.CreatedAt,
+			dbItem.Reader.UpdatedAt,
+		)
+	}
+	return item, nil
+}
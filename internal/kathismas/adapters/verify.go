@@ -0,0 +1,176 @@
+package adapters
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/asdine/storm/v3/codec/json"
+	bolt "go.etcd.io/bbolt"
+)
+
+// readerGroupBucket is the bucket storm keeps ReaderGroupDB records in
+const readerGroupBucket = "ReaderGroupDB"
+
+// verifyOpenTimeout keeps a verification of the live database from waiting for the application to release it
+const verifyOpenTimeout = 5 * time.Second
+
+// VerifyReport tells what a bolt database holds and what is wrong with it.
+// Readers are the readers of the groups, Calendars the calendars of the groups,
+// including the ones still embedded in the records of databases waiting for a migration.
+type VerifyReport struct {
+	SchemaVersion int
+	Groups        int
+	Readers       int
+	Calendars     int
+	// Problems lists the inconsistencies found by bolt and the records that do not decode
+	Problems []string
+}
+
+func (r *VerifyReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *VerifyReport) addProblem(format string, args ...any) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// VerifyDatabase opens the bolt file read only, runs the bolt consistency check and decodes every group
+// and calendar the way the repositories do. An error means the file could not be read as a bolt database
+// at all, the problems found inside it are in the report.
+func VerifyDatabase(path string) (*VerifyReport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{ReadOnly: true, Timeout: verifyOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	defer db.Close()
+
+	report := &VerifyReport{}
+	err = db.View(func(tx *bolt.Tx) error {
+		// bolt reads the pages of a truncated file past its end and crashes, its check included
+		if size := tx.Size(); size > info.Size() {
+			report.addProblem("file is truncated: %d bytes of %d", info.Size(), size)
+			return nil
+		}
+		// bolt panics on the pages it can not read instead of returning an error. The check of bolt runs
+		// in a goroutine of its own where the panic can not be recovered, so the pages it reads are read
+		// here first and it only checks a tree that can be read.
+		defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+		defer func() {
+			if p := recover(); p != nil {
+				report.addProblem("unreadable data: %v", p)
+			}
+		}()
+
+		readAllPages(tx)
+		for errCheck := range tx.Check() {
+			report.addProblem("consistency check: %v", errCheck)
+		}
+
+		version, err := schemaVersion(tx)
+		if err != nil {
+			report.addProblem("%v", err)
+		}
+		report.SchemaVersion = version
+		if latest := migrations[len(migrations)-1].version; version > latest {
+			report.addProblem("schema version %d is newer than the supported %d", version, latest)
+		}
+
+		groupIDs, err := verifyGroups(tx, report)
+		if err != nil {
+			return err
+		}
+		return verifyGroupCalendars(tx, groupIDs, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// readAllPages reads every page of every bucket, nested buckets included, panicking on the ones bolt can not read
+func readAllPages(tx *bolt.Tx) {
+	_ = tx.ForEach(func(_ []byte, bucket *bolt.Bucket) error {
+		bucket.Stats()
+		return nil
+	})
+}
+
+// verifyGroups counts the groups that decode and returns their IDs
+func verifyGroups(tx *bolt.Tx, report *VerifyReport) (map[string]bool, error) {
+	groupIDs := make(map[string]bool)
+	bucket := tx.Bucket([]byte(readerGroupBucket))
+	if bucket == nil {
+		return groupIDs, nil
+	}
+
+	var groups ReaderGroupRepository
+	err := bucket.ForEach(func(key, value []byte) error {
+		// nested buckets hold the storm metadata and indexes
+		if value == nil {
+			return nil
+		}
+		var dbGroup ReaderGroupDB
+		if err := json.Codec.Unmarshal(value, &dbGroup); err != nil {
+			report.addProblem("reader group %s: %v", key, err)
+			return nil
+		}
+		if _, err := groups.unmarshalFromDB(&dbGroup); err != nil {
+			report.addProblem("reader group %s: %v", key, err)
+			return nil
+		}
+		for _, dbCalendar := range dbGroup.Calendars {
+			if _, err := unmarshalCalendarRef(dbCalendar); err != nil {
+				report.addProblem("calendar %d of reader group %s: %v", dbCalendar.Year, key, err)
+				continue
+			}
+			report.Calendars++
+		}
+		groupIDs[dbGroup.ID] = true
+		report.Groups++
+		report.Readers += len(dbGroup.Readers)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading reader groups: %w", err)
+	}
+	return groupIDs, nil
+}
+
+// verifyGroupCalendars counts the calendars that decode and belong to a stored group
+func verifyGroupCalendars(tx *bolt.Tx, groupIDs map[string]bool, report *VerifyReport) error {
+	bucket := tx.Bucket([]byte(groupCalendarBucket))
+	if bucket == nil {
+		return nil
+	}
+
+	err := bucket.ForEach(func(key, value []byte) error {
+		if value == nil {
+			return nil
+		}
+		var dbCalendar GroupCalendarDB
+		if err := json.Codec.Unmarshal(value, &dbCalendar); err != nil {
+			report.addProblem("calendar %s: %v", key, err)
+			return nil
+		}
+		if _, err := unmarshalCalendarRef(dbCalendar.CalendarRefDB); err != nil {
+			report.addProblem("calendar %s: %v", key, err)
+			return nil
+		}
+		if !groupIDs[dbCalendar.GroupID] {
+			report.addProblem("calendar %s belongs to no stored reader group", key)
+			return nil
+		}
+		report.Calendars++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading calendars: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package adapters
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// newVerifiedDB stores a migrated database with two groups, one of them with two calendars, and closes it
+func newVerifiedDB(t *testing.T, change func(db *storm.DB)) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.db")
+	db, err := storm.Open(path, storm.Codec(json.Codec))
+	require.NoError(t, err)
+	_, err = Migrate(db, false)
+	require.NoError(t, err)
+
+	repo := NewReaderGroupRepository(db)
+	withCalendars := newTestGroupWithReader(t, "First", 1001)
+	require.NoError(t, withCalendars.AddCalendar(newTestCalendar(2026)))
+	require.NoError(t, withCalendars.AddCalendar(newTestCalendar(2027)))
+	require.NoError(t, repo.Create(context.Background(), withCalendars))
+	require.NoError(t, repo.Create(context.Background(), newTestGroupWithReader(t, "Second", 1002)))
+
+	if change != nil {
+		change(db)
+	}
+	require.NoError(t, db.Close())
+	return path
+}
+
+func TestVerifyDatabase(t *testing.T) {
+	report, err := VerifyDatabase(newVerifiedDB(t, nil))
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, &VerifyReport{
+		SchemaVersion: migrations[len(migrations)-1].version,
+		Groups:        2,
+		Readers:       2,
+		Calendars:     2,
+	}, report)
+}
+
+func TestVerifyDatabase_Problems(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(t *testing.T, db *storm.DB)
+		wantProblem string
+	}{
+		{
+			name: "record that does not decode",
+			change: func(t *testing.T, db *storm.DB) {
+				require.NoError(t, db.Bolt.Update(func(tx *bolt.Tx) error {
+					return tx.Bucket([]byte(readerGroupBucket)).Put([]byte("broken"), []byte(`{"id": 1}`))
+				}))
+			},
+			wantProblem: "reader group broken",
+		},
+		{
+			name: "group with a broken ID",
+			change: func(t *testing.T, db *storm.DB) {
+				require.NoError(t, db.Save(&ReaderGroupDB{ID: "not-a-uuid", Name: "Broken"}))
+			},
+			wantProblem: "invalid group ID",
+		},
+		{
+			name: "calendar of a deleted group",
+			change: func(t *testing.T, db *storm.DB) {
+				require.NoError(t, db.Save(&GroupCalendarDB{
+					Key:           groupCalendarKey("orphan", 2026, "civil"),
+					GroupID:       "orphan",
+					CalendarRefDB: marshalCalendarRef(newTestCalendar(2026)),
+				}))
+			},
+			wantProblem: "belongs to no stored reader group",
+		},
+		{
+			name: "newer schema",
+			change: func(t *testing.T, db *storm.DB) {
+				require.NoError(t, db.Bolt.Update(func(tx *bolt.Tx) error {
+					return setSchemaVersion(tx, migrations[len(migrations)-1].version+1)
+				}))
+			},
+			wantProblem: "is newer than the supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := VerifyDatabase(newVerifiedDB(t, func(db *storm.DB) { tt.change(t, db) }))
+			require.NoError(t, err)
+			assert.False(t, report.OK())
+			require.Len(t, report.Problems, 1)
+			assert.Contains(t, report.Problems[0], tt.wantProblem)
+		})
+	}
+}
+
+func TestVerifyDatabase_TruncatedFile(t *testing.T) {
+	var size int64
+	path := newVerifiedDB(t, func(db *storm.DB) {
+		require.NoError(t, db.Bolt.View(func(tx *bolt.Tx) error {
+			size = tx.Size()
+			return nil
+		}))
+	})
+	// bolt grows the file ahead of the data, so only a cut below the data size loses pages
+	require.NoError(t, os.Truncate(path, size/2))
+
+	report, err := VerifyDatabase(path)
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	assert.Contains(t, report.Problems[0], "file is truncated")
+}
+
+func TestVerifyDatabase_NotADatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, os.WriteFile(path, []byte("not a bolt database"), 0o600))
+
+	_, err := VerifyDatabase(path)
+	require.Error(t, err)
+}
+
+func TestVerifyDatabase_CorruptBranchPage(t *testing.T) {
+	var root uint64
+	var pageSize int
+	path := newVerifiedDB(t, func(db *storm.DB) {
+		// enough records for the bucket to have a branch page as its root
+		require.NoError(t, db.Bolt.Update(func(tx *bolt.Tx) error {
+			bucket, err := tx.CreateBucket([]byte("Filler"))
+			if err != nil {
+				return err
+			}
+			for i := range 2000 {
+				if err := bucket.Put([]byte(fmt.Sprintf("key-%05d", i)), make([]byte, 64)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+		require.NoError(t, db.Bolt.View(func(tx *bolt.Tx) error {
+			root = uint64(tx.Bucket([]byte("Filler")).Root())
+			info, err := tx.Page(int(root))
+			require.NoError(t, err)
+			require.Equal(t, "branch", info.Type)
+			return nil
+		}))
+		pageSize = db.Bolt.Info().PageSize
+	})
+
+	// the first element of a branch page follows the 16 byte page header, its child page ID is at offset 8
+	file, err := os.OpenFile(path, os.O_RDWR, 0o600)
+	require.NoError(t, err)
+	child := make([]byte, 8)
+	binary.LittleEndian.PutUint64(child, 1<<40)
+	_, err = file.WriteAt(child, int64(root)*int64(pageSize)+16+8)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	report, err := VerifyDatabase(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Problems)
+	assert.Contains(t, report.Problems[0], "unreadable data")
+}
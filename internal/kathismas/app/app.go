@@ -34,6 +34,14 @@ type Commands struct {
 	DeleteReaderGroup          command.DeleteReaderGroupHandler
 	UpdateReaderGroup          command.UpdateReaderGroupHandler
 	RegenerateCalendarForGroup command.RegenerateCalendarForGroupHandler
+	AddNoReadingPeriod         command.AddNoReadingPeriodHandler
+	RemoveNoReadingPeriod      command.RemoveNoReadingPeriodHandler
+	GenerateCalendarRange      command.GenerateCalendarRangeForGroupHandler
+	RestoreFromTrash           command.RestoreFromTrashHandler
+	PurgeTrash                 command.PurgeTrashHandler
+	ImportReaderGroup          command.ImportReaderGroupHandler
+	RestoreGroupFromBackup     command.RestoreGroupFromBackupHandler
+	CreateBackup               command.CreateBackupHandler
 }
 
 type Queries struct {
@@ -41,4 +49,10 @@ type Queries struct {
 	GetReaderGroup        query.GetReaderGroupHandler
 	GetCurrentKathisma    query.GetCurrentKathismaHandler
 	GetReaderByTelegramID query.GetReaderByTelegramIDHandler
+	VerifyCalendar        query.VerifyCalendarHandler
+	GetPaschalion         query.GetPaschalionHandler
+	ListAuditEntries      query.ListAuditEntriesHandler
+	ListTrash             query.ListTrashHandler
+	ExportReaderGroup     query.ExportReaderGroupHandler
+	DownloadBackup        query.DownloadBackupHandler
 }
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type AddNoReadingPeriod struct {
+	GroupID    uuid.UUID
+	Name       string
+	Anchor     domain.NoReadingAnchor
+	PaschaFrom int
+	PaschaTo   int
+	FixedFrom  domain.MonthDay
+	FixedTo    domain.MonthDay
+}
+
+type AddNoReadingPeriodHandler struct {
+	groupRepo domain.RepositoryReaderGroup
+	auditLog  domain.RepositoryAuditLog
+}
+
+func NewAddNoReadingPeriodHandler(
+	groupRepo domain.RepositoryReaderGroup,
+	auditLog domain.RepositoryAuditLog,
+) AddNoReadingPeriodHandler {
+	if groupRepo == nil {
+		panic("nil groupRepo")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return AddNoReadingPeriodHandler{groupRepo: groupRepo, auditLog: auditLog}
+}
+
+func (h AddNoReadingPeriodHandler) Handle(ctx context.Context, cmd AddNoReadingPeriod) error {
+	return retryUpdateOnConflict(ctx, func() error {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h AddNoReadingPeriodHandler) handle(ctx context.Context, cmd AddNoReadingPeriod) error {
+	group, err := h.groupRepo.GetByID(ctx, cmd.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to get reader group: %w", err)
+	}
+	before := group.Snapshot()
+
+	var period *domain.NoReadingPeriod
+	switch cmd.Anchor {
+	case domain.NoReadingAnchorPascha:
+		period, err = domain.NewPaschaNoReadingPeriod(cmd.Name, cmd.PaschaFrom, cmd.PaschaTo)
+	case domain.NoReadingAnchorFixed:
+		period, err = domain.NewFixedNoReadingPeriod(cmd.Name, cmd.FixedFrom, cmd.FixedTo)
+	default:
+		err = fmt.Errorf("unknown no reading period anchor %q", cmd.Anchor)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create no reading period: %w", err)
+	}
+
+	if err := group.AddNoReadingPeriod(*period); err != nil {
+		return fmt.Errorf("failed to add no reading period to group: %w", err)
+	}
+
+	if err := h.groupRepo.Update(ctx, group); err != nil {
+		return fmt.Errorf("failed to update reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionAddNoReadingPeriod, group.ID, domain.DiffReaderGroups(before, group))
+
+	return nil
+}
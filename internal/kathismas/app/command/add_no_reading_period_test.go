@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/mocks"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddNoReadingPeriodHandler_Handle(t *testing.T) {
+	groupID, _ := uuid.NewV7()
+
+	tests := []struct {
+		name        string
+		cmd         AddNoReadingPeriod
+		setupMock   func(repo *mocks.RepositoryReaderGroupMock)
+		wantErr     bool
+		errContains string
+		validate    func(t *testing.T, repo *mocks.RepositoryReaderGroupMock)
+	}{
+		{
+			name: "pascha anchored period",
+			cmd: AddNoReadingPeriod{
+				GroupID:    groupID,
+				Name:       "Пятидесятница",
+				Anchor:     domain.NoReadingAnchorPascha,
+				PaschaFrom: 49,
+				PaschaTo:   50,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					group.ID = groupID
+					return group, nil
+				}
+				repo.UpdateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					require.Len(t, group.NoReadingPeriods, 2)
+					assert.Equal(t, "Пятидесятница", group.NoReadingPeriods[1].Name)
+					assert.Equal(t, 49, group.NoReadingPeriods[1].PaschaFrom)
+					return nil
+				}
+			},
+			validate: func(t *testing.T, repo *mocks.RepositoryReaderGroupMock) {
+				assert.Len(t, repo.UpdateCalls(), 1)
+			},
+		},
+		{
+			name: "fixed period",
+			cmd: AddNoReadingPeriod{
+				GroupID:   groupID,
+				Name:      "Святки",
+				Anchor:    domain.NoReadingAnchorFixed,
+				FixedFrom: domain.MonthDay{Month: time.January, Day: 7},
+				FixedTo:   domain.MonthDay{Month: time.January, Day: 17},
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					return group, nil
+				}
+				repo.UpdateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					require.Len(t, group.NoReadingPeriods, 2)
+					assert.Equal(t, domain.NoReadingAnchorFixed, group.NoReadingPeriods[1].Anchor)
+					return nil
+				}
+			},
+		},
+		{
+			name: "unknown anchor",
+			cmd: AddNoReadingPeriod{
+				GroupID: groupID,
+				Name:    "Test",
+				Anchor:  "lunar",
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					return group, nil
+				}
+			},
+			wantErr:     true,
+			errContains: "unknown no reading period anchor",
+			validate: func(t *testing.T, repo *mocks.RepositoryReaderGroupMock) {
+				assert.Empty(t, repo.UpdateCalls())
+			},
+		},
+		{
+			name: "group not found",
+			cmd: AddNoReadingPeriod{
+				GroupID: groupID,
+				Name:    "Test",
+				Anchor:  domain.NoReadingAnchorPascha,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					return nil, errors.New("group not found")
+				}
+			},
+			wantErr:     true,
+			errContains: "failed to get reader group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repoMock := &mocks.RepositoryReaderGroupMock{}
+			tt.setupMock(repoMock)
+			handler := NewAddNoReadingPeriodHandler(repoMock, memory.NewAuditLogRepository())
+
+			// Act
+			err := handler.Handle(context.Background(), tt.cmd)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errContains != "" {
+					assert.Contains(t, err.Error(), tt.errContains)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, repoMock)
+			}
+		})
+	}
+}
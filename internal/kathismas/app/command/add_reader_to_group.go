@@ -18,20 +18,34 @@ type AddReaderToGroup struct {
 
 type AddReaderToGroupHandler struct {
 	groupRepo domain.RepositoryReaderGroup
+	auditLog  domain.RepositoryAuditLog
 }
 
-func NewAddReaderToGroupHandler(groupRepo domain.RepositoryReaderGroup) AddReaderToGroupHandler {
+func NewAddReaderToGroupHandler(
+	groupRepo domain.RepositoryReaderGroup,
+	auditLog domain.RepositoryAuditLog,
+) AddReaderToGroupHandler {
 	if groupRepo == nil {
 		panic("nil groupRepo")
 	}
-	return AddReaderToGroupHandler{groupRepo: groupRepo}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return AddReaderToGroupHandler{groupRepo: groupRepo, auditLog: auditLog}
 }
 
 func (h AddReaderToGroupHandler) Handle(ctx context.Context, cmd AddReaderToGroup) error {
+	return retryUpdateOnConflict(ctx, func() error {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h AddReaderToGroupHandler) handle(ctx context.Context, cmd AddReaderToGroup) error {
 	group, err := h.groupRepo.GetByID(ctx, cmd.GroupID)
 	if err != nil {
 		return fmt.Errorf("failed to get reader group: %w", err)
 	}
+	before := group.Snapshot()
 
 	reader, err := domain.NewPsalmReader(cmd.Username, cmd.TelegramID, cmd.Phone, cmd.ReaderNumber)
 	if err != nil {
@@ -45,6 +59,7 @@ func (h AddReaderToGroupHandler) Handle(ctx context.Context, cmd AddReaderToGrou
 	if err := h.groupRepo.Update(ctx, group); err != nil {
 		return fmt.Errorf("failed to update reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionAddReader, group.ID, domain.DiffReaderGroups(before, group))
 
 	return nil
 }
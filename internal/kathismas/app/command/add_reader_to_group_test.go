@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/mocks"
 	"github.com/gofrs/uuid/v5"
@@ -139,6 +140,60 @@ func TestAddReaderToGroupHandler_Handle(t *testing.T) {
 				assert.Len(t, repo.UpdateCalls(), 1)
 			},
 		},
+		{
+			name: "concurrent change is retried on the reloaded group",
+			cmd: AddReaderToGroup{
+				GroupID:      groupID,
+				ReaderNumber: 2,
+				Username:     "Test User",
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					group.Version = len(repo.GetByIDCalls())
+					if group.Version > 1 {
+						// the reader registered in the meantime
+						reader1, _ := domain.NewPsalmReader("Иван", 0, "", 1)
+						_ = group.AddReader(reader1)
+					}
+					return group, nil
+				}
+				repo.UpdateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					if group.Version == 1 {
+						return domain.VersionConflictError{GroupID: group.ID, Version: 1, StoredVersion: 2}
+					}
+					assert.Len(t, group.Readers, 2)
+					return nil
+				}
+			},
+			wantErr: false,
+			validate: func(t *testing.T, repo *mocks.RepositoryReaderGroupMock) {
+				assert.Len(t, repo.GetByIDCalls(), 2)
+				assert.Len(t, repo.UpdateCalls(), 2)
+			},
+		},
+		{
+			name: "conflict persisting after all attempts",
+			cmd: AddReaderToGroup{
+				GroupID:      groupID,
+				ReaderNumber: 1,
+				Username:     "Test User",
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					return group, nil
+				}
+				repo.UpdateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					return domain.VersionConflictError{GroupID: group.ID}
+				}
+			},
+			wantErr:     true,
+			errContains: "changed concurrently",
+			validate: func(t *testing.T, repo *mocks.RepositoryReaderGroupMock) {
+				assert.Len(t, repo.UpdateCalls(), maxConflictAttempts)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -146,7 +201,7 @@ func TestAddReaderToGroupHandler_Handle(t *testing.T) {
 			// Arrange
 			repoMock := &mocks.RepositoryReaderGroupMock{}
 			tt.setupMock(repoMock)
-			handler := NewAddReaderToGroupHandler(repoMock)
+			handler := NewAddReaderToGroupHandler(repoMock, memory.NewAuditLogRepository())
 
 			// Act
 			err := handler.Handle(context.Background(), tt.cmd)
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// Actor is who runs a command, the ports put it into the context with WithActor
+type Actor struct {
+	Name   string
+	Source domain.AuditSource
+}
+
+type actorContextKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the system actor when no port put one into the context
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Name: "system", Source: domain.AuditSourceSystem}
+}
+
+// recordAudit appends the entry of a change which is already saved. The change is not undone
+// when the entry cannot be written, so the failure is only logged.
+func recordAudit(
+	ctx context.Context,
+	auditLog domain.RepositoryAuditLog,
+	action domain.AuditAction,
+	groupID uuid.UUID,
+	changes []domain.AuditChange,
+) {
+	actor := ActorFromContext(ctx)
+	entry, err := domain.NewAuditEntry(actor.Name, actor.Source, action, groupID, changes)
+	if err == nil {
+		err = auditLog.Append(ctx, entry)
+	}
+	if err != nil {
+		slog.Error("failed to append audit entry", "action", action, "group_id", groupID, "error", err)
+	}
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands_AppendAuditEntries(t *testing.T) {
+	groupRepo := memory.NewReaderGroupRepository()
+	auditLog := memory.NewAuditLogRepository()
+
+	webCtx := WithActor(context.Background(), Actor{Name: "127.0.0.1", Source: domain.AuditSourceWeb})
+	groupID, err := NewCreateReaderGroupHandler(groupRepo, auditLog).
+		Handle(webCtx, CreateReaderGroup{Name: "Test Group", StartOffset: 1})
+	require.NoError(t, err)
+
+	telegramCtx := WithActor(context.Background(), Actor{Name: "@ivan", Source: domain.AuditSourceTelegram})
+	err = NewAddReaderToGroupHandler(groupRepo, auditLog).Handle(telegramCtx, AddReaderToGroup{
+		GroupID:      groupID,
+		ReaderNumber: 3,
+		Username:     "Иван",
+		TelegramID:   1001,
+	})
+	require.NoError(t, err)
+
+	// a failed command leaves no entry
+	err = NewAddReaderToGroupHandler(groupRepo, auditLog).Handle(telegramCtx, AddReaderToGroup{
+		GroupID:      groupID,
+		ReaderNumber: 3,
+		Username:     "Петр",
+	})
+	require.Error(t, err)
+
+	err = NewDeleteReaderGroupHandler(groupRepo, memory.NewTrashRepository(), auditLog, time.Hour).
+		Handle(context.Background(), DeleteReaderGroup{GroupID: groupID})
+	require.NoError(t, err)
+
+	entries, err := auditLog.List(context.Background(), domain.AuditFilter{GroupID: groupID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	deleted, added, created := entries[0], entries[1], entries[2]
+	assert.Equal(t, domain.AuditActionCreateGroup, created.Action)
+	assert.Equal(t, "127.0.0.1", created.Actor)
+	assert.Equal(t, domain.AuditSourceWeb, created.Source)
+	assert.Contains(t, created.Changes, domain.AuditChange{Field: "name", After: "Test Group"})
+
+	assert.Equal(t, domain.AuditActionAddReader, added.Action)
+	assert.Equal(t, "@ivan", added.Actor)
+	assert.Equal(t, domain.AuditSourceTelegram, added.Source)
+	assert.Equal(t, []domain.AuditChange{{Field: "reader 3", After: "Иван (telegram 1001)"}}, added.Changes)
+
+	assert.Equal(t, domain.AuditActionDeleteGroup, deleted.Action)
+	assert.Equal(t, domain.AuditSourceSystem, deleted.Source)
+	assert.Contains(t, deleted.Changes, domain.AuditChange{Field: "name", Before: "Test Group"})
+}
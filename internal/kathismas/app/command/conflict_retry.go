@@ -0,0 +1,28 @@
+package command
+
+import (
+	"context"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+// maxConflictAttempts bounds how many times a command loads and changes a group saved concurrently
+const maxConflictAttempts = 3
+
+// retryOnConflict runs the load, change and save of a group again when the group was saved in between,
+// so that the change is applied on top of the other one instead of dropping it
+func retryOnConflict[T any](ctx context.Context, handle func() (T, error)) (T, error) {
+	result, err := handle()
+	for attempt := 1; attempt < maxConflictAttempts && domain.IsVersionConflict(err) && ctx.Err() == nil; attempt++ {
+		result, err = handle()
+	}
+	return result, err
+}
+
+// retryUpdateOnConflict is retryOnConflict for commands without a result
+func retryUpdateOnConflict(ctx context.Context, handle func() error) error {
+	_, err := retryOnConflict(ctx, func() (struct{}, error) {
+		return struct{}{}, handle()
+	})
+	return err
+}
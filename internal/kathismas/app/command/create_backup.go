@@ -0,0 +1,33 @@
+package command
+
+import (
+	"context"
+	"fmt"
+)
+
+type CreateBackup struct{}
+
+// BackupCreator writes a verified backup of the database and returns its path
+type BackupCreator interface {
+	CreateBackup() (string, error)
+}
+
+type CreateBackupHandler struct {
+	backups BackupCreator
+}
+
+func NewCreateBackupHandler(backups BackupCreator) CreateBackupHandler {
+	if backups == nil {
+		panic("nil backups")
+	}
+	return CreateBackupHandler{backups: backups}
+}
+
+// Handle takes a backup into the backup directory and prunes the old ones, it returns the path of the backup
+func (h CreateBackupHandler) Handle(_ context.Context, _ CreateBackup) (string, error) {
+	path, err := h.backups.CreateBackup()
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
+	}
+	return path, nil
+}
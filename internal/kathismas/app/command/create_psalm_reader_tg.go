@@ -2,12 +2,13 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/common/decorator"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/common/errors"
-	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
 )
 
 type CreatePsalmReaderTG struct {
@@ -20,16 +21,18 @@ type CreatePsalmReaderTG struct {
 type CreateCalendarOfReaderHandler decorator.CommandHandler[CreatePsalmReaderTG]
 
 type createPsalmReaderTGHandler struct {
-	repo *adapters.PsalmReaderTGRepository
+	repo     domain.RepositoryPsalmReader
+	auditLog domain.RepositoryAuditLog
 }
 
 func NewCreatePsalmReaderTGHandler(
-	repo *adapters.PsalmReaderTGRepository,
+	repo domain.RepositoryPsalmReader,
+	auditLog domain.RepositoryAuditLog,
 	logger *slog.Logger,
 	metricsClient decorator.MetricsClient,
 ) decorator.CommandHandler[CreatePsalmReaderTG] {
 	return decorator.ApplyCommandDecorators[CreatePsalmReaderTG](
-		createPsalmReaderTGHandler{repo: repo},
+		createPsalmReaderTGHandler{repo: repo, auditLog: auditLog},
 		logger,
 		metricsClient,
 	)
@@ -46,5 +49,8 @@ func (cpr createPsalmReaderTGHandler) Handle(ctx context.Context, cmd CreatePsal
 	if err != nil {
 		return errors.NewSlugError(err.Error(), "unable-to-create-psalm-reader-tg-availability")
 	}
+	recordAudit(ctx, cpr.auditLog, domain.AuditActionCreatePsalmReader, uuid.Nil, []domain.AuditChange{
+		{Field: fmt.Sprintf("reader %d", prTG.ReaderNumber), After: prTG.Username},
+	})
 	return nil
 }
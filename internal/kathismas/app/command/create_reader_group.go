@@ -11,17 +11,30 @@ import (
 type CreateReaderGroup struct {
 	Name        string
 	StartOffset int
+	// Size is the number of readers in the group, zero means a full group of 20
+	Size int
+	// YearMode selects the window of the calendar year, empty means January to December
+	YearMode domain.CalendarYearMode
+	// LentRule selects the load during Great Lent, empty means the ordinary one
+	LentRule domain.LentReadingRule
 }
 
 type CreateReaderGroupHandler struct {
-	repo domain.RepositoryReaderGroup
+	repo     domain.RepositoryReaderGroup
+	auditLog domain.RepositoryAuditLog
 }
 
-func NewCreateReaderGroupHandler(repo domain.RepositoryReaderGroup) CreateReaderGroupHandler {
+func NewCreateReaderGroupHandler(
+	repo domain.RepositoryReaderGroup,
+	auditLog domain.RepositoryAuditLog,
+) CreateReaderGroupHandler {
 	if repo == nil {
 		panic("nil repo")
 	}
-	return CreateReaderGroupHandler{repo: repo}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return CreateReaderGroupHandler{repo: repo, auditLog: auditLog}
 }
 
 func (h CreateReaderGroupHandler) Handle(ctx context.Context, cmd CreateReaderGroup) (uuid.UUID, error) {
@@ -30,9 +43,28 @@ func (h CreateReaderGroupHandler) Handle(ctx context.Context, cmd CreateReaderGr
 		return uuid.Nil, fmt.Errorf("failed to create reader group: %w", err)
 	}
 
+	if cmd.Size != 0 {
+		if err := group.UpdateSize(cmd.Size); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to create reader group: %w", err)
+		}
+	}
+
+	if cmd.YearMode != "" {
+		if err := group.UpdateYearMode(cmd.YearMode); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to create reader group: %w", err)
+		}
+	}
+
+	if cmd.LentRule != "" {
+		if err := group.UpdateLentRule(cmd.LentRule); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to create reader group: %w", err)
+		}
+	}
+
 	if err := h.repo.Create(ctx, group); err != nil {
 		return uuid.Nil, fmt.Errorf("failed to save reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionCreateGroup, group.ID, domain.DiffReaderGroups(nil, group))
 
 	return group.ID, nil
 }
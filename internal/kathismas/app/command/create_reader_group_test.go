@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/mocks"
 	"github.com/gofrs/uuid/v5"
@@ -57,6 +58,79 @@ func TestCreateReaderGroupHandler_Handle(t *testing.T) {
 				assert.Len(t, repo.CreateCalls(), 1)
 				call := repo.CreateCalls()[0]
 				assert.Equal(t, 20, call.Group.StartOffset)
+				assert.Equal(t, domain.KathismasCount, call.Group.Size)
+				assert.Equal(t, domain.CalendarYearCivil, call.Group.YearMode)
+				assert.Equal(t, domain.LentReadingOrdinary, call.Group.LentRule)
+			},
+		},
+		{
+			name: "creation with custom size",
+			cmd: CreateReaderGroup{
+				Name:        "Малая группа",
+				StartOffset: 1,
+				Size:        7,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.CreateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					return nil
+				}
+			},
+			wantErr: false,
+			validate: func(t *testing.T, groupID uuid.UUID, repo *mocks.RepositoryReaderGroupMock) {
+				require.Len(t, repo.CreateCalls(), 1)
+				assert.Equal(t, 7, repo.CreateCalls()[0].Group.Size)
+			},
+		},
+		{
+			name: "creation with church year",
+			cmd: CreateReaderGroup{
+				Name:        "Test Group",
+				StartOffset: 1,
+				YearMode:    domain.CalendarYearChurch,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.CreateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					return nil
+				}
+			},
+			wantErr: false,
+			validate: func(t *testing.T, groupID uuid.UUID, repo *mocks.RepositoryReaderGroupMock) {
+				require.Len(t, repo.CreateCalls(), 1)
+				assert.Equal(t, domain.CalendarYearChurch, repo.CreateCalls()[0].Group.YearMode)
+			},
+		},
+		{
+			name: "creation with double psalter in lent",
+			cmd: CreateReaderGroup{
+				Name:        "Test Group",
+				StartOffset: 1,
+				LentRule:    domain.LentReadingDoublePsalter,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				repo.CreateFunc = func(ctx context.Context, group *domain.ReaderGroup) error {
+					return nil
+				}
+			},
+			wantErr: false,
+			validate: func(t *testing.T, groupID uuid.UUID, repo *mocks.RepositoryReaderGroupMock) {
+				require.Len(t, repo.CreateCalls(), 1)
+				assert.Equal(t, domain.LentReadingDoublePsalter, repo.CreateCalls()[0].Group.LentRule)
+			},
+		},
+		{
+			name: "invalid size",
+			cmd: CreateReaderGroup{
+				Name:        "Test",
+				StartOffset: 1,
+				Size:        21,
+			},
+			setupMock: func(repo *mocks.RepositoryReaderGroupMock) {
+				// Should not be called
+			},
+			wantErr:     true,
+			errContains: "group size must be between 1 and 20",
+			validate: func(t *testing.T, groupID uuid.UUID, repo *mocks.RepositoryReaderGroupMock) {
+				assert.Len(t, repo.CreateCalls(), 0)
 			},
 		},
 		{
@@ -126,7 +200,7 @@ func TestCreateReaderGroupHandler_Handle(t *testing.T) {
 			// Arrange
 			repoMock := &mocks.RepositoryReaderGroupMock{}
 			tt.setupMock(repoMock)
-			handler := NewCreateReaderGroupHandler(repoMock)
+			handler := NewCreateReaderGroupHandler(repoMock, memory.NewAuditLogRepository())
 
 			// Act
 			groupID, err := handler.Handle(context.Background(), tt.cmd)
@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/gofrs/uuid/v5"
@@ -10,27 +12,73 @@ import (
 
 type DeleteReaderGroup struct {
 	GroupID uuid.UUID
+	// Version is the version of the group the deletion was asked at, zero when unknown.
+	// A stale version is reported to the user instead of trashing a group with changes they have not seen.
+	Version int
 }
 
+// DeleteReaderGroupHandler moves the group with its calendars to the trash, where it stays restorable
+// for the retention period
 type DeleteReaderGroupHandler struct {
 	readerGroupRepo domain.RepositoryReaderGroup
+	trash           domain.RepositoryTrash
+	auditLog        domain.RepositoryAuditLog
+	retention       time.Duration
 }
 
-func NewDeleteReaderGroupHandler(readerGroupRepo domain.RepositoryReaderGroup) DeleteReaderGroupHandler {
+func NewDeleteReaderGroupHandler(
+	readerGroupRepo domain.RepositoryReaderGroup,
+	trash domain.RepositoryTrash,
+	auditLog domain.RepositoryAuditLog,
+	retention time.Duration,
+) DeleteReaderGroupHandler {
 	if readerGroupRepo == nil {
 		panic("nil readerGroupRepo")
 	}
-	return DeleteReaderGroupHandler{readerGroupRepo: readerGroupRepo}
+	if trash == nil {
+		panic("nil trash")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return DeleteReaderGroupHandler{
+		readerGroupRepo: readerGroupRepo,
+		trash:           trash,
+		auditLog:        auditLog,
+		retention:       retention,
+	}
 }
 
 func (h DeleteReaderGroupHandler) Handle(ctx context.Context, cmd DeleteReaderGroup) error {
-	if _, err := h.readerGroupRepo.GetByID(ctx, cmd.GroupID); err != nil {
+	group, err := h.readerGroupRepo.GetByIDWithCalendars(ctx, cmd.GroupID)
+	if err != nil {
 		return fmt.Errorf("failed to get reader group: %w", err)
 	}
+	if cmd.Version != 0 && cmd.Version != group.Version {
+		return domain.VersionConflictError{GroupID: group.ID, Version: cmd.Version, StoredVersion: group.Version}
+	}
+
+	item, err := domain.NewGroupTrashItem(group, h.retention)
+	if err != nil {
+		return fmt.Errorf("failed to move reader group to trash: %w", err)
+	}
+	if err := h.trash.Put(ctx, item); err != nil {
+		return fmt.Errorf("failed to move reader group to trash: %w", err)
+	}
 
 	if err := h.readerGroupRepo.Delete(ctx, cmd.GroupID); err != nil {
+		discardTrashItem(ctx, h.trash, item.ID)
 		return fmt.Errorf("failed to delete reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionDeleteGroup, group.ID, domain.DiffReaderGroups(group, nil))
 
 	return nil
 }
+
+// discardTrashItem takes back an item whose deletion failed, so that the trash does not offer a restore
+// of what was never deleted
+func discardTrashItem(ctx context.Context, trash domain.RepositoryTrash, id uuid.UUID) {
+	if err := trash.Delete(ctx, id); err != nil {
+		slog.Error("failed to discard trash item", "item_id", id, "error", err)
+	}
+}
@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteReaderGroupHandler_StaleVersion(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+	group := tt.createGroup(t)
+	seenVersion := group.Version
+
+	// the group is renamed after the page with the delete button was shown
+	stored, err := tt.groupRepo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.NoError(t, stored.UpdateName("Renamed Group"))
+	require.NoError(t, tt.groupRepo.Update(ctx, stored))
+
+	deleteGroup := NewDeleteReaderGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour)
+	err = deleteGroup.Handle(ctx, DeleteReaderGroup{GroupID: group.ID, Version: seenVersion})
+	var conflict domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, stored.Version, conflict.StoredVersion)
+
+	_, err = tt.groupRepo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	items, err := tt.trash.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	require.NoError(t, deleteGroup.Handle(ctx, DeleteReaderGroup{GroupID: group.ID, Version: stored.Version}))
+	tt.onlyItem(t)
+}
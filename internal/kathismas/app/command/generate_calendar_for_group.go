@@ -19,46 +19,66 @@ type GenerateCalendarForGroup struct {
 }
 
 type CalendarGenerator interface {
-	GenerateForGroup(year, startOffset int) (*bytes.Buffer, domain.CalendarMap, error)
+	GenerateForGroup(
+		year, startOffset, readersCount int,
+		yearMode domain.CalendarYearMode,
+		lentRule domain.LentReadingRule,
+		noReadingPeriods []domain.NoReadingPeriod,
+	) (*bytes.Buffer, domain.CalendarMap, error)
 }
 
 type GenerateCalendarForGroupHandler struct {
 	groupRepo domain.RepositoryReaderGroup
 	generator CalendarGenerator
+	auditLog  domain.RepositoryAuditLog
 }
 
 func NewGenerateCalendarForGroupHandler(
 	groupRepo domain.RepositoryReaderGroup,
 	generator CalendarGenerator,
+	auditLog domain.RepositoryAuditLog,
 ) GenerateCalendarForGroupHandler {
-	if groupRepo == nil || generator == nil {
-		slog.Error("not found group repo or generator calendar in NewGenerateCalendarForGroupHandler")
+	if groupRepo == nil || generator == nil || auditLog == nil {
+		slog.Error("not found group repo, generator calendar or audit log in NewGenerateCalendarForGroupHandler")
 		os.Exit(1)
 	}
 	return GenerateCalendarForGroupHandler{
 		groupRepo: groupRepo,
 		generator: generator,
+		auditLog:  auditLog,
 	}
 }
 
 func (h GenerateCalendarForGroupHandler) Handle(ctx context.Context, cmd GenerateCalendarForGroup) (*bytes.Buffer, error) {
-	group, err := h.groupRepo.GetByID(ctx, cmd.GroupID)
+	return retryOnConflict(ctx, func() (*bytes.Buffer, error) {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h GenerateCalendarForGroupHandler) handle(ctx context.Context, cmd GenerateCalendarForGroup) (*bytes.Buffer, error) {
+	group, err := h.groupRepo.GetByIDWithCalendars(ctx, cmd.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reader group: %w", err)
 	}
+	before := group.Snapshot()
 
 	year := cmd.Year
 	if year == 0 {
-		year = time.Now().Year()
+		year = group.YearMode.YearOf(time.Now())
 	}
 	startOffset := h.calculateStartOffset(group, year, cmd.StartOffset)
 
-	buffer, calendarData, err := h.generator.GenerateForGroup(year, startOffset)
+	buffer, calendarData, err := h.generator.GenerateForGroup(
+		year, startOffset, group.Size, group.YearMode, group.LentRule, group.NoReadingPeriods,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate calendar: %w", err)
 	}
 
-	calendar := domain.NewCalendarOfReader(year, startOffset, calendarData)
+	calendar := domain.NewCalendarOfReader(year, group.YearMode, startOffset, calendarData)
+	if err := calendar.Verify(); err != nil {
+		return nil, fmt.Errorf("generated calendar is invalid: %w", err)
+	}
 
 	if err := group.AddCalendar(*calendar); err != nil {
 		return nil, fmt.Errorf("failed to add calendar to group: %w", err)
@@ -67,6 +87,7 @@ func (h GenerateCalendarForGroupHandler) Handle(ctx context.Context, cmd Generat
 	if err := h.groupRepo.Update(ctx, group); err != nil {
 		return nil, fmt.Errorf("failed to update reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionGenerateCalendar, group.ID, domain.DiffReaderGroups(before, group))
 
 	return buffer, nil
 }
@@ -75,10 +96,5 @@ func (h GenerateCalendarForGroupHandler) calculateStartOffset(group *domain.Read
 	if cmdStartOffset != 0 {
 		return cmdStartOffset
 	}
-	for _, cal := range group.Calendars {
-		if cal.Year == year-1 {
-			return cal.CalculateNextStartOffset()
-		}
-	}
-	return group.StartOffset
+	return group.NextStartOffset(year)
 }
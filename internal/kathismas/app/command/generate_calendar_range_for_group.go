@@ -0,0 +1,117 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxCalendarRangeYears bounds how many years can be generated in one go
+const MaxCalendarRangeYears = 20
+
+type GenerateCalendarRangeForGroup struct {
+	GroupID  uuid.UUID
+	FromYear int
+	ToYear   int
+}
+
+// CalendarRangeGenerator computes calendars apart from rendering them, so that several years
+// can be chained and put into one workbook
+type CalendarRangeGenerator interface {
+	CalculateForGroup(
+		year, startOffset, readersCount int,
+		yearMode domain.CalendarYearMode,
+		lentRule domain.LentReadingRule,
+		noReadingPeriods []domain.NoReadingPeriod,
+	) domain.CalendarMap
+	RenderForGroup(calendars []domain.CalendarOfReader) (*bytes.Buffer, error)
+}
+
+type GenerateCalendarRangeForGroupHandler struct {
+	groupRepo domain.RepositoryReaderGroup
+	generator CalendarRangeGenerator
+	auditLog  domain.RepositoryAuditLog
+}
+
+func NewGenerateCalendarRangeForGroupHandler(
+	groupRepo domain.RepositoryReaderGroup,
+	generator CalendarRangeGenerator,
+	auditLog domain.RepositoryAuditLog,
+) GenerateCalendarRangeForGroupHandler {
+	if groupRepo == nil || generator == nil || auditLog == nil {
+		slog.Error("not found group repo, generator calendar or audit log in NewGenerateCalendarRangeForGroupHandler")
+		os.Exit(1)
+	}
+	return GenerateCalendarRangeForGroupHandler{
+		groupRepo: groupRepo,
+		generator: generator,
+		auditLog:  auditLog,
+	}
+}
+
+// Handle generates the calendars of every year in the range, each year continuing the previous one
+// exactly as if they were generated one by one, replacing the stored calendars of those years,
+// and returns them in one workbook
+func (h GenerateCalendarRangeForGroupHandler) Handle(
+	ctx context.Context,
+	cmd GenerateCalendarRangeForGroup,
+) (*bytes.Buffer, error) {
+	return retryOnConflict(ctx, func() (*bytes.Buffer, error) {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h GenerateCalendarRangeForGroupHandler) handle(
+	ctx context.Context,
+	cmd GenerateCalendarRangeForGroup,
+) (*bytes.Buffer, error) {
+	if cmd.FromYear < domain.MinSupportedYear || cmd.ToYear > domain.MaxSupportedYear || cmd.ToYear < cmd.FromYear {
+		return nil, fmt.Errorf("invalid year range %d-%d", cmd.FromYear, cmd.ToYear)
+	}
+	if cmd.ToYear-cmd.FromYear+1 > MaxCalendarRangeYears {
+		return nil, fmt.Errorf("year range must not exceed %d years", MaxCalendarRangeYears)
+	}
+
+	group, err := h.groupRepo.GetByIDWithCalendars(ctx, cmd.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reader group: %w", err)
+	}
+	before := group.Snapshot()
+
+	calendars := make([]domain.CalendarOfReader, 0, cmd.ToYear-cmd.FromYear+1)
+	for year := cmd.FromYear; year <= cmd.ToYear; year++ {
+		// a stored calendar of the year is replaced, otherwise the next year would continue it
+		// instead of the one generated here
+		group.RemoveCalendarsByYear(year)
+		startOffset := group.NextStartOffset(year)
+		calendarData := h.generator.CalculateForGroup(
+			year, startOffset, group.Size, group.YearMode, group.LentRule, group.NoReadingPeriods,
+		)
+
+		calendar := domain.NewCalendarOfReader(year, group.YearMode, startOffset, calendarData)
+		if err := calendar.Verify(); err != nil {
+			return nil, fmt.Errorf("generated calendar is invalid: %w", err)
+		}
+		if err := group.AddCalendar(*calendar); err != nil {
+			return nil, fmt.Errorf("failed to add calendar to group: %w", err)
+		}
+		calendars = append(calendars, *calendar)
+	}
+
+	buffer, err := h.generator.RenderForGroup(calendars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate calendar: %w", err)
+	}
+
+	if err := h.groupRepo.Update(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to update reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionGenerateCalendarRange, group.ID, domain.DiffReaderGroups(before, group))
+
+	return buffer, nil
+}
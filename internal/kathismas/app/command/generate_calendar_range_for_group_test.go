@@ -0,0 +1,188 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/mocks"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/services"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// calendarGeneratorStub computes real calendars but skips building workbooks
+type calendarGeneratorStub struct {
+	rendered [][]domain.CalendarOfReader
+}
+
+func (g *calendarGeneratorStub) CalculateForGroup(
+	year, startOffset, readersCount int,
+	yearMode domain.CalendarYearMode,
+	lentRule domain.LentReadingRule,
+	noReadingPeriods []domain.NoReadingPeriod,
+) domain.CalendarMap {
+	calendar := services.CreateCalendarForGroup(
+		startOffset, year, readersCount, yearMode, lentRule, noReadingPeriods,
+	)
+	calendarMap := make(domain.CalendarMap)
+	for pair := calendar.Oldest(); pair != nil; pair = pair.Next() {
+		calendarMap[pair.Key] = pair.Value
+	}
+	return calendarMap
+}
+
+func (g *calendarGeneratorStub) RenderForGroup(calendars []domain.CalendarOfReader) (*bytes.Buffer, error) {
+	g.rendered = append(g.rendered, calendars)
+	return &bytes.Buffer{}, nil
+}
+
+func (g *calendarGeneratorStub) GenerateForGroup(
+	year, startOffset, readersCount int,
+	yearMode domain.CalendarYearMode,
+	lentRule domain.LentReadingRule,
+	noReadingPeriods []domain.NoReadingPeriod,
+) (*bytes.Buffer, domain.CalendarMap, error) {
+	return &bytes.Buffer{}, g.CalculateForGroup(
+		year, startOffset, readersCount, yearMode, lentRule, noReadingPeriods,
+	), nil
+}
+
+func newGroupRepoStub(group *domain.ReaderGroup) *mocks.RepositoryReaderGroupMock {
+	return &mocks.RepositoryReaderGroupMock{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+			return group, nil
+		},
+		GetByIDWithCalendarsFunc: func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+			return group, nil
+		},
+		UpdateFunc: func(ctx context.Context, group *domain.ReaderGroup) error {
+			return nil
+		},
+	}
+}
+
+func TestGenerateCalendarRangeForGroupHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		cmd         GenerateCalendarRangeForGroup
+		wantErr     bool
+		errContains string
+		wantYears   []int
+	}{
+		{
+			name:      "five years",
+			cmd:       GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2030},
+			wantYears: []int{2026, 2027, 2028, 2029, 2030},
+		},
+		{
+			name:      "single year",
+			cmd:       GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2026},
+			wantYears: []int{2026},
+		},
+		{
+			name:        "reversed range",
+			cmd:         GenerateCalendarRangeForGroup{FromYear: 2030, ToYear: 2026},
+			wantErr:     true,
+			errContains: "invalid year range",
+		},
+		{
+			name:        "too long range",
+			cmd:         GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2050},
+			wantErr:     true,
+			errContains: "year range must not exceed 20 years",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, _ := domain.NewReaderGroup("Test Group", 5)
+			repo := newGroupRepoStub(group)
+			generator := &calendarGeneratorStub{}
+			handler := NewGenerateCalendarRangeForGroupHandler(repo, generator, memory.NewAuditLogRepository())
+
+			_, err := handler.Handle(context.Background(), tt.cmd)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Empty(t, repo.UpdateCalls())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, repo.UpdateCalls(), 1)
+			require.Len(t, generator.rendered, 1)
+			years := make([]int, 0, len(group.Calendars))
+			for _, calendar := range generator.rendered[0] {
+				years = append(years, calendar.Year)
+			}
+			assert.Equal(t, tt.wantYears, years)
+			assert.Len(t, group.Calendars, len(tt.wantYears))
+		})
+	}
+}
+
+func TestGenerateCalendarRangeForGroupHandler_SameAsYearByYear(t *testing.T) {
+	for _, yearMode := range []domain.CalendarYearMode{domain.CalendarYearCivil, domain.CalendarYearChurch} {
+		t.Run(string(yearMode), func(t *testing.T) {
+			rangeGroup, _ := domain.NewReaderGroup("Range", 5)
+			require.NoError(t, rangeGroup.UpdateSize(7))
+			require.NoError(t, rangeGroup.UpdateYearMode(yearMode))
+			yearlyGroup, _ := domain.NewReaderGroup("Yearly", 5)
+			require.NoError(t, yearlyGroup.UpdateSize(7))
+			require.NoError(t, yearlyGroup.UpdateYearMode(yearMode))
+
+			generator := &calendarGeneratorStub{}
+			rangeHandler := NewGenerateCalendarRangeForGroupHandler(
+				newGroupRepoStub(rangeGroup), generator, memory.NewAuditLogRepository(),
+			)
+			_, err := rangeHandler.Handle(context.Background(), GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2030})
+			require.NoError(t, err)
+
+			yearlyHandler := NewGenerateCalendarForGroupHandler(
+				newGroupRepoStub(yearlyGroup), generator, memory.NewAuditLogRepository(),
+			)
+			for year := 2026; year <= 2030; year++ {
+				_, err := yearlyHandler.Handle(context.Background(), GenerateCalendarForGroup{Year: year})
+				require.NoError(t, err)
+			}
+
+			require.Len(t, rangeGroup.Calendars, 5)
+			require.Len(t, yearlyGroup.Calendars, 5)
+			for i := range rangeGroup.Calendars {
+				assert.Equal(t, yearlyGroup.Calendars[i].Year, rangeGroup.Calendars[i].Year)
+				assert.Equal(t, yearlyGroup.Calendars[i].StartOffset, rangeGroup.Calendars[i].StartOffset)
+				assert.Equal(t, yearlyGroup.Calendars[i].Calendar, rangeGroup.Calendars[i].Calendar)
+			}
+		})
+	}
+}
+
+func TestGenerateCalendarRangeForGroupHandler_OverStoredYears(t *testing.T) {
+	group, _ := domain.NewReaderGroup("Test Group", 5)
+	generator := &calendarGeneratorStub{}
+	handler := NewGenerateCalendarRangeForGroupHandler(
+		newGroupRepoStub(group), generator, memory.NewAuditLogRepository(),
+	)
+	_, err := handler.Handle(context.Background(), GenerateCalendarRangeForGroup{FromYear: 2025, ToYear: 2028})
+	require.NoError(t, err)
+
+	require.NoError(t, group.UpdateSize(7))
+	_, err = handler.Handle(context.Background(), GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2028})
+	require.NoError(t, err)
+
+	require.Len(t, group.Calendars, 4)
+	for year := 2026; year <= 2028; year++ {
+		previous := group.CalendarForYear(year - 1)
+		calendar := group.CalendarForYear(year)
+		require.NotNil(t, previous)
+		require.NotNil(t, calendar)
+		lastDay := calendar.StartDate().AddDate(0, 0, -1)
+		assert.Equal(t, previous.CalculateStartOffsetAfter(lastDay), calendar.StartOffset, "year %d", year)
+	}
+	assert.Empty(t, group.FindCalendarDrifts())
+}
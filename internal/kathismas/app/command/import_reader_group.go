@@ -0,0 +1,356 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// ImportMode tells what to do with the IDs of an imported group
+type ImportMode string
+
+const (
+	// ImportKeepIDs creates the group under the IDs of the document and fails when the group exists
+	ImportKeepIDs ImportMode = "keep"
+	// ImportRegenerateIDs creates a copy of the group under new IDs, without the Telegram accounts
+	// that already read in another group
+	ImportRegenerateIDs ImportMode = "regenerate"
+	// ImportMerge adds to the stored group what it lacks and creates the group when there is none
+	ImportMerge ImportMode = "merge"
+)
+
+// ParseImportMode returns the mode by its name, an empty name keeps the IDs
+func ParseImportMode(value string) (ImportMode, error) {
+	switch mode := ImportMode(value); mode {
+	case ImportKeepIDs, ImportRegenerateIDs, ImportMerge:
+		return mode, nil
+	case "":
+		return ImportKeepIDs, nil
+	default:
+		return "", fmt.Errorf("unknown import mode %q, expected keep, regenerate or merge", value)
+	}
+}
+
+// GroupExistsError means a group is already stored under the ID of the imported one
+type GroupExistsError struct {
+	GroupID uuid.UUID
+}
+
+func (e GroupExistsError) Error() string {
+	return fmt.Sprintf("reader group %s already exists, import it with new IDs or merge it", e.GroupID)
+}
+
+type ImportReaderGroup struct {
+	Document []byte
+	Mode     ImportMode
+}
+
+// ImportReaderGroupResult counts what the import added, Skipped* what a merge left out
+// because the stored group already has it and ClearedTelegramIDs the readers a copy took
+// without their Telegram accounts
+type ImportReaderGroupResult struct {
+	GroupID               uuid.UUID `json:"group_id"`
+	Created               bool      `json:"created"`
+	AddedReaders          int       `json:"added_readers"`
+	AddedNoReadingPeriods int       `json:"added_no_reading_periods"`
+	AddedCalendars        int       `json:"added_calendars"`
+	SkippedReaders        int       `json:"skipped_readers"`
+	SkippedPeriods        int       `json:"skipped_no_reading_periods"`
+	SkippedCalendars      int       `json:"skipped_calendars"`
+	ClearedTelegramIDs    int       `json:"cleared_telegram_ids"`
+}
+
+type GroupDocumentDecoder interface {
+	Decode(data []byte) (*domain.ReaderGroup, error)
+}
+
+type ImportReaderGroupHandler struct {
+	repo     domain.RepositoryReaderGroup
+	decoder  GroupDocumentDecoder
+	auditLog domain.RepositoryAuditLog
+}
+
+func NewImportReaderGroupHandler(
+	repo domain.RepositoryReaderGroup,
+	decoder GroupDocumentDecoder,
+	auditLog domain.RepositoryAuditLog,
+) ImportReaderGroupHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if decoder == nil {
+		panic("nil decoder")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return ImportReaderGroupHandler{repo: repo, decoder: decoder, auditLog: auditLog}
+}
+
+// Handle checks the document the way the group would be checked when built in the application
+// and stores it according to the mode. A Telegram account of an imported reader that already
+// reads in another group fails the import, except for a copy, whose reader is imported without it.
+func (h ImportReaderGroupHandler) Handle(ctx context.Context, cmd ImportReaderGroup) (*ImportReaderGroupResult, error) {
+	mode, err := ParseImportMode(string(cmd.Mode))
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := h.decoder.Decode(cmd.Document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reader group document: %w", err)
+	}
+	group, err := importedGroup(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reader group document: %w", err)
+	}
+
+	switch mode {
+	case ImportRegenerateIDs:
+		if err := regenerateIDs(group); err != nil {
+			return nil, err
+		}
+		cleared, err := clearTakenTelegramIDs(ctx, h.repo, group)
+		if err != nil {
+			return nil, err
+		}
+		result, err := h.create(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		result.ClearedTelegramIDs = cleared
+		return result, nil
+	case ImportMerge:
+		return retryOnConflict(ctx, func() (*ImportReaderGroupResult, error) {
+			return h.merge(ctx, group)
+		})
+	default:
+		return h.create(ctx, group)
+	}
+}
+
+func (h ImportReaderGroupHandler) create(
+	ctx context.Context,
+	group *domain.ReaderGroup,
+) (*ImportReaderGroupResult, error) {
+	_, err := h.repo.GetByID(ctx, group.ID)
+	if err == nil {
+		return nil, GroupExistsError{GroupID: group.ID}
+	}
+	var notFound domain.ReaderGroupNotFoundError
+	if !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("failed to get reader group: %w", err)
+	}
+
+	if err := h.repo.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionImportGroup, group.ID, domain.DiffReaderGroups(nil, group))
+
+	return &ImportReaderGroupResult{
+		GroupID:               group.ID,
+		Created:               true,
+		AddedReaders:          len(group.Readers),
+		AddedNoReadingPeriods: len(group.NoReadingPeriods),
+		AddedCalendars:        len(group.Calendars),
+	}, nil
+}
+
+// merge keeps the settings and everything the stored group has. It adds the readers whose ID, number
+// and Telegram account are free, the no reading periods under new names and the calendars of the years
+// the group has no calendar for in their year mode.
+func (h ImportReaderGroupHandler) merge(
+	ctx context.Context,
+	imported *domain.ReaderGroup,
+) (*ImportReaderGroupResult, error) {
+	group, err := h.repo.GetByIDWithCalendars(ctx, imported.ID)
+	var notFound domain.ReaderGroupNotFoundError
+	if errors.As(err, &notFound) {
+		return h.create(ctx, imported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reader group: %w", err)
+	}
+	before := group.Snapshot()
+	result := &ImportReaderGroupResult{GroupID: group.ID}
+
+	for i := range imported.Readers {
+		if err := group.AddReader(&imported.Readers[i]); err != nil {
+			result.SkippedReaders++
+			continue
+		}
+		result.AddedReaders++
+	}
+
+	for _, period := range imported.NoReadingPeriods {
+		if hasNoReadingPeriodNamed(group, period.Name) || group.AddNoReadingPeriod(period) != nil {
+			result.SkippedPeriods++
+			continue
+		}
+		result.AddedNoReadingPeriods++
+	}
+
+	for _, calendar := range imported.Calendars {
+		if hasCalendar(group, calendar.Year, calendar.YearMode) || group.AddCalendar(calendar) != nil {
+			result.SkippedCalendars++
+			continue
+		}
+		result.AddedCalendars++
+	}
+
+	if result.AddedReaders+result.AddedNoReadingPeriods+result.AddedCalendars == 0 {
+		return result, nil
+	}
+	if err := h.repo.Update(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to update reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionImportGroup, group.ID, domain.DiffReaderGroups(before, group))
+	return result, nil
+}
+
+// importedGroup builds the decoded group again through the domain, so that a document is refused
+// for anything the application would not let a user do
+func importedGroup(decoded *domain.ReaderGroup) (*domain.ReaderGroup, error) {
+	group, err := domain.NewReaderGroup(decoded.Name, decoded.StartOffset)
+	if err != nil {
+		return nil, err
+	}
+	if err := group.UpdateSize(decoded.Size); err != nil {
+		return nil, err
+	}
+	if err := group.UpdateYearMode(decoded.YearMode); err != nil {
+		return nil, err
+	}
+	if err := group.UpdateLentRule(decoded.LentRule); err != nil {
+		return nil, err
+	}
+
+	group.NoReadingPeriods = make([]domain.NoReadingPeriod, 0, len(decoded.NoReadingPeriods))
+	for _, decodedPeriod := range decoded.NoReadingPeriods {
+		period, err := importedNoReadingPeriod(decodedPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("no reading period %q: %w", decodedPeriod.Name, err)
+		}
+		if err := group.AddNoReadingPeriod(*period); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range decoded.Readers {
+		if err := group.AddReader(&decoded.Readers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, calendar := range decoded.Calendars {
+		if calendar.YearMode != domain.CalendarYearCivil && calendar.YearMode != domain.CalendarYearChurch {
+			return nil, fmt.Errorf("calendar for year %d has unknown year mode %q", calendar.Year, calendar.YearMode)
+		}
+		if hasCalendar(group, calendar.Year, calendar.YearMode) {
+			return nil, fmt.Errorf("calendar for year %d is given twice", calendar.Year)
+		}
+		if err := calendar.Verify(); err != nil {
+			return nil, err
+		}
+		if err := group.AddCalendar(calendar); err != nil {
+			return nil, err
+		}
+	}
+
+	group.ID, group.CreatedAt, group.UpdatedAt = decoded.ID, decoded.CreatedAt, decoded.UpdatedAt
+	return group, nil
+}
+
+// importedNoReadingPeriod checks the period as the constructor of its anchor does and keeps its ID
+func importedNoReadingPeriod(decoded domain.NoReadingPeriod) (*domain.NoReadingPeriod, error) {
+	var period *domain.NoReadingPeriod
+	var err error
+	switch decoded.Anchor {
+	case domain.NoReadingAnchorPascha:
+		period, err = domain.NewPaschaNoReadingPeriod(decoded.Name, decoded.PaschaFrom, decoded.PaschaTo)
+	case domain.NoReadingAnchorFixed:
+		period, err = domain.NewFixedNoReadingPeriod(decoded.Name, decoded.FixedFrom, decoded.FixedTo)
+	default:
+		err = fmt.Errorf("unknown anchor %q", decoded.Anchor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	period.ID = decoded.ID
+	return period, nil
+}
+
+// clearTakenTelegramIDs clears the Telegram IDs of the readers of a copy that already read in another group
+// and returns how many were cleared. A Telegram account reads in one group only, the group it reads in now keeps it.
+func clearTakenTelegramIDs(ctx context.Context, repo domain.RepositoryReaderGroup, group *domain.ReaderGroup) (int, error) {
+	cleared := 0
+	for i := range group.Readers {
+		if group.Readers[i].TelegramID == 0 {
+			continue
+		}
+		_, err := repo.GetByTelegramID(ctx, group.Readers[i].TelegramID)
+		var notFound domain.TelegramReaderNotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return cleared, fmt.Errorf("failed to get reader group: %w", err)
+		}
+		group.Readers[i].TelegramID = 0
+		cleared++
+	}
+	return cleared, nil
+}
+
+// regenerateIDs gives the group and everything in it new IDs, so that it is stored as a copy
+func regenerateIDs(group *domain.ReaderGroup) error {
+	newID := func() (uuid.UUID, error) {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("failed to generate uuid7: %w", err)
+		}
+		return id, nil
+	}
+
+	var err error
+	if group.ID, err = newID(); err != nil {
+		return err
+	}
+	for i := range group.Readers {
+		if group.Readers[i].ID, err = newID(); err != nil {
+			return err
+		}
+	}
+	for i := range group.NoReadingPeriods {
+		if group.NoReadingPeriods[i].ID, err = newID(); err != nil {
+			return err
+		}
+	}
+	for i := range group.Calendars {
+		if group.Calendars[i].ID, err = newID(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasNoReadingPeriodNamed(group *domain.ReaderGroup, name string) bool {
+	for _, period := range group.NoReadingPeriods {
+		if period.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCalendar(group *domain.ReaderGroup, year int, yearMode domain.CalendarYearMode) bool {
+	for _, calendar := range group.Calendars {
+		if calendar.Year == year && calendar.YearMode == yearMode {
+			return true
+		}
+	}
+	return false
+}
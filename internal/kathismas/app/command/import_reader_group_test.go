@@ -0,0 +1,202 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/groupdoc"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportedGroup returns a group of two readers with a generated calendar for each of the years
+func newExportedGroup(t *testing.T, telegramID int64, years ...int) *domain.ReaderGroup {
+	t.Helper()
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.UpdateSize(2))
+	ivan, err := domain.NewPsalmReader("Иван", telegramID, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(ivan))
+	petr, err := domain.NewPsalmReader("Петр", 0, "", 2)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(petr))
+
+	generate := NewGenerateCalendarForGroupHandler(
+		newGroupRepoStub(group), &calendarGeneratorStub{}, memory.NewAuditLogRepository(),
+	)
+	for _, year := range years {
+		_, err := generate.Handle(context.Background(), GenerateCalendarForGroup{GroupID: group.ID, Year: year})
+		require.NoError(t, err)
+	}
+	return group
+}
+
+func encodeGroup(t *testing.T, group *domain.ReaderGroup) []byte {
+	t.Helper()
+	document, err := groupdoc.NewDocument().Encode(group)
+	require.NoError(t, err)
+	return document
+}
+
+func TestImportReaderGroupHandler_Keep(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewReaderGroupRepository()
+	auditLog := memory.NewAuditLogRepository()
+	handler := NewImportReaderGroupHandler(repo, groupdoc.NewDocument(), auditLog)
+	group := newExportedGroup(t, 1001, 2026)
+	document := encodeGroup(t, group)
+
+	result, err := handler.Handle(ctx, ImportReaderGroup{Document: document, Mode: ImportKeepIDs})
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReaderGroupResult{
+		GroupID:               group.ID,
+		Created:               true,
+		AddedReaders:          2,
+		AddedNoReadingPeriods: len(group.NoReadingPeriods),
+		AddedCalendars:        1,
+	}, result)
+
+	stored, err := repo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group.Readers[0].ID, stored.Readers[0].ID)
+	assert.Equal(t, group.Calendars[0].ID, stored.Calendars[0].ID)
+	assert.Equal(t, group.Calendars[0].Calendar, stored.Calendars[0].Calendar)
+
+	entries, err := auditLog.List(ctx, domain.AuditFilter{GroupID: group.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionImportGroup, entries[0].Action)
+
+	_, err = handler.Handle(ctx, ImportReaderGroup{Document: document, Mode: ImportKeepIDs})
+	var exists GroupExistsError
+	require.ErrorAs(t, err, &exists)
+	assert.Equal(t, group.ID, exists.GroupID)
+}
+
+func TestImportReaderGroupHandler_Regenerate(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewReaderGroupRepository()
+	handler := NewImportReaderGroupHandler(repo, groupdoc.NewDocument(), memory.NewAuditLogRepository())
+
+	group := newExportedGroup(t, 0, 2026)
+	require.NoError(t, repo.Create(ctx, group))
+
+	result, err := handler.Handle(ctx, ImportReaderGroup{Document: encodeGroup(t, group), Mode: ImportRegenerateIDs})
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+	assert.NotEqual(t, group.ID, result.GroupID)
+
+	copied, err := repo.GetByIDWithCalendars(ctx, result.GroupID)
+	require.NoError(t, err)
+	assert.Equal(t, group.Name, copied.Name)
+	require.Len(t, copied.Readers, 2)
+	assert.NotEqual(t, group.Readers[0].ID, copied.Readers[0].ID)
+	assert.Equal(t, group.Readers[0].Username, copied.Readers[0].Username)
+	require.Len(t, copied.Calendars, 1)
+	assert.NotEqual(t, group.Calendars[0].ID, copied.Calendars[0].ID)
+	assert.Equal(t, group.Calendars[0].Calendar, copied.Calendars[0].Calendar)
+
+	assert.Zero(t, result.ClearedTelegramIDs)
+
+	// a copy can not take the Telegram account of a reader along, the reader is copied without it
+	withTelegram := newExportedGroup(t, 1001)
+	require.NoError(t, repo.Create(ctx, withTelegram))
+	result, err = handler.Handle(ctx, ImportReaderGroup{Document: encodeGroup(t, withTelegram), Mode: ImportRegenerateIDs})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ClearedTelegramIDs)
+
+	copied, err = repo.GetByID(ctx, result.GroupID)
+	require.NoError(t, err)
+	require.Len(t, copied.Readers, 2)
+	assert.Zero(t, copied.Readers[0].TelegramID)
+	reader, err := repo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, withTelegram.ID, reader.ID, "the stored group keeps the Telegram account")
+}
+
+func TestImportReaderGroupHandler_Merge(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewReaderGroupRepository()
+	handler := NewImportReaderGroupHandler(repo, groupdoc.NewDocument(), memory.NewAuditLogRepository())
+
+	exported := newExportedGroup(t, 1001, 2026, 2027)
+	stored := exported.Snapshot()
+	stored.Readers = stored.Readers[:1]
+	stored.Calendars = stored.Calendars[:1]
+	require.NoError(t, stored.UpdateName("Renamed"))
+	require.NoError(t, repo.Create(ctx, stored))
+
+	result, err := handler.Handle(ctx, ImportReaderGroup{Document: encodeGroup(t, exported), Mode: ImportMerge})
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReaderGroupResult{
+		GroupID:          exported.ID,
+		AddedReaders:     1,
+		AddedCalendars:   1,
+		SkippedReaders:   1,
+		SkippedPeriods:   len(exported.NoReadingPeriods),
+		SkippedCalendars: 1,
+	}, result)
+
+	merged, err := repo.GetByIDWithCalendars(ctx, exported.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", merged.Name, "the settings of the stored group win")
+	assert.Len(t, merged.Readers, 2)
+	assert.Len(t, merged.Calendars, 2)
+
+	// a group that is not stored is created
+	other := newExportedGroup(t, 0)
+	result, err = handler.Handle(ctx, ImportReaderGroup{Document: encodeGroup(t, other), Mode: ImportMerge})
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+}
+
+func TestImportReaderGroupHandler_InvalidDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(group *domain.ReaderGroup)
+		errContains string
+	}{
+		{
+			name:        "reader outside of the group",
+			change:      func(group *domain.ReaderGroup) { group.Readers[1].ReaderNumber = 3 },
+			errContains: "reader number must be between 1 and 2",
+		},
+		{
+			name:        "broken calendar",
+			change:      func(group *domain.ReaderGroup) { group.Calendars[0].Calendar[1][1] = []int{21} },
+			errContains: "calendar for year 2026 has",
+		},
+		{
+			name:        "unknown lent rule",
+			change:      func(group *domain.ReaderGroup) { group.LentRule = "never" },
+			errContains: "unknown lent reading rule",
+		},
+		{
+			name: "unknown anchor",
+			change: func(group *domain.ReaderGroup) {
+				group.NoReadingPeriods[0].Anchor = "moon"
+			},
+			errContains: "unknown anchor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewReaderGroupRepository()
+			group := newExportedGroup(t, 0, 2026)
+			tt.change(group)
+
+			_, err := NewImportReaderGroupHandler(repo, groupdoc.NewDocument(), memory.NewAuditLogRepository()).
+				Handle(context.Background(), ImportReaderGroup{Document: encodeGroup(t, group)})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid reader group document")
+			assert.Contains(t, err.Error(), tt.errContains)
+
+			_, err = repo.GetByID(context.Background(), group.ID)
+			var notFound domain.ReaderGroupNotFoundError
+			assert.ErrorAs(t, err, &notFound)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+type PurgeTrash struct {
+	// Now is the time the expiry is checked at, zero means the current time
+	Now time.Time
+}
+
+type PurgeTrashHandler struct {
+	trash    domain.RepositoryTrash
+	auditLog domain.RepositoryAuditLog
+}
+
+func NewPurgeTrashHandler(trash domain.RepositoryTrash, auditLog domain.RepositoryAuditLog) PurgeTrashHandler {
+	if trash == nil {
+		panic("nil trash")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return PurgeTrashHandler{trash: trash, auditLog: auditLog}
+}
+
+// Handle deletes the expired items for good and returns how many were deleted
+func (h PurgeTrashHandler) Handle(ctx context.Context, cmd PurgeTrash) (int, error) {
+	now := cmd.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	items, err := h.trash.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	purged := 0
+	for _, item := range items {
+		if !item.IsExpired(now) {
+			continue
+		}
+		if err := h.trash.Delete(ctx, item.ID); err != nil {
+			return purged, fmt.Errorf("failed to purge trash item: %w", err)
+		}
+		purged++
+		recordAudit(ctx, h.auditLog, domain.AuditActionPurgeTrash, item.GroupID, []domain.AuditChange{
+			{Field: string(item.Kind), Before: trashItemTitle(item)},
+		})
+	}
+	return purged, nil
+}
+
+func trashItemTitle(item domain.TrashItem) string {
+	if item.Reader != nil {
+		return fmt.Sprintf("%d. %s", item.Reader.ReaderNumber, item.Reader.Username)
+	}
+	return item.GroupName
+}
@@ -15,65 +15,145 @@ import (
 type RegenerateCalendarForGroup struct {
 	GroupID uuid.UUID
 	Year    int
+	// Cascade regenerates the later stored years whose start offset no longer follows the regenerated one
+	Cascade bool
+}
+
+// CalendarChange reports how regeneration affected the calendar of one year
+type CalendarChange struct {
+	Year           int  `json:"year"`
+	OldStartOffset int  `json:"old_start_offset"`
+	NewStartOffset int  `json:"new_start_offset"`
+	ChangedDays    int  `json:"changed_days"`
+	Regenerated    bool `json:"regenerated"`
+}
+
+type RegenerateCalendarResult struct {
+	Buffer *bytes.Buffer
+	// Changes starts with the regenerated year, followed by the later years that went out of step
+	Changes []CalendarChange
 }
 
 type RegenerateCalendarForGroupHandler struct {
 	groupRepo domain.RepositoryReaderGroup
-	generator CalendarGenerator
+	generator CalendarRangeGenerator
+	auditLog  domain.RepositoryAuditLog
 }
 
 func NewRegenerateCalendarForGroupHandler(
 	groupRepo domain.RepositoryReaderGroup,
-	generator CalendarGenerator,
+	generator CalendarRangeGenerator,
+	auditLog domain.RepositoryAuditLog,
 ) RegenerateCalendarForGroupHandler {
-	if groupRepo == nil || generator == nil {
-		slog.Error("not found group repo or generator calendar in NewRegenerateCalendarForGroupHandler")
+	if groupRepo == nil || generator == nil || auditLog == nil {
+		slog.Error("not found group repo, generator calendar or audit log in NewRegenerateCalendarForGroupHandler")
 		os.Exit(1)
 	}
 	return RegenerateCalendarForGroupHandler{
 		groupRepo: groupRepo,
 		generator: generator,
+		auditLog:  auditLog,
 	}
 }
 
-func (h RegenerateCalendarForGroupHandler) Handle(ctx context.Context, cmd RegenerateCalendarForGroup) (*bytes.Buffer, error) {
-	group, err := h.groupRepo.GetByID(ctx, cmd.GroupID)
+func (h RegenerateCalendarForGroupHandler) Handle(
+	ctx context.Context,
+	cmd RegenerateCalendarForGroup,
+) (*RegenerateCalendarResult, error) {
+	return retryOnConflict(ctx, func() (*RegenerateCalendarResult, error) {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h RegenerateCalendarForGroupHandler) handle(
+	ctx context.Context,
+	cmd RegenerateCalendarForGroup,
+) (*RegenerateCalendarResult, error) {
+	group, err := h.groupRepo.GetByIDWithCalendars(ctx, cmd.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reader group: %w", err)
 	}
+	before := group.Snapshot()
 
 	year := cmd.Year
 	if year == 0 {
-		year = time.Now().Year()
+		year = group.YearMode.YearOf(time.Now())
 	}
 
-	removed := group.RemoveCalendarsByYear(year)
-	slog.Info("removed calendars for regeneration", "year", year, "count", removed)
-
-	startOffset := h.calculateStartOffset(group, year)
-	buffer, calendarData, err := h.generator.GenerateForGroup(year, startOffset)
+	calendar, change, err := h.regenerateYear(group, year)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate calendar: %w", err)
+		return nil, err
 	}
+	changes := []CalendarChange{change}
 
-	calendar := domain.NewCalendarOfReader(year, startOffset, calendarData)
+	// later years were derived from this one, walk them while they are stored one after another
+	for next := year + 1; group.CalendarForYear(next) != nil; next++ {
+		stored := group.CalendarForYear(next)
+		expected := group.NextStartOffset(next)
+		if stored.StartOffset == expected {
+			break
+		}
+		if !cmd.Cascade {
+			changes = append(changes, CalendarChange{
+				Year:           next,
+				OldStartOffset: stored.StartOffset,
+				NewStartOffset: expected,
+			})
+			break
+		}
+		_, nextChange, err := h.regenerateYear(group, next)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, nextChange)
+	}
+	for _, c := range changes {
+		slog.Info("calendar regeneration", "year", c.Year, "old_start_offset", c.OldStartOffset,
+			"new_start_offset", c.NewStartOffset, "changed_days", c.ChangedDays, "regenerated", c.Regenerated)
+	}
 
-	if err := group.AddCalendar(*calendar); err != nil {
-		return nil, fmt.Errorf("failed to add calendar to group: %w", err)
+	buffer, err := h.generator.RenderForGroup([]domain.CalendarOfReader{*calendar})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate calendar: %w", err)
 	}
 
 	if err := h.groupRepo.Update(ctx, group); err != nil {
 		return nil, fmt.Errorf("failed to update reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRegenerateCalendar, group.ID, domain.DiffReaderGroups(before, group))
 
-	return buffer, nil
+	return &RegenerateCalendarResult{Buffer: buffer, Changes: changes}, nil
 }
 
-func (h RegenerateCalendarForGroupHandler) calculateStartOffset(group *domain.ReaderGroup, year int) int {
-	for _, cal := range group.Calendars {
-		if cal.Year == year-1 {
-			return cal.CalculateNextStartOffset()
-		}
+// regenerateYear replaces the calendar of the year with a new one continuing the previous year
+func (h RegenerateCalendarForGroupHandler) regenerateYear(
+	group *domain.ReaderGroup,
+	year int,
+) (*domain.CalendarOfReader, CalendarChange, error) {
+	change := CalendarChange{Year: year, Regenerated: true}
+	var oldCalendar domain.CalendarMap
+	if old := group.CalendarForYear(year); old != nil {
+		change.OldStartOffset = old.StartOffset
+		oldCalendar = old.Calendar
+	}
+
+	removed := group.RemoveCalendarsByYear(year)
+	slog.Info("removed calendars for regeneration", "year", year, "count", removed)
+
+	startOffset := group.NextStartOffset(year)
+	calendarData := h.generator.CalculateForGroup(
+		year, startOffset, group.Size, group.YearMode, group.LentRule, group.NoReadingPeriods,
+	)
+	calendar := domain.NewCalendarOfReader(year, group.YearMode, startOffset, calendarData)
+	if err := calendar.Verify(); err != nil {
+		return nil, change, fmt.Errorf("generated calendar is invalid: %w", err)
 	}
-	return group.StartOffset
+
+	if err := group.AddCalendar(*calendar); err != nil {
+		return nil, change, fmt.Errorf("failed to add calendar to group: %w", err)
+	}
+
+	change.NewStartOffset = startOffset
+	change.ChangedDays = calendarData.ChangedDays(oldCalendar)
+	return calendar, change, nil
 }
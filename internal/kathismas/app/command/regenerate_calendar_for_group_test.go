@@ -0,0 +1,111 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGroupWithYears returns a group with consecutive calendars for 2026-2029 whose first year
+// is then shifted by an extra no reading day, so that the later years go out of step on regeneration
+func newGroupWithYears(t *testing.T) *domain.ReaderGroup {
+	t.Helper()
+	group, _ := domain.NewReaderGroup("Test Group", 5)
+	generator := &calendarGeneratorStub{}
+	_, err := NewGenerateCalendarRangeForGroupHandler(
+		newGroupRepoStub(group), generator, memory.NewAuditLogRepository(),
+	).Handle(context.Background(), GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2029})
+	require.NoError(t, err)
+
+	pentecost, err := domain.NewPaschaNoReadingPeriod("Пятидесятница", 49, 49)
+	require.NoError(t, err)
+	require.NoError(t, group.AddNoReadingPeriod(*pentecost))
+	return group
+}
+
+func TestRegenerateCalendarForGroupHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name            string
+		cascade         bool
+		wantRegenerated []int
+		wantReported    []int
+		wantDrifts      []int
+	}{
+		{
+			name:            "without cascade later years are only reported",
+			cascade:         false,
+			wantRegenerated: []int{2026},
+			wantReported:    []int{2027},
+			wantDrifts:      []int{2027},
+		},
+		{
+			name:            "cascade regenerates every later year",
+			cascade:         true,
+			wantRegenerated: []int{2026, 2027, 2028, 2029},
+			wantReported:    []int{},
+			wantDrifts:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := newGroupWithYears(t)
+			before := group.CalendarForYear(2027).StartOffset
+			repo := newGroupRepoStub(group)
+			handler := NewRegenerateCalendarForGroupHandler(
+				repo, &calendarGeneratorStub{}, memory.NewAuditLogRepository(),
+			)
+
+			result, err := handler.Handle(context.Background(), RegenerateCalendarForGroup{
+				Year:    2026,
+				Cascade: tt.cascade,
+			})
+			require.NoError(t, err)
+			require.Len(t, repo.UpdateCalls(), 1)
+
+			regenerated, reported := []int{}, []int{}
+			for _, change := range result.Changes {
+				if change.Regenerated {
+					regenerated = append(regenerated, change.Year)
+					assert.Positive(t, change.ChangedDays, "year %d", change.Year)
+					continue
+				}
+				reported = append(reported, change.Year)
+				assert.Equal(t, before, change.OldStartOffset)
+				assert.NotEqual(t, change.OldStartOffset, change.NewStartOffset)
+			}
+			assert.Equal(t, tt.wantRegenerated, regenerated)
+			assert.Equal(t, tt.wantReported, reported)
+
+			var drifts []int
+			for _, drift := range group.FindCalendarDrifts() {
+				drifts = append(drifts, drift.Year)
+			}
+			assert.Equal(t, tt.wantDrifts, drifts)
+			assert.Len(t, group.Calendars, 4)
+		})
+	}
+}
+
+func TestRegenerateCalendarForGroupHandler_StopsAtConsistentYear(t *testing.T) {
+	group, _ := domain.NewReaderGroup("Test Group", 5)
+	_, err := NewGenerateCalendarRangeForGroupHandler(
+		newGroupRepoStub(group), &calendarGeneratorStub{}, memory.NewAuditLogRepository(),
+	).Handle(context.Background(), GenerateCalendarRangeForGroup{FromYear: 2026, ToYear: 2028})
+	require.NoError(t, err)
+
+	handler := NewRegenerateCalendarForGroupHandler(
+		newGroupRepoStub(group), &calendarGeneratorStub{}, memory.NewAuditLogRepository(),
+	)
+	result, err := handler.Handle(context.Background(), RegenerateCalendarForGroup{Year: 2026, Cascade: true})
+	require.NoError(t, err)
+
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, 2026, result.Changes[0].Year)
+	assert.Equal(t, result.Changes[0].OldStartOffset, result.Changes[0].NewStartOffset)
+	assert.Zero(t, result.Changes[0].ChangedDays)
+}
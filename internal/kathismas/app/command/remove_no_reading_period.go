@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type RemoveNoReadingPeriod struct {
+	GroupID  uuid.UUID
+	PeriodID uuid.UUID
+}
+
+type RemoveNoReadingPeriodHandler struct {
+	groupRepo domain.RepositoryReaderGroup
+	auditLog  domain.RepositoryAuditLog
+}
+
+func NewRemoveNoReadingPeriodHandler(
+	groupRepo domain.RepositoryReaderGroup,
+	auditLog domain.RepositoryAuditLog,
+) RemoveNoReadingPeriodHandler {
+	if groupRepo == nil {
+		panic("nil groupRepo")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return RemoveNoReadingPeriodHandler{groupRepo: groupRepo, auditLog: auditLog}
+}
+
+func (h RemoveNoReadingPeriodHandler) Handle(ctx context.Context, cmd RemoveNoReadingPeriod) error {
+	return retryUpdateOnConflict(ctx, func() error {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h RemoveNoReadingPeriodHandler) handle(ctx context.Context, cmd RemoveNoReadingPeriod) error {
+	group, err := h.groupRepo.GetByID(ctx, cmd.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to get reader group: %w", err)
+	}
+	before := group.Snapshot()
+
+	if err := group.RemoveNoReadingPeriod(cmd.PeriodID); err != nil {
+		return fmt.Errorf("failed to remove no reading period from group: %w", err)
+	}
+
+	if err := h.groupRepo.Update(ctx, group); err != nil {
+		return fmt.Errorf("failed to update reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRemoveNoReadingPeriod, group.ID, domain.DiffReaderGroups(before, group))
+
+	return nil
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/gofrs/uuid/v5"
@@ -13,30 +14,71 @@ type RemoveReaderFromGroup struct {
 	ReaderID uuid.UUID
 }
 
+// RemoveReaderFromGroupHandler moves the reader to the trash, where it stays restorable for the retention period
 type RemoveReaderFromGroupHandler struct {
 	readerGroupRepo domain.RepositoryReaderGroup
+	trash           domain.RepositoryTrash
+	auditLog        domain.RepositoryAuditLog
+	retention       time.Duration
 }
 
-func NewRemoveReaderFromGroupHandler(readerGroupRepo domain.RepositoryReaderGroup) RemoveReaderFromGroupHandler {
+func NewRemoveReaderFromGroupHandler(
+	readerGroupRepo domain.RepositoryReaderGroup,
+	trash domain.RepositoryTrash,
+	auditLog domain.RepositoryAuditLog,
+	retention time.Duration,
+) RemoveReaderFromGroupHandler {
 	if readerGroupRepo == nil {
 		panic("nil readerGroupRepo")
 	}
-	return RemoveReaderFromGroupHandler{readerGroupRepo: readerGroupRepo}
+	if trash == nil {
+		panic("nil trash")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return RemoveReaderFromGroupHandler{
+		readerGroupRepo: readerGroupRepo,
+		trash:           trash,
+		auditLog:        auditLog,
+		retention:       retention,
+	}
 }
 
 func (h RemoveReaderFromGroupHandler) Handle(ctx context.Context, cmd RemoveReaderFromGroup) error {
+	return retryUpdateOnConflict(ctx, func() error {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h RemoveReaderFromGroupHandler) handle(ctx context.Context, cmd RemoveReaderFromGroup) error {
 	group, err := h.readerGroupRepo.GetByID(ctx, cmd.GroupID)
 	if err != nil {
 		return fmt.Errorf("failed to get reader group: %w", err)
 	}
+	before := group.Snapshot()
+
+	reader, err := group.GetReader(cmd.ReaderID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reader from group: %w", err)
+	}
+	item, err := domain.NewReaderTrashItem(group, *reader, h.retention)
+	if err != nil {
+		return fmt.Errorf("failed to move reader to trash: %w", err)
+	}
 
 	if err := group.RemoveReader(cmd.ReaderID); err != nil {
 		return fmt.Errorf("failed to remove reader from group: %w", err)
 	}
 
+	if err := h.trash.Put(ctx, item); err != nil {
+		return fmt.Errorf("failed to move reader to trash: %w", err)
+	}
 	if err := h.readerGroupRepo.Update(ctx, group); err != nil {
+		discardTrashItem(ctx, h.trash, item.ID)
 		return fmt.Errorf("failed to update reader group: %w", err)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRemoveReader, group.ID, domain.DiffReaderGroups(before, group))
 
 	return nil
 }
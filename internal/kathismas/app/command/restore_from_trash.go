@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type RestoreFromTrash struct {
+	ItemID uuid.UUID
+}
+
+type RestoreFromTrashHandler struct {
+	groupRepo domain.RepositoryReaderGroup
+	trash     domain.RepositoryTrash
+	auditLog  domain.RepositoryAuditLog
+}
+
+func NewRestoreFromTrashHandler(
+	groupRepo domain.RepositoryReaderGroup,
+	trash domain.RepositoryTrash,
+	auditLog domain.RepositoryAuditLog,
+) RestoreFromTrashHandler {
+	if groupRepo == nil {
+		panic("nil groupRepo")
+	}
+	if trash == nil {
+		panic("nil trash")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return RestoreFromTrashHandler{groupRepo: groupRepo, trash: trash, auditLog: auditLog}
+}
+
+// Handle puts a group back under its old ID, or a reader back into its group under the old number.
+// The item stays in the trash when the ID, the number or the Telegram account has been taken since.
+func (h RestoreFromTrashHandler) Handle(ctx context.Context, cmd RestoreFromTrash) error {
+	item, err := h.trash.GetByID(ctx, cmd.ItemID)
+	if err != nil {
+		return fmt.Errorf("failed to get trash item: %w", err)
+	}
+
+	switch item.Kind {
+	case domain.TrashKindGroup:
+		err = h.restoreGroup(ctx, item)
+	case domain.TrashKindReader:
+		err = retryUpdateOnConflict(ctx, func() error {
+			return h.restoreReader(ctx, item)
+		})
+	default:
+		err = fmt.Errorf("unknown trash item kind %q", item.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := h.trash.Delete(ctx, item.ID); err != nil {
+		return fmt.Errorf("failed to remove restored item from trash: %w", err)
+	}
+	return nil
+}
+
+func (h RestoreFromTrashHandler) restoreGroup(ctx context.Context, item *domain.TrashItem) error {
+	if err := h.groupRepo.Create(ctx, item.Group); err != nil {
+		return fmt.Errorf("failed to restore reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRestoreGroup, item.GroupID, domain.DiffReaderGroups(nil, item.Group))
+	return nil
+}
+
+func (h RestoreFromTrashHandler) restoreReader(ctx context.Context, item *domain.TrashItem) error {
+	group, err := h.groupRepo.GetByID(ctx, item.GroupID)
+	var notFound domain.ReaderGroupNotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("group %q of the reader is deleted, restore the group first", item.GroupName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get reader group: %w", err)
+	}
+	before := group.Snapshot()
+
+	if err := group.AddReader(item.Reader); err != nil {
+		return fmt.Errorf("failed to restore reader: %w", err)
+	}
+	if err := h.groupRepo.Update(ctx, group); err != nil {
+		return fmt.Errorf("failed to update reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRestoreReader, group.ID, domain.DiffReaderGroups(before, group))
+	return nil
+}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type trashTest struct {
+	groupRepo *memory.ReaderGroupRepository
+	trash     *memory.TrashRepository
+	auditLog  *memory.AuditLogRepository
+}
+
+func newTrashTest() trashTest {
+	return trashTest{
+		groupRepo: memory.NewReaderGroupRepository(),
+		trash:     memory.NewTrashRepository(),
+		auditLog:  memory.NewAuditLogRepository(),
+	}
+}
+
+func (tt trashTest) createGroup(t *testing.T) *domain.ReaderGroup {
+	t.Helper()
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	reader, err := domain.NewPsalmReader("Иван", 1001, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, group.AddReader(reader))
+	require.NoError(t, group.AddCalendar(*domain.NewCalendarOfReader(2026, domain.CalendarYearCivil, 1,
+		domain.CalendarMap{1: {1: {1}}})))
+	require.NoError(t, tt.groupRepo.Create(context.Background(), group))
+	return group
+}
+
+func (tt trashTest) onlyItem(t *testing.T) domain.TrashItem {
+	t.Helper()
+	items, err := tt.trash.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	return items[0]
+}
+
+func (tt trashTest) restore(item domain.TrashItem) error {
+	return NewRestoreFromTrashHandler(tt.groupRepo, tt.trash, tt.auditLog).
+		Handle(context.Background(), RestoreFromTrash{ItemID: item.ID})
+}
+
+func TestRestoreFromTrashHandler_Group(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+	group := tt.createGroup(t)
+
+	err := NewDeleteReaderGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(ctx, DeleteReaderGroup{GroupID: group.ID})
+	require.NoError(t, err)
+	_, err = tt.groupRepo.GetByID(ctx, group.ID)
+	require.ErrorAs(t, err, &domain.ReaderGroupNotFoundError{})
+
+	item := tt.onlyItem(t)
+	assert.Equal(t, domain.TrashKindGroup, item.Kind)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), item.ExpiresAt, time.Minute)
+
+	require.NoError(t, tt.restore(item))
+
+	restored, err := tt.groupRepo.GetByIDWithCalendars(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group.Name, restored.Name)
+	require.Len(t, restored.Readers, 1)
+	assert.Equal(t, group.Readers[0].ID, restored.Readers[0].ID)
+	require.Len(t, restored.Calendars, 1)
+	assert.Equal(t, group.Calendars[0].ID, restored.Calendars[0].ID)
+
+	// the reader is found by the Telegram account again
+	byTelegram, err := tt.groupRepo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, byTelegram.ID)
+
+	_, err = tt.trash.GetByID(ctx, item.ID)
+	require.ErrorAs(t, err, &domain.TrashItemNotFoundError{})
+}
+
+func TestRestoreFromTrashHandler_Reader(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+	group := tt.createGroup(t)
+	reader := group.Readers[0]
+
+	err := NewRemoveReaderFromGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(ctx, RemoveReaderFromGroup{GroupID: group.ID, ReaderID: reader.ID})
+	require.NoError(t, err)
+	item := tt.onlyItem(t)
+	assert.Equal(t, domain.TrashKindReader, item.Kind)
+	assert.Equal(t, group.ID, item.GroupID)
+
+	// the number was taken in the meantime, the reader stays in the trash
+	err = NewAddReaderToGroupHandler(tt.groupRepo, tt.auditLog).
+		Handle(ctx, AddReaderToGroup{GroupID: group.ID, ReaderNumber: reader.ReaderNumber, Username: "Петр"})
+	require.NoError(t, err)
+	require.ErrorContains(t, tt.restore(item), "already taken")
+	tt.onlyItem(t)
+
+	stored, err := tt.groupRepo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.NoError(t, stored.RemoveReader(stored.Readers[0].ID))
+	require.NoError(t, tt.groupRepo.Update(ctx, stored))
+
+	require.NoError(t, tt.restore(item))
+	restored, err := tt.groupRepo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, restored.Readers, 1)
+	assert.Equal(t, reader.ID, restored.Readers[0].ID)
+
+	entries, err := tt.auditLog.List(ctx, domain.AuditFilter{Action: domain.AuditActionRestoreReader})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestRestoreFromTrashHandler_ReaderOfDeletedGroup(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+	group := tt.createGroup(t)
+
+	err := NewRemoveReaderFromGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(ctx, RemoveReaderFromGroup{GroupID: group.ID, ReaderID: group.Readers[0].ID})
+	require.NoError(t, err)
+	readerItem := tt.onlyItem(t)
+	require.NoError(t, NewDeleteReaderGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(ctx, DeleteReaderGroup{GroupID: group.ID}))
+
+	require.ErrorContains(t, tt.restore(readerItem), "restore the group first")
+}
+
+func TestPurgeTrashHandler(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+	group := tt.createGroup(t)
+
+	err := NewRemoveReaderFromGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(ctx, RemoveReaderFromGroup{GroupID: group.ID, ReaderID: group.Readers[0].ID})
+	require.NoError(t, err)
+	require.NoError(t, NewDeleteReaderGroupHandler(tt.groupRepo, tt.trash, tt.auditLog, 2*time.Hour).
+		Handle(ctx, DeleteReaderGroup{GroupID: group.ID}))
+
+	purge := NewPurgeTrashHandler(tt.trash, tt.auditLog)
+	purged, err := purge.Handle(ctx, PurgeTrash{})
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = purge.Handle(ctx, PurgeTrash{Now: time.Now().Add(90 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, domain.TrashKindGroup, tt.onlyItem(t).Kind)
+
+	entries, err := tt.auditLog.List(ctx, domain.AuditFilter{Action: domain.AuditActionPurgeTrash})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []domain.AuditChange{{Field: "reader", Before: "1. Иван"}}, entries[0].Changes)
+}
@@ -0,0 +1,197 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+// RestoreGroupMode tells where a group from a backup goes
+type RestoreGroupMode string
+
+const (
+	// RestoreOverwrite puts the group from the backup in place of the stored one, which goes to the trash
+	RestoreOverwrite RestoreGroupMode = "overwrite"
+	// RestoreAsCopy stores the group from the backup under new IDs next to the stored one
+	RestoreAsCopy RestoreGroupMode = "copy"
+)
+
+// ParseRestoreGroupMode returns the mode by its name, an empty name overwrites
+func ParseRestoreGroupMode(value string) (RestoreGroupMode, error) {
+	switch mode := RestoreGroupMode(value); mode {
+	case RestoreOverwrite, RestoreAsCopy:
+		return mode, nil
+	case "":
+		return RestoreOverwrite, nil
+	default:
+		return "", fmt.Errorf("unknown restore mode %q, expected overwrite or copy", value)
+	}
+}
+
+// RestoreGroupFromBackup carries a group read from a backup with its calendars.
+// A dry run only tells what the restore would change.
+type RestoreGroupFromBackup struct {
+	Group  *domain.ReaderGroup
+	Mode   RestoreGroupMode
+	DryRun bool
+}
+
+// RestoreGroupFromBackupResult tells what the restore changed in the group stored under GroupID
+type RestoreGroupFromBackupResult struct {
+	GroupID uuid.UUID
+	Created bool
+	Changes []domain.AuditChange
+	// ClearedTelegramIDs counts the readers of a copy left without the Telegram account
+	// that reads in another group
+	ClearedTelegramIDs int
+}
+
+// RestoreGroupFromBackupHandler restores one group from a backup without touching the other groups
+type RestoreGroupFromBackupHandler struct {
+	repo      domain.RepositoryReaderGroup
+	trash     domain.RepositoryTrash
+	auditLog  domain.RepositoryAuditLog
+	retention time.Duration
+}
+
+func NewRestoreGroupFromBackupHandler(
+	repo domain.RepositoryReaderGroup,
+	trash domain.RepositoryTrash,
+	auditLog domain.RepositoryAuditLog,
+	retention time.Duration,
+) RestoreGroupFromBackupHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if trash == nil {
+		panic("nil trash")
+	}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return RestoreGroupFromBackupHandler{repo: repo, trash: trash, auditLog: auditLog, retention: retention}
+}
+
+// Handle checks the group the way an imported one is checked. An overwrite moves the stored group to the trash,
+// so it can be brought back, and creates the group when it is not stored any more.
+func (h RestoreGroupFromBackupHandler) Handle(
+	ctx context.Context,
+	cmd RestoreGroupFromBackup,
+) (*RestoreGroupFromBackupResult, error) {
+	mode, err := ParseRestoreGroupMode(string(cmd.Mode))
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Group == nil {
+		return nil, errors.New("nil group")
+	}
+	group, err := importedGroup(cmd.Group)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reader group %s in backup: %w", cmd.Group.ID, err)
+	}
+
+	if mode == RestoreAsCopy {
+		return h.restoreAsCopy(ctx, group, cmd.DryRun)
+	}
+	return h.overwrite(ctx, group, cmd.DryRun)
+}
+
+func (h RestoreGroupFromBackupHandler) restoreAsCopy(
+	ctx context.Context,
+	group *domain.ReaderGroup,
+	dryRun bool,
+) (*RestoreGroupFromBackupResult, error) {
+	if err := regenerateIDs(group); err != nil {
+		return nil, err
+	}
+	cleared, err := clearTakenTelegramIDs(ctx, h.repo, group)
+	if err != nil {
+		return nil, err
+	}
+	result := &RestoreGroupFromBackupResult{GroupID: group.ID, Created: true, ClearedTelegramIDs: cleared}
+
+	result.Changes = domain.DiffReaderGroups(nil, group)
+	if dryRun {
+		return result, nil
+	}
+	if err := h.repo.Create(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRestoreBackupGroup, group.ID, result.Changes)
+	return result, nil
+}
+
+func (h RestoreGroupFromBackupHandler) overwrite(
+	ctx context.Context,
+	group *domain.ReaderGroup,
+	dryRun bool,
+) (*RestoreGroupFromBackupResult, error) {
+	stored, err := h.repo.GetByIDWithCalendars(ctx, group.ID)
+	var notFound domain.ReaderGroupNotFoundError
+	if errors.As(err, &notFound) {
+		stored, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reader group: %w", err)
+	}
+
+	result := &RestoreGroupFromBackupResult{
+		GroupID: group.ID,
+		Created: stored == nil,
+		Changes: domain.DiffReaderGroups(stored, group),
+	}
+	if dryRun {
+		return result, nil
+	}
+	if stored != nil && len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	var trashItemID uuid.UUID
+	if stored != nil {
+		if trashItemID, err = h.moveToTrash(ctx, stored); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.repo.Create(ctx, group); err != nil {
+		if stored != nil {
+			h.putBack(ctx, stored, trashItemID)
+		}
+		return nil, fmt.Errorf("failed to save reader group: %w", err)
+	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionRestoreBackupGroup, group.ID, result.Changes)
+	return result, nil
+}
+
+// moveToTrash deletes the stored group the way DeleteReaderGroupHandler does, without an audit entry
+// of its own: the restore records the changes
+func (h RestoreGroupFromBackupHandler) moveToTrash(ctx context.Context, stored *domain.ReaderGroup) (uuid.UUID, error) {
+	item, err := domain.NewGroupTrashItem(stored, h.retention)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to move reader group to trash: %w", err)
+	}
+	if err := h.trash.Put(ctx, item); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to move reader group to trash: %w", err)
+	}
+	if err := h.repo.Delete(ctx, stored.ID); err != nil {
+		discardTrashItem(ctx, h.trash, item.ID)
+		return uuid.Nil, fmt.Errorf("failed to delete reader group: %w", err)
+	}
+	return item.ID, nil
+}
+
+// putBack stores the group an overwrite failed to replace again and takes it out of the trash.
+// When that fails too, the group stays restorable from the trash.
+func (h RestoreGroupFromBackupHandler) putBack(ctx context.Context, stored *domain.ReaderGroup, trashItemID uuid.UUID) {
+	if err := h.repo.Create(ctx, stored); err != nil {
+		slog.Error("failed to put back reader group, restore it from the trash",
+			"group_id", stored.ID, "error", err)
+		return
+	}
+	discardTrashItem(ctx, h.trash, trashItemID)
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (tt trashTest) restoreFromBackup(
+	group *domain.ReaderGroup,
+	mode RestoreGroupMode,
+	dryRun bool,
+) (*RestoreGroupFromBackupResult, error) {
+	return NewRestoreGroupFromBackupHandler(tt.groupRepo, tt.trash, tt.auditLog, time.Hour).
+		Handle(context.Background(), RestoreGroupFromBackup{Group: group, Mode: mode, DryRun: dryRun})
+}
+
+func TestRestoreGroupFromBackupHandler_Overwrite(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+
+	stored := newExportedGroup(t, 1001, 2026, 2027)
+	require.NoError(t, tt.groupRepo.Create(ctx, stored))
+	backedUp := stored.Snapshot()
+	backedUp.Calendars = backedUp.Calendars[:1]
+	require.NoError(t, backedUp.UpdateName("Old Name"))
+
+	result, err := tt.restoreFromBackup(backedUp, RestoreOverwrite, true)
+	require.NoError(t, err)
+	assert.False(t, result.Created)
+	assert.Equal(t, []string{"name", "calendar 2027"}, changedFields(result.Changes))
+	unchanged, err := tt.groupRepo.GetByIDWithCalendars(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Len(t, unchanged.Calendars, 2, "a dry run changes nothing")
+
+	_, err = tt.restoreFromBackup(backedUp, RestoreOverwrite, false)
+	require.NoError(t, err)
+
+	restored, err := tt.groupRepo.GetByIDWithCalendars(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Old Name", restored.Name)
+	assert.Len(t, restored.Calendars, 1, "calendars the backup lacks are gone")
+	assert.Len(t, restored.Readers, 2)
+
+	item := tt.onlyItem(t)
+	assert.Equal(t, stored.ID, item.GroupID)
+	assert.Len(t, item.Group.Calendars, 2, "the overwritten group waits in the trash")
+
+	entries, err := tt.auditLog.List(ctx, domain.AuditFilter{GroupID: stored.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditActionRestoreBackupGroup, entries[0].Action)
+}
+
+func TestRestoreGroupFromBackupHandler_OverwriteDeletedGroup(t *testing.T) {
+	tt := newTrashTest()
+	backedUp := newExportedGroup(t, 1001, 2026)
+
+	result, err := tt.restoreFromBackup(backedUp, RestoreOverwrite, false)
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+
+	restored, err := tt.groupRepo.GetByIDWithCalendars(context.Background(), backedUp.ID)
+	require.NoError(t, err)
+	assert.Len(t, restored.Calendars, 1)
+}
+
+func TestRestoreGroupFromBackupHandler_OverwriteFails(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+
+	backedUp := newExportedGroup(t, 1001, 2026)
+	stored := backedUp.Snapshot()
+	stored.Readers = stored.Readers[1:]
+	require.NoError(t, tt.groupRepo.Create(ctx, stored))
+	// the Telegram account of the backed up reader reads in another group now
+	require.NoError(t, tt.groupRepo.Create(ctx, newExportedGroup(t, 1001)))
+
+	_, err := tt.restoreFromBackup(backedUp, RestoreOverwrite, false)
+	var taken domain.TelegramIDTakenError
+	require.ErrorAs(t, err, &taken)
+
+	kept, err := tt.groupRepo.GetByIDWithCalendars(ctx, stored.ID)
+	require.NoError(t, err, "the stored group is put back")
+	assert.Len(t, kept.Readers, 1)
+	items, err := tt.trash.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestRestoreGroupFromBackupHandler_Copy(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+
+	stored := newExportedGroup(t, 1001, 2026)
+	require.NoError(t, tt.groupRepo.Create(ctx, stored))
+
+	result, err := tt.restoreFromBackup(stored.Snapshot(), RestoreAsCopy, false)
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+	assert.NotEqual(t, stored.ID, result.GroupID)
+	assert.Equal(t, 1, result.ClearedTelegramIDs)
+
+	copied, err := tt.groupRepo.GetByIDWithCalendars(ctx, result.GroupID)
+	require.NoError(t, err)
+	require.Len(t, copied.Readers, 2)
+	assert.Zero(t, copied.Readers[0].TelegramID)
+	assert.Len(t, copied.Calendars, 1)
+
+	reader, err := tt.groupRepo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, reader.ID, "the stored group keeps the Telegram account")
+}
+
+func TestRestoreGroupFromBackupHandler_CopyKeepsFreeTelegramID(t *testing.T) {
+	ctx := context.Background()
+	tt := newTrashTest()
+
+	backedUp := newExportedGroup(t, 1001, 2026)
+
+	result, err := tt.restoreFromBackup(backedUp, RestoreAsCopy, false)
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+	assert.Zero(t, result.ClearedTelegramIDs)
+
+	reader, err := tt.groupRepo.GetByTelegramID(ctx, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, result.GroupID, reader.ID, "the copy takes the free Telegram account")
+}
+
+func changedFields(changes []domain.AuditChange) []string {
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
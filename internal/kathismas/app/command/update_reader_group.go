@@ -12,24 +12,50 @@ type UpdateReaderGroup struct {
 	GroupID     uuid.UUID
 	Name        *string
 	StartOffset *int
+	Size        *int
+	YearMode    *domain.CalendarYearMode
+	LentRule    *domain.LentReadingRule
+	// Version is the version of the group the change was made at, zero when unknown.
+	// A stale version is reported to the user instead of being applied over the later changes.
+	Version int
 }
 
 type UpdateReaderGroupHandler struct {
 	readerGroupRepo domain.RepositoryReaderGroup
+	auditLog        domain.RepositoryAuditLog
 }
 
-func NewUpdateReaderGroupHandler(readerGroupRepo domain.RepositoryReaderGroup) UpdateReaderGroupHandler {
+func NewUpdateReaderGroupHandler(
+	readerGroupRepo domain.RepositoryReaderGroup,
+	auditLog domain.RepositoryAuditLog,
+) UpdateReaderGroupHandler {
 	if readerGroupRepo == nil {
 		panic("nil readerGroupRepo")
 	}
-	return UpdateReaderGroupHandler{readerGroupRepo: readerGroupRepo}
+	if auditLog == nil {
+		panic("nil auditLog")
+	}
+	return UpdateReaderGroupHandler{readerGroupRepo: readerGroupRepo, auditLog: auditLog}
 }
 
 func (h UpdateReaderGroupHandler) Handle(ctx context.Context, cmd UpdateReaderGroup) error {
+	if cmd.Version != 0 {
+		return h.handle(ctx, cmd)
+	}
+	return retryUpdateOnConflict(ctx, func() error {
+		return h.handle(ctx, cmd)
+	})
+}
+
+func (h UpdateReaderGroupHandler) handle(ctx context.Context, cmd UpdateReaderGroup) error {
 	group, err := h.readerGroupRepo.GetByID(ctx, cmd.GroupID)
 	if err != nil {
 		return fmt.Errorf("failed to get reader group: %w", err)
 	}
+	before := group.Snapshot()
+	if cmd.Version != 0 && cmd.Version != group.Version {
+		return domain.VersionConflictError{GroupID: group.ID, Version: cmd.Version, StoredVersion: group.Version}
+	}
 
 	if cmd.Name != nil && cmd.StartOffset != nil {
 		if err := group.UpdateName(*cmd.Name); err != nil {
@@ -48,9 +74,28 @@ func (h UpdateReaderGroupHandler) Handle(ctx context.Context, cmd UpdateReaderGr
 		}
 	}
 
+	if cmd.Size != nil {
+		if err := group.UpdateSize(*cmd.Size); err != nil {
+			return fmt.Errorf("failed to update group size: %w", err)
+		}
+	}
+
+	if cmd.YearMode != nil {
+		if err := group.UpdateYearMode(*cmd.YearMode); err != nil {
+			return fmt.Errorf("failed to update year mode: %w", err)
+		}
+	}
+
+	if cmd.LentRule != nil {
+		if err := group.UpdateLentRule(*cmd.LentRule); err != nil {
+			return fmt.Errorf("failed to update lent reading rule: %w", err)
+		}
+	}
+
 	errUpd := h.readerGroupRepo.Update(ctx, group)
 	if errUpd != nil {
 		return fmt.Errorf("failed to update reader group: %w", errUpd)
 	}
+	recordAudit(ctx, h.auditLog, domain.AuditActionUpdateGroup, group.ID, domain.DiffReaderGroups(before, group))
 	return nil
 }
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/mocks"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateReaderGroupHandler_Version(t *testing.T) {
+	name := "Renamed"
+
+	tests := []struct {
+		name            string
+		version         int
+		wantConflict    bool
+		wantUpdateCalls int
+	}{
+		{name: "version of the stored group", version: 3, wantUpdateCalls: 1},
+		{name: "unknown version", version: 0, wantUpdateCalls: 1},
+		{name: "stale version is reported", version: 2, wantConflict: true, wantUpdateCalls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.RepositoryReaderGroupMock{
+				GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+					group, _ := domain.NewReaderGroup("Test Group", 1)
+					group.Version = 3
+					return group, nil
+				},
+				UpdateFunc: func(ctx context.Context, group *domain.ReaderGroup) error {
+					assert.Equal(t, name, group.Name)
+					return nil
+				},
+			}
+			handler := NewUpdateReaderGroupHandler(repo, memory.NewAuditLogRepository())
+
+			err := handler.Handle(context.Background(), UpdateReaderGroup{Name: &name, Version: tt.version})
+
+			if tt.wantConflict {
+				require.Error(t, err)
+				assert.True(t, domain.IsVersionConflict(err))
+				assert.Len(t, repo.GetByIDCalls(), 1, "a stale version is not retried")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, repo.UpdateCalls(), tt.wantUpdateCalls)
+		})
+	}
+}
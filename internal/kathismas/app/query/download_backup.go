@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type DownloadBackup struct{}
+
+// BackupSnapshotDTO is a backup archive of the database as it is now, the caller closes Archive
+type BackupSnapshotDTO struct {
+	Name    string
+	Archive io.ReadCloser
+}
+
+// BackupSnapshotter writes a backup archive of a consistent snapshot of the open database
+type BackupSnapshotter interface {
+	OpenSnapshot() (string, io.ReadCloser, error)
+}
+
+type DownloadBackupHandler struct {
+	backups BackupSnapshotter
+}
+
+func NewDownloadBackupHandler(backups BackupSnapshotter) DownloadBackupHandler {
+	if backups == nil {
+		panic("nil backups")
+	}
+	return DownloadBackupHandler{backups: backups}
+}
+
+// Handle snapshots the database without stopping the application and archives it the way backups are
+func (h DownloadBackupHandler) Handle(_ context.Context, _ DownloadBackup) (*BackupSnapshotDTO, error) {
+	name, archive, err := h.backups.OpenSnapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	return &BackupSnapshotDTO{Name: name, Archive: archive}, nil
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type ExportReaderGroup struct {
+	ID uuid.UUID
+}
+
+// ExportedReaderGroupDTO is the document of the group, Name is there to name the file after the group
+type ExportedReaderGroupDTO struct {
+	Name     string
+	Document []byte
+}
+
+type GroupDocumentEncoder interface {
+	Encode(group *domain.ReaderGroup) ([]byte, error)
+}
+
+type ExportReaderGroupHandler struct {
+	repo    domain.RepositoryReaderGroup
+	encoder GroupDocumentEncoder
+}
+
+func NewExportReaderGroupHandler(
+	repo domain.RepositoryReaderGroup,
+	encoder GroupDocumentEncoder,
+) ExportReaderGroupHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if encoder == nil {
+		panic("nil encoder")
+	}
+	return ExportReaderGroupHandler{repo: repo, encoder: encoder}
+}
+
+// Handle writes the group with its readers, no reading periods and calendars as a portable document
+func (h ExportReaderGroupHandler) Handle(ctx context.Context, q ExportReaderGroup) (*ExportedReaderGroupDTO, error) {
+	group, err := h.repo.GetByIDWithCalendars(ctx, q.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reader group: %w", err)
+	}
+
+	document, err := h.encoder.Encode(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export reader group: %w", err)
+	}
+	return &ExportedReaderGroupDTO{Name: group.Name, Document: document}, nil
+}
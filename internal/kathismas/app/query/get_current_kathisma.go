@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/services"
 	"github.com/gofrs/uuid/v5"
 )
 
@@ -15,13 +16,30 @@ type GetCurrentKathisma struct {
 }
 
 type CurrentKathismaDTO struct {
-	GroupID      uuid.UUID `json:"group_id"`
-	GroupName    string    `json:"group_name"`
-	ReaderNumber int       `json:"reader_number"`
-	Date         string    `json:"date"`
-	YearDay      int       `json:"year_day"`
-	Kathisma     int       `json:"kathisma"`
-	Year         int       `json:"year"`
+	GroupID         uuid.UUID            `json:"group_id"`
+	GroupName       string               `json:"group_name"`
+	ReaderNumber    int                  `json:"reader_number"`
+	Date            string               `json:"date"`
+	YearDay         int                  `json:"year_day"`
+	Kathisma        int                  `json:"kathisma"`
+	Kathismas       []int                `json:"kathismas"`
+	Content         []KathismaContentDTO `json:"content"`
+	Year            int                  `json:"year"`
+	NoReadingPeriod string               `json:"no_reading_period,omitempty"`
+}
+
+type PsalmRangeDTO struct {
+	FirstPsalm int    `json:"first_psalm"`
+	LastPsalm  int    `json:"last_psalm"`
+	FirstVerse int    `json:"first_verse,omitempty"`
+	LastVerse  int    `json:"last_verse,omitempty"`
+	Title      string `json:"title"`
+}
+
+type KathismaContentDTO struct {
+	Kathisma int             `json:"kathisma"`
+	Psalms   PsalmRangeDTO   `json:"psalms"`
+	Stases   []PsalmRangeDTO `json:"stases"`
 }
 
 type GetCurrentKathismaHandler struct {
@@ -33,56 +51,102 @@ func NewGetCurrentKathismaHandler(groupRepo domain.RepositoryReaderGroup) GetCur
 }
 
 func (h GetCurrentKathismaHandler) Handle(ctx context.Context, query GetCurrentKathisma) (*CurrentKathismaDTO, error) {
-	if query.ReaderNumber < 1 || query.ReaderNumber > 20 {
-		return nil, fmt.Errorf("reader number must be between 1 and 20")
+	if query.ReaderNumber < 1 || query.ReaderNumber > domain.KathismasCount {
+		return nil, fmt.Errorf("reader number must be between 1 and %d", domain.KathismasCount)
 	}
 
-	group, err := h.groupRepo.GetByID(ctx, query.GroupID)
+	group, err := h.groupRepo.GetByIDWithCalendars(ctx, query.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group: %w", err)
 	}
 
-	now := time.Now()
-	currentYear := now.Year()
-	yearDay := now.YearDay()
-
-	var currentCalendar *domain.CalendarOfReader
-	for _, cal := range group.Calendars {
-		if cal.Year == currentYear {
-			currentCalendar = &cal
-			break
-		}
+	if query.ReaderNumber > group.Size {
+		return nil, fmt.Errorf("reader number must be between 1 and %d in this group", group.Size)
 	}
 
+	now := time.Now()
+
+	// with the church year mode a date may belong to a calendar started in the previous civil year
+	currentCalendar := group.FindCalendarForDate(now)
 	if currentCalendar == nil {
-		return nil, fmt.Errorf("no calendar found for year %d. Please generate calendar for this year first", currentYear)
+		return nil, fmt.Errorf(
+			"no calendar found for year %s. Please generate calendar for this year first",
+			group.YearMode.Label(group.YearMode.YearOf(now)),
+		)
 	}
+	currentYear := currentCalendar.Year
+	yearDay, _ := currentCalendar.DayNumber(now)
 
 	readerCalendar, ok := currentCalendar.Calendar[query.ReaderNumber]
 	if !ok {
 		return nil, fmt.Errorf("reader number %d not found in calendar", query.ReaderNumber)
 	}
 
-	kathisma, ok := readerCalendar[yearDay]
-	if !ok {
+	kathismas, ok := readerCalendar[yearDay]
+	if !ok || len(kathismas) == 0 {
+		var periodName string
+		if period := services.FindNoReadingPeriod(now, group.NoReadingPeriods); period != nil {
+			periodName = period.Name
+		}
 		return &CurrentKathismaDTO{
-			GroupID:      group.ID,
-			GroupName:    group.Name,
-			ReaderNumber: query.ReaderNumber,
-			Date:         now.Format("2006-01-02"),
-			YearDay:      yearDay,
-			Kathisma:     0, // 0 means no reading today
-			Year:         currentYear,
+			GroupID:         group.ID,
+			GroupName:       group.Name,
+			ReaderNumber:    query.ReaderNumber,
+			Date:            now.Format("2006-01-02"),
+			YearDay:         yearDay,
+			Kathisma:        0, // 0 means no reading today
+			Kathismas:       []int{},
+			Year:            currentYear,
+			NoReadingPeriod: periodName,
 		}, nil
 	}
 
+	// a small group may go through the Psalter several times a day in Lent, each kathisma is described once
+	content := make([]KathismaContentDTO, 0, len(kathismas))
+	described := make(map[int]bool, len(kathismas))
+	for _, kathisma := range kathismas {
+		if described[kathisma] {
+			continue
+		}
+		described[kathisma] = true
+		kathismaContent, err := domain.GetKathismaContent(kathisma)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kathisma content: %w", err)
+		}
+		content = append(content, toKathismaContentDTO(kathismaContent))
+	}
+
 	return &CurrentKathismaDTO{
 		GroupID:      group.ID,
 		GroupName:    group.Name,
 		ReaderNumber: query.ReaderNumber,
 		Date:         now.Format("2006-01-02"),
 		YearDay:      yearDay,
-		Kathisma:     kathisma,
+		Kathisma:     kathismas[0],
+		Kathismas:    kathismas,
+		Content:      content,
 		Year:         currentYear,
 	}, nil
 }
+
+func toKathismaContentDTO(content domain.KathismaContent) KathismaContentDTO {
+	stases := make([]PsalmRangeDTO, 0, len(content.Stases))
+	for _, stasis := range content.Stases {
+		stases = append(stases, toPsalmRangeDTO(stasis))
+	}
+	return KathismaContentDTO{
+		Kathisma: content.Number,
+		Psalms:   toPsalmRangeDTO(content.Psalms),
+		Stases:   stases,
+	}
+}
+
+func toPsalmRangeDTO(r domain.PsalmRange) PsalmRangeDTO {
+	return PsalmRangeDTO{
+		FirstPsalm: r.FirstPsalm,
+		LastPsalm:  r.LastPsalm,
+		FirstVerse: r.FirstVerse,
+		LastVerse:  r.LastVerse,
+		Title:      r.String(),
+	}
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain/services"
+)
+
+type GetPaschalion struct {
+	Year int
+}
+
+type MovableFeastDTO struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  int    `json:"days"`
+}
+
+type PaschalionDTO struct {
+	Year   int               `json:"year"`
+	Pascha string            `json:"pascha"`
+	Feasts []MovableFeastDTO `json:"feasts"`
+}
+
+type GetPaschalionHandler struct{}
+
+func NewGetPaschalionHandler() GetPaschalionHandler {
+	return GetPaschalionHandler{}
+}
+
+func (h GetPaschalionHandler) Handle(_ context.Context, q GetPaschalion) (*PaschalionDTO, error) {
+	if q.Year < domain.MinSupportedYear || q.Year > domain.MaxSupportedYear {
+		return nil, fmt.Errorf("year must be between %d and %d", domain.MinSupportedYear, domain.MaxSupportedYear)
+	}
+
+	paschalion := services.GetPaschalion(q.Year)
+	feasts := make([]MovableFeastDTO, 0, len(paschalion.Feasts()))
+	for _, feast := range paschalion.Feasts() {
+		feasts = append(feasts, MovableFeastDTO{
+			Key:   feast.Key,
+			Name:  feast.Name,
+			Start: feast.Start.Format("2006-01-02"),
+			End:   feast.End.Format("2006-01-02"),
+			Days:  domain.DayNumber(feast.Start, feast.End),
+		})
+	}
+
+	return &PaschalionDTO{
+		Year:   paschalion.Year,
+		Pascha: paschalion.Pascha.Format("2006-01-02"),
+		Feasts: feasts,
+	}, nil
+}
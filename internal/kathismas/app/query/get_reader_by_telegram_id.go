@@ -31,24 +31,22 @@ func NewGetReaderByTelegramIDHandler(readerGroupRepo domain.RepositoryReaderGrou
 }
 
 func (h *GetReaderByTelegramIDHandler) Handle(ctx context.Context, q *GetReaderByTelegramIDQuery) (*GetReaderByTelegramIDResult, error) {
-	groups, err := h.readerGroupRepo.GetAll(ctx)
+	group, err := h.readerGroupRepo.GetByTelegramID(ctx, q.TelegramID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get groups: %w", err)
+		return nil, fmt.Errorf("failed to get group by telegram ID: %w", err)
 	}
 
-	for _, group := range groups {
-		for _, reader := range group.Readers {
-			if reader.TelegramID == q.TelegramID {
-				return &GetReaderByTelegramIDResult{
-					GroupID:      group.ID,
-					GroupName:    group.Name,
-					ReaderID:     reader.ID,
-					ReaderNumber: int(reader.ReaderNumber),
-					Username:     reader.Username,
-				}, nil
-			}
+	for _, reader := range group.Readers {
+		if reader.TelegramID == q.TelegramID {
+			return &GetReaderByTelegramIDResult{
+				GroupID:      group.ID,
+				GroupName:    group.Name,
+				ReaderID:     reader.ID,
+				ReaderNumber: int(reader.ReaderNumber),
+				Username:     reader.Username,
+			}, nil
 		}
 	}
 
-	return nil, fmt.Errorf("reader with telegram ID %d not found", q.TelegramID)
+	return nil, domain.TelegramReaderNotFoundError{TelegramID: q.TelegramID}
 }
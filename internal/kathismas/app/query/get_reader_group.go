@@ -20,13 +20,32 @@ type PsalmReaderDTO struct {
 	Phone        string `json:"phone"`
 }
 
+type NoReadingPeriodDTO struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Anchor      string `json:"anchor"`
+	Description string `json:"description"`
+}
+
+type CalendarDriftDTO struct {
+	Year                int `json:"year"`
+	StoredStartOffset   int `json:"stored_start_offset"`
+	ExpectedStartOffset int `json:"expected_start_offset"`
+}
+
 type ReaderGroupDetailDTO struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	StartOffset int              `json:"start_offset"`
-	Readers     []PsalmReaderDTO `json:"readers"`
-	CreatedAt   string           `json:"created_at"`
-	UpdatedAt   string           `json:"updated_at"`
+	ID               string               `json:"id"`
+	Name             string               `json:"name"`
+	Size             int                  `json:"size"`
+	YearMode         string               `json:"year_mode"`
+	LentRule         string               `json:"lent_rule"`
+	StartOffset      int                  `json:"start_offset"`
+	Readers          []PsalmReaderDTO     `json:"readers"`
+	NoReadingPeriods []NoReadingPeriodDTO `json:"no_reading_periods"`
+	CalendarDrifts   []CalendarDriftDTO   `json:"calendar_drifts"`
+	Version          int                  `json:"version"`
+	CreatedAt        string               `json:"created_at"`
+	UpdatedAt        string               `json:"updated_at"`
 }
 
 func (dto *ReaderGroupDetailDTO) GetAvailableReaderNumbers() []int8 {
@@ -35,8 +54,8 @@ func (dto *ReaderGroupDetailDTO) GetAvailableReaderNumbers() []int8 {
 		usedNumbers[r.ReaderNumber] = true
 	}
 
-	available := make([]int8, 0, 20-len(dto.Readers))
-	for i := int8(1); i <= 20; i++ {
+	available := make([]int8, 0, max(dto.Size-len(dto.Readers), 0))
+	for i := int8(1); int(i) <= dto.Size; i++ {
 		if !usedNumbers[i] {
 			available = append(available, i)
 		}
@@ -56,7 +75,7 @@ func NewGetReaderGroupHandler(repo domain.RepositoryReaderGroup) GetReaderGroupH
 }
 
 func (h GetReaderGroupHandler) Handle(ctx context.Context, q GetReaderGroup) (*ReaderGroupDetailDTO, error) {
-	group, err := h.repo.GetByID(ctx, q.ID)
+	group, err := h.repo.GetByIDWithCalendars(ctx, q.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reader group: %w", err)
 	}
@@ -72,12 +91,33 @@ func (h GetReaderGroupHandler) Handle(ctx context.Context, q GetReaderGroup) (*R
 		})
 	}
 
+	periods := make([]NoReadingPeriodDTO, 0, len(group.NoReadingPeriods))
+	for _, period := range group.NoReadingPeriods {
+		periods = append(periods, NoReadingPeriodDTO{
+			ID:          period.ID.String(),
+			Name:        period.Name,
+			Anchor:      string(period.Anchor),
+			Description: period.Description(),
+		})
+	}
+
+	drifts := make([]CalendarDriftDTO, 0)
+	for _, drift := range group.FindCalendarDrifts() {
+		drifts = append(drifts, CalendarDriftDTO(drift))
+	}
+
 	return &ReaderGroupDetailDTO{
-		ID:          group.ID.String(),
-		Name:        group.Name,
-		StartOffset: group.StartOffset,
-		Readers:     readers,
-		CreatedAt:   group.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   group.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:               group.ID.String(),
+		Name:             group.Name,
+		Size:             group.Size,
+		YearMode:         string(group.YearMode),
+		LentRule:         string(group.LentRule),
+		StartOffset:      group.StartOffset,
+		Readers:          readers,
+		NoReadingPeriods: periods,
+		CalendarDrifts:   drifts,
+		Version:          group.Version,
+		CreatedAt:        group.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        group.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
 }
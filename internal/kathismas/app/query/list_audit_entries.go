@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+type ListAuditEntries struct {
+	Filter domain.AuditFilter
+}
+
+type AuditChangeDTO struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type AuditEntryDTO struct {
+	ID        string           `json:"id"`
+	GroupID   string           `json:"group_id,omitempty"`
+	Actor     string           `json:"actor"`
+	Source    string           `json:"source"`
+	Action    string           `json:"action"`
+	Changes   []AuditChangeDTO `json:"changes"`
+	CreatedAt string           `json:"created_at"`
+}
+
+type ListAuditEntriesHandler struct {
+	repo domain.RepositoryAuditLog
+}
+
+func NewListAuditEntriesHandler(repo domain.RepositoryAuditLog) ListAuditEntriesHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	return ListAuditEntriesHandler{repo: repo}
+}
+
+func (h ListAuditEntriesHandler) Handle(ctx context.Context, q ListAuditEntries) ([]AuditEntryDTO, error) {
+	entries, err := h.repo.List(ctx, q.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	dtos := make([]AuditEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		changes := make([]AuditChangeDTO, 0, len(entry.Changes))
+		for _, change := range entry.Changes {
+			changes = append(changes, AuditChangeDTO(change))
+		}
+		dto := AuditEntryDTO{
+			ID:        entry.ID.String(),
+			Actor:     entry.Actor,
+			Source:    string(entry.Source),
+			Action:    string(entry.Action),
+			Changes:   changes,
+			CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		}
+		if !entry.GroupID.IsNil() {
+			dto.GroupID = entry.GroupID.String()
+		}
+		dtos = append(dtos, dto)
+	}
+
+	return dtos, nil
+}
@@ -12,10 +12,14 @@ type ListReaderGroups struct{}
 type ReaderGroupDTO struct {
 	ID             string `json:"id"`
 	Name           string `json:"name"`
+	Size           int    `json:"size"`
+	YearMode       string `json:"year_mode"`
+	LentRule       string `json:"lent_rule"`
 	StartOffset    int    `json:"start_offset"`
 	ReadersCount   int    `json:"readers_count"`
 	CalendarsCount int    `json:"calendars_count"`
 	CreatedAt      string `json:"created_at"`
+	Version        int    `json:"version"`
 }
 
 type ListReaderGroupsHandler struct {
@@ -37,13 +41,22 @@ func (h ListReaderGroupsHandler) Handle(ctx context.Context, q ListReaderGroups)
 
 	dtos := make([]ReaderGroupDTO, 0, len(groups))
 	for _, group := range groups {
+		// calendars are not loaded with the groups
+		calendarsCount, err := h.repo.CountCalendars(ctx, group.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count calendars: %w", err)
+		}
 		dtos = append(dtos, ReaderGroupDTO{
 			ID:             group.ID.String(),
 			Name:           group.Name,
+			Size:           group.Size,
+			YearMode:       string(group.YearMode),
+			LentRule:       string(group.LentRule),
 			StartOffset:    group.StartOffset,
 			ReadersCount:   group.ReadersCount(),
-			CalendarsCount: group.CalendarsCount(),
+			CalendarsCount: calendarsCount,
 			CreatedAt:      group.CreatedAt.Format("2006-01-02 15:04:05"),
+			Version:        group.Version,
 		})
 	}
 
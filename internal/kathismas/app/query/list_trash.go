@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+)
+
+type ListTrash struct{}
+
+type TrashItemDTO struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	GroupID   string `json:"group_id"`
+	GroupName string `json:"group_name"`
+	// ReaderName and ReaderNumber are set for removed readers,
+	// ReadersCount and CalendarsCount for deleted groups
+	ReaderName     string `json:"reader_name,omitempty"`
+	ReaderNumber   int8   `json:"reader_number,omitempty"`
+	ReadersCount   int    `json:"readers_count,omitempty"`
+	CalendarsCount int    `json:"calendars_count,omitempty"`
+	DeletedAt      string `json:"deleted_at"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
+type ListTrashHandler struct {
+	trash domain.RepositoryTrash
+}
+
+func NewListTrashHandler(trash domain.RepositoryTrash) ListTrashHandler {
+	if trash == nil {
+		panic("nil trash")
+	}
+	return ListTrashHandler{trash: trash}
+}
+
+func (h ListTrashHandler) Handle(ctx context.Context, q ListTrash) ([]TrashItemDTO, error) {
+	items, err := h.trash.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	dtos := make([]TrashItemDTO, 0, len(items))
+	for _, item := range items {
+		dto := TrashItemDTO{
+			ID:        item.ID.String(),
+			Kind:      string(item.Kind),
+			GroupID:   item.GroupID.String(),
+			GroupName: item.GroupName,
+			DeletedAt: item.DeletedAt.Format("2006-01-02 15:04:05"),
+			ExpiresAt: item.ExpiresAt.Format("2006-01-02 15:04:05"),
+		}
+		if item.Reader != nil {
+			dto.ReaderName = item.Reader.Username
+			dto.ReaderNumber = item.Reader.ReaderNumber
+		}
+		if item.Group != nil {
+			dto.ReadersCount = item.Group.ReadersCount()
+			dto.CalendarsCount = item.Group.CalendarsCount()
+		}
+		dtos = append(dtos, dto)
+	}
+
+	return dtos, nil
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/gofrs/uuid/v5"
+)
+
+type VerifyCalendar struct {
+	GroupID uuid.UUID
+	Year    int
+}
+
+type CalendarViolationDTO struct {
+	Day     int    `json:"day,omitempty"`
+	Reader  int    `json:"reader,omitempty"`
+	Message string `json:"message"`
+}
+
+type CalendarVerificationDTO struct {
+	GroupID      uuid.UUID              `json:"group_id"`
+	Year         int                    `json:"year"`
+	YearMode     string                 `json:"year_mode"`
+	StartOffset  int                    `json:"start_offset"`
+	ReadersCount int                    `json:"readers_count"`
+	GroupSize    int                    `json:"group_size"`
+	ReadingDays  int                    `json:"reading_days"`
+	Valid        bool                   `json:"valid"`
+	Violations   []CalendarViolationDTO `json:"violations"`
+}
+
+type VerifyCalendarHandler struct {
+	groupRepo domain.RepositoryReaderGroup
+}
+
+func NewVerifyCalendarHandler(groupRepo domain.RepositoryReaderGroup) VerifyCalendarHandler {
+	if groupRepo == nil {
+		panic("nil groupRepo")
+	}
+	return VerifyCalendarHandler{groupRepo: groupRepo}
+}
+
+// Handle checks the stored calendar of the year in the current year mode of the group
+func (h VerifyCalendarHandler) Handle(ctx context.Context, q VerifyCalendar) (*CalendarVerificationDTO, error) {
+	group, err := h.groupRepo.GetByIDWithCalendars(ctx, q.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	calendar := group.CalendarForYear(q.Year)
+	if calendar == nil {
+		return nil, fmt.Errorf("no calendar found for year %s", group.YearMode.Label(q.Year))
+	}
+
+	violations := make([]CalendarViolationDTO, 0)
+	if errVerify := calendar.Verify(); errVerify != nil {
+		var verificationErr *domain.CalendarVerificationError
+		if !errors.As(errVerify, &verificationErr) {
+			return nil, fmt.Errorf("failed to verify calendar: %w", errVerify)
+		}
+		for _, violation := range verificationErr.Violations {
+			violations = append(violations, CalendarViolationDTO(violation))
+		}
+	}
+
+	return &CalendarVerificationDTO{
+		GroupID:      group.ID,
+		Year:         calendar.Year,
+		YearMode:     string(calendar.YearMode),
+		StartOffset:  calendar.StartOffset,
+		ReadersCount: len(calendar.Calendar),
+		GroupSize:    group.Size,
+		ReadingDays:  calendar.ReadingDays(),
+		Valid:        len(violations) == 0,
+		Violations:   violations,
+	}, nil
+}
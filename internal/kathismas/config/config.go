@@ -2,18 +2,51 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 )
 
+// StorageBolt and StorageSQLite are the storage backends Storage.Backend chooses from
+const (
+	StorageBolt   = "bolt"
+	StorageSQLite = "sqlite"
+)
+
 type Config struct {
 	System struct {
 		BaseUrl string `yaml:"base_url" env:"SYSTEM_BASE_URL"`
+		// Revision is the build revision, set by the application at start
+		Revision string `yaml:"-"`
 	}
 	Telegram struct {
 		BotToken   string `yaml:"bot_token" env:"TELEGRAM_BOT_TOKEN"`
 		NumWorkers int8   `yaml:"num_workers" env:"TELEGRAM_NUM_WORKERS" envDefault:"10"`
 	}
+	Storage struct {
+		Backend    string `yaml:"backend" env:"STORAGE_BACKEND" envDefault:"bolt"`
+		BoltPath   string `yaml:"bolt_path" env:"STORAGE_BOLT_PATH" envDefault:"for-twenty-readers.db"`
+		SQLitePath string `yaml:"sqlite_path" env:"STORAGE_SQLITE_PATH" envDefault:"for-twenty-readers.sqlite"`
+	}
+	// Trash keeps deleted groups and removed readers restorable for Retention,
+	// expired items are purged every PurgeInterval
+	Trash struct {
+		Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION" envDefault:"720h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
+	}
+	// Backup takes a backup of the bolt database into Dir every Interval and prunes the backups
+	// by the Keep counts, an empty Dir turns the schedule off. Token opens the snapshot download.
+	Backup struct {
+		Dir         string        `yaml:"dir" env:"BACKUP_DIR"`
+		Interval    time.Duration `yaml:"interval" env:"BACKUP_INTERVAL" envDefault:"24h"`
+		KeepDaily   int           `yaml:"keep_daily" env:"BACKUP_KEEP_DAILY" envDefault:"7"`
+		KeepWeekly  int           `yaml:"keep_weekly" env:"BACKUP_KEEP_WEEKLY" envDefault:"4"`
+		KeepMonthly int           `yaml:"keep_monthly" env:"BACKUP_KEEP_MONTHLY" envDefault:"12"`
+		KeepYearly  int           `yaml:"keep_yearly" env:"BACKUP_KEEP_YEARLY" envDefault:"3"`
+		Passphrase  string        `yaml:"passphrase" env:"BACKUP_PASSPHRASE"`
+		KeyFile     string        `yaml:"key_file" env:"BACKUP_KEY_FILE"`
+		Token       string        `yaml:"token" env:"BACKUP_TOKEN"`
+	}
 }
 
 func NewConfiguration() (*Config, error) {
@@ -22,5 +55,17 @@ func NewConfiguration() (*Config, error) {
 	if err = env.ParseWithOptions(&cfg, env.Options{Prefix: ""}); err != nil {
 		return nil, fmt.Errorf("couldn't find conf in environment: %v", err)
 	}
+	if cfg.Storage.Backend != StorageBolt && cfg.Storage.Backend != StorageSQLite {
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+	if cfg.Trash.Retention <= 0 || cfg.Trash.PurgeInterval <= 0 {
+		return nil, fmt.Errorf("trash retention and purge interval must be positive")
+	}
+	if cfg.Backup.Interval <= 0 {
+		return nil, fmt.Errorf("backup interval must be positive")
+	}
+	if cfg.Backup.Dir != "" && cfg.Storage.Backend != StorageBolt {
+		return nil, fmt.Errorf("backups are only taken of the %s backend", StorageBolt)
+	}
 	return &cfg, nil
 }
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// AuditSource tells through which port a change was made
+type AuditSource string
+
+const (
+	AuditSourceWeb      AuditSource = "web"
+	AuditSourceTelegram AuditSource = "telegram"
+	// AuditSourceSystem marks the changes made without a port, by the application itself
+	AuditSourceSystem AuditSource = "system"
+)
+
+// AuditAction names the command an audit entry was written for
+type AuditAction string
+
+const (
+	AuditActionCreateGroup           AuditAction = "create_group"
+	AuditActionUpdateGroup           AuditAction = "update_group"
+	AuditActionDeleteGroup           AuditAction = "delete_group"
+	AuditActionAddReader             AuditAction = "add_reader"
+	AuditActionRemoveReader          AuditAction = "remove_reader"
+	AuditActionAddNoReadingPeriod    AuditAction = "add_no_reading_period"
+	AuditActionRemoveNoReadingPeriod AuditAction = "remove_no_reading_period"
+	AuditActionGenerateCalendar      AuditAction = "generate_calendar"
+	AuditActionGenerateCalendarRange AuditAction = "generate_calendar_range"
+	AuditActionRegenerateCalendar    AuditAction = "regenerate_calendar"
+	AuditActionCreatePsalmReader     AuditAction = "create_psalm_reader"
+	AuditActionRestoreGroup          AuditAction = "restore_group"
+	AuditActionRestoreReader         AuditAction = "restore_reader"
+	AuditActionPurgeTrash            AuditAction = "purge_trash"
+	AuditActionImportGroup           AuditAction = "import_group"
+	AuditActionRestoreBackupGroup    AuditAction = "restore_backup_group"
+)
+
+// AuditChange is a field before and after a change, empty when the field did not exist on that side
+type AuditChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// AuditEntry records who changed a group, through which port and what changed.
+// GroupID is nil for changes outside of any group.
+type AuditEntry struct {
+	ID        uuid.UUID
+	GroupID   uuid.UUID
+	Actor     string
+	Source    AuditSource
+	Action    AuditAction
+	Changes   []AuditChange
+	CreatedAt time.Time
+}
+
+func NewAuditEntry(
+	actor string,
+	source AuditSource,
+	action AuditAction,
+	groupID uuid.UUID,
+	changes []AuditChange,
+) (*AuditEntry, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate uuid7: %w", err)
+	}
+	return &AuditEntry{
+		ID:        id,
+		GroupID:   groupID,
+		Actor:     actor,
+		Source:    source,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// AuditFilter selects audit entries, zero fields match every entry
+type AuditFilter struct {
+	GroupID uuid.UUID
+	Actor   string
+	Source  AuditSource
+	Action  AuditAction
+	// Since and Until bound CreatedAt, Until is exclusive
+	Since time.Time
+	Until time.Time
+	// Limit keeps the newest entries only
+	Limit int
+}
+
+func (f AuditFilter) Matches(entry AuditEntry) bool {
+	switch {
+	case !f.GroupID.IsNil() && entry.GroupID != f.GroupID,
+		f.Actor != "" && entry.Actor != f.Actor,
+		f.Source != "" && entry.Source != f.Source,
+		f.Action != "" && entry.Action != f.Action,
+		!f.Since.IsZero() && entry.CreatedAt.Before(f.Since),
+		!f.Until.IsZero() && !entry.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Apply returns the matching entries newest first, up to Limit of them
+func (f AuditFilter) Apply(entries []AuditEntry) []AuditEntry {
+	matching := make([]AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	slices.SortFunc(matching, func(a, b AuditEntry) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.ID.Bytes(), a.ID.Bytes())
+	})
+	if f.Limit > 0 && len(matching) > f.Limit {
+		matching = matching[:f.Limit]
+	}
+	return matching
+}
+
+// RepositoryAuditLog only appends entries, List returns the newest ones first
+type RepositoryAuditLog interface {
+	Append(ctx context.Context, entry *AuditEntry) error
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+// DiffReaderGroups lists the fields which differ between the two states of a group, a nil group has none.
+// Readers are matched by number, periods by name and calendars by year, calendars only show up
+// when the group was loaded with them.
+func DiffReaderGroups(before, after *ReaderGroup) []AuditChange {
+	beforeValues, afterValues := groupAuditValues(before), groupAuditValues(after)
+
+	fields := beforeValues.fields
+	for _, field := range afterValues.fields {
+		if _, ok := beforeValues.values[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	var changes []AuditChange
+	for _, field := range fields {
+		if beforeValues.values[field] != afterValues.values[field] {
+			changes = append(changes, AuditChange{
+				Field:  field,
+				Before: beforeValues.values[field],
+				After:  afterValues.values[field],
+			})
+		}
+	}
+	return changes
+}
+
+// auditValues keeps the fields in the order they were added
+type auditValues struct {
+	fields []string
+	values map[string]string
+}
+
+func (v *auditValues) add(field, value string) {
+	if _, ok := v.values[field]; !ok {
+		v.fields = append(v.fields, field)
+	}
+	v.values[field] = value
+}
+
+func groupAuditValues(group *ReaderGroup) auditValues {
+	values := auditValues{values: make(map[string]string)}
+	if group == nil {
+		return values
+	}
+
+	values.add("name", group.Name)
+	values.add("size", strconv.Itoa(group.Size))
+	values.add("start_offset", strconv.Itoa(group.StartOffset))
+	values.add("year_mode", string(group.YearMode))
+	values.add("lent_rule", string(group.LentRule))
+	for _, reader := range group.Readers {
+		value := reader.Username
+		if reader.TelegramID != 0 {
+			value += fmt.Sprintf(" (telegram %d)", reader.TelegramID)
+		}
+		values.add(fmt.Sprintf("reader %d", reader.ReaderNumber), value)
+	}
+	for _, period := range group.NoReadingPeriods {
+		values.add("no_reading_period "+period.Name, period.Description())
+	}
+	for _, calendar := range group.Calendars {
+		values.add(
+			"calendar "+calendar.YearMode.Label(calendar.Year),
+			fmt.Sprintf("start offset %d, %s", calendar.StartOffset, calendar.ID),
+		)
+	}
+	return values
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffReaderGroups(t *testing.T) {
+	before, err := NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	before.NoReadingPeriods = nil
+	ivan, err := NewPsalmReader("Иван", 1001, "", 1)
+	require.NoError(t, err)
+	require.NoError(t, before.AddReader(ivan))
+
+	after := *before
+	after.Readers = nil
+	require.NoError(t, after.UpdateName("Renamed"))
+	petr, err := NewPsalmReader("Петр", 0, "", 2)
+	require.NoError(t, err)
+	require.NoError(t, after.AddReader(petr))
+	period, err := NewPaschaNoReadingPeriod("Светлая седмица", 0, 6)
+	require.NoError(t, err)
+	require.NoError(t, after.AddNoReadingPeriod(*period))
+
+	assert.Equal(t, []AuditChange{
+		{Field: "name", Before: "Test Group", After: "Renamed"},
+		{Field: "reader 1", Before: "Иван (telegram 1001)"},
+		{Field: "reader 2", After: "Петр"},
+		{Field: "no_reading_period Светлая седмица", After: "Пасха +0 … +6 дн."},
+	}, DiffReaderGroups(before, &after))
+
+	assert.Empty(t, DiffReaderGroups(before, before))
+
+	// a created group has every field after the change only
+	created := DiffReaderGroups(nil, before)
+	require.NotEmpty(t, created)
+	assert.Equal(t, AuditChange{Field: "name", After: "Test Group"}, created[0])
+	for _, change := range created {
+		assert.Empty(t, change.Before)
+	}
+}
+
+func TestDiffReaderGroups_Calendars(t *testing.T) {
+	before, err := NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	old := NewCalendarOfReader(2026, CalendarYearChurch, 1, CalendarMap{})
+	require.NoError(t, before.AddCalendar(*old))
+
+	after := *before
+	after.Calendars = nil
+	regenerated := NewCalendarOfReader(2026, CalendarYearChurch, 3, CalendarMap{})
+	require.NoError(t, after.AddCalendar(*regenerated))
+
+	assert.Equal(t, []AuditChange{{
+		Field:  "calendar 2026/2027",
+		Before: "start offset 1, " + old.ID.String(),
+		After:  "start offset 3, " + regenerated.ID.String(),
+	}}, DiffReaderGroups(before, &after))
+}
+
+func TestAuditFilter_Matches(t *testing.T) {
+	groupID := uuid.Must(uuid.NewV7())
+	entry, err := NewAuditEntry("127.0.0.1", AuditSourceWeb, AuditActionAddReader, groupID, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   bool
+	}{
+		{name: "empty", filter: AuditFilter{}, want: true},
+		{name: "all fields", filter: AuditFilter{
+			GroupID: groupID,
+			Actor:   "127.0.0.1",
+			Source:  AuditSourceWeb,
+			Action:  AuditActionAddReader,
+			Since:   entry.CreatedAt,
+			Until:   entry.CreatedAt.Add(time.Second),
+		}, want: true},
+		{name: "other group", filter: AuditFilter{GroupID: uuid.Must(uuid.NewV7())}, want: false},
+		{name: "other actor", filter: AuditFilter{Actor: "@ivan"}, want: false},
+		{name: "other source", filter: AuditFilter{Source: AuditSourceTelegram}, want: false},
+		{name: "other action", filter: AuditFilter{Action: AuditActionRemoveReader}, want: false},
+		{name: "later", filter: AuditFilter{Since: entry.CreatedAt.Add(time.Second)}, want: false},
+		{name: "until is exclusive", filter: AuditFilter{Until: entry.CreatedAt}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(*entry))
+		})
+	}
+}
@@ -1,20 +1,39 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
 
 // CalendarMap stores calendar data for all readers in a group
-// First key: reader number (1-20)
-// Second key: year day (1-365/366)
-// Value: kathisma number (1-20)
-type CalendarMap map[int]map[int]int
+// First key: reader number (1-group size)
+// Second key: day of the calendar year (1-365/366), counted from the start of the year in its mode
+// Value: kathisma numbers (1-20) read that day, several when the group has fewer than 20 readers
+type CalendarMap map[int]map[int][]int
+
+// ChangedDays counts the days on which at least one reader reads differently in the other calendar
+func (c CalendarMap) ChangedDays(other CalendarMap) int {
+	changed := make(map[int]bool)
+	compare := func(a, b CalendarMap) {
+		for readerNumber, days := range a {
+			for day, kathismas := range days {
+				if !slices.Equal(kathismas, b[readerNumber][day]) {
+					changed[day] = true
+				}
+			}
+		}
+	}
+	compare(c, other)
+	compare(other, c)
+	return len(changed)
+}
 
 type CalendarOfReader struct {
 	ID          uuid.UUID `storm:"id"`
 	Year        int
+	YearMode    CalendarYearMode
 	StartOffset int
 	Calendar    CalendarMap
 	CreatedAt   time.Time `storm:"index"`
@@ -24,6 +43,7 @@ type CalendarOfReader struct {
 func UnmarshallCalendarOfReader(
 	id uuid.UUID,
 	year int,
+	yearMode CalendarYearMode,
 	startOffset int,
 	calendar CalendarMap,
 	createdAt time.Time,
@@ -32,6 +52,7 @@ func UnmarshallCalendarOfReader(
 	return &CalendarOfReader{
 		ID:          id,
 		Year:        year,
+		YearMode:    yearMode,
 		StartOffset: startOffset,
 		Calendar:    calendar,
 		CreatedAt:   createdAt,
@@ -39,11 +60,12 @@ func UnmarshallCalendarOfReader(
 	}
 }
 
-func NewCalendarOfReader(year, startOffset int, calendarData CalendarMap) *CalendarOfReader {
+func NewCalendarOfReader(year int, yearMode CalendarYearMode, startOffset int, calendarData CalendarMap) *CalendarOfReader {
 	now := time.Now()
 	return &CalendarOfReader{
 		ID:          uuid.Must(uuid.NewV7()),
 		Year:        year,
+		YearMode:    yearMode,
 		StartOffset: startOffset,
 		Calendar:    calendarData,
 		CreatedAt:   now,
@@ -51,24 +73,53 @@ func NewCalendarOfReader(year, startOffset int, calendarData CalendarMap) *Calen
 	}
 }
 
+func (c *CalendarOfReader) StartDate() time.Time {
+	return c.YearMode.StartDate(c.Year)
+}
+
+func (c *CalendarOfReader) EndDate() time.Time {
+	return c.StartDate().AddDate(1, 0, -1)
+}
+
+// DayNumber returns the day of the calendar the date falls on,
+// false when the date is outside the calendar year
+func (c *CalendarOfReader) DayNumber(date time.Time) (int, bool) {
+	day := DayNumber(c.StartDate(), date)
+	if day < 1 || day > c.YearMode.NumberDays(c.Year) {
+		return 0, false
+	}
+	return day, true
+}
+
 // CalculateNextStartOffset calculates the StartOffset for the next year
-// based on the last kathisma of reader #1 in the current year
+// based on the first kathisma of reader #1 on the last reading day of the current year
 func (c *CalendarOfReader) CalculateNextStartOffset() int {
+	return c.CalculateStartOffsetAfter(c.EndDate())
+}
+
+// CalculateStartOffsetAfter calculates the StartOffset of a calendar starting the day after the date,
+// based on the first kathisma of reader #1 on the last reading day up to that date
+func (c *CalendarOfReader) CalculateStartOffsetAfter(date time.Time) int {
 	readerOneSchedule, exists := c.Calendar[1]
 	if !exists || len(readerOneSchedule) == 0 {
 		return 1
 	}
 
+	lastDay := min(DayNumber(c.StartDate(), date), c.YearMode.NumberDays(c.Year))
+
 	maxDay := 0
 	for day := range readerOneSchedule {
-		if day > maxDay {
+		if day > maxDay && day <= lastDay {
 			maxDay = day
 		}
 	}
 
-	lastKathisma := readerOneSchedule[maxDay]
-	nextKathisma := lastKathisma + 1
-	if nextKathisma > 20 {
+	lastKathismas := readerOneSchedule[maxDay]
+	if len(lastKathismas) == 0 {
+		return 1
+	}
+	nextKathisma := lastKathismas[0] + 1
+	if nextKathisma > KathismasCount {
 		nextKathisma = 1
 	}
 
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxReportedViolations keeps the error message of a badly broken calendar readable
+const maxReportedViolations = 10
+
+// CalendarViolation is a single broken invariant of a calendar. Reader is zero when the whole day is affected,
+// Day is zero when the reader as a whole is wrong.
+type CalendarViolation struct {
+	Day     int
+	Reader  int
+	Message string
+}
+
+func (v CalendarViolation) String() string {
+	if v.Day == 0 {
+		return fmt.Sprintf("reader %d: %s", v.Reader, v.Message)
+	}
+	if v.Reader == 0 {
+		return fmt.Sprintf("day %d: %s", v.Day, v.Message)
+	}
+	return fmt.Sprintf("day %d, reader %d: %s", v.Day, v.Reader, v.Message)
+}
+
+type CalendarVerificationError struct {
+	Year       int
+	Violations []CalendarViolation
+}
+
+func (e *CalendarVerificationError) Error() string {
+	messages := make([]string, 0, min(len(e.Violations), maxReportedViolations))
+	for _, violation := range e.Violations[:min(len(e.Violations), maxReportedViolations)] {
+		messages = append(messages, violation.String())
+	}
+	if len(e.Violations) > maxReportedViolations {
+		messages = append(messages, fmt.Sprintf("and %d more", len(e.Violations)-maxReportedViolations))
+	}
+	return fmt.Sprintf("calendar for year %d has %d violations: %s", e.Year, len(e.Violations), strings.Join(messages, "; "))
+}
+
+// Verify checks that readers are numbered from 1 without gaps, that every day belongs to the calendar year,
+// and that on every reading day each kathisma is read equally often, once on ordinary days, and every reader reads something.
+// It returns a *CalendarVerificationError listing all violations.
+func (c *CalendarOfReader) Verify() error {
+	var violations []CalendarViolation
+	numberDays := c.YearMode.NumberDays(c.Year)
+	readersCount := len(c.Calendar)
+
+	for readerNumber, days := range c.Calendar {
+		if readerNumber < 1 || readerNumber > readersCount {
+			violations = append(violations, CalendarViolation{
+				Reader:  readerNumber,
+				Message: fmt.Sprintf("reader number is outside of 1..%d", readersCount),
+			})
+		}
+		for day := range days {
+			if day < 1 || day > numberDays {
+				violations = append(violations, CalendarViolation{
+					Day:     day,
+					Reader:  readerNumber,
+					Message: fmt.Sprintf("day is outside of the calendar year of %d days", numberDays),
+				})
+			}
+		}
+	}
+
+	for day := 1; day <= numberDays; day++ {
+		violations = append(violations, c.verifyDay(day, readersCount)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(violations, func(a, b CalendarViolation) int {
+		if a.Day != b.Day {
+			return a.Day - b.Day
+		}
+		return a.Reader - b.Reader
+	})
+	return &CalendarVerificationError{Year: c.Year, Violations: violations}
+}
+
+// ReadingDays counts the days on which anybody reads
+func (c *CalendarOfReader) ReadingDays() int {
+	days := make(map[int]bool)
+	for _, readerDays := range c.Calendar {
+		for day, kathismas := range readerDays {
+			if len(kathismas) > 0 {
+				days[day] = true
+			}
+		}
+	}
+	return len(days)
+}
+
+func (c *CalendarOfReader) verifyDay(day, readersCount int) []CalendarViolation {
+	var violations []CalendarViolation
+	var readCount [KathismasCount + 1]int
+	var totalRead int
+	var idle []int
+	reading := false
+
+	for readerNumber := 1; readerNumber <= readersCount; readerNumber++ {
+		kathismas := c.Calendar[readerNumber][day]
+		if len(kathismas) == 0 {
+			idle = append(idle, readerNumber)
+			continue
+		}
+		reading = true
+		for _, kathisma := range kathismas {
+			if kathisma < 1 || kathisma > KathismasCount {
+				violations = append(violations, CalendarViolation{
+					Day:     day,
+					Reader:  readerNumber,
+					Message: fmt.Sprintf("unknown kathisma %d", kathisma),
+				})
+				continue
+			}
+			readCount[kathisma]++
+			totalRead++
+		}
+	}
+
+	// a day nobody reads is a no reading day
+	if !reading {
+		return violations
+	}
+
+	for _, readerNumber := range idle {
+		violations = append(violations, CalendarViolation{
+			Day:     day,
+			Reader:  readerNumber,
+			Message: "reader has nothing to read on a reading day",
+		})
+	}
+	// on the days of an increased load the Psalter is read several times, but each kathisma equally often
+	passes := max(totalRead/KathismasCount, 1)
+	for kathisma := 1; kathisma <= KathismasCount; kathisma++ {
+		switch {
+		case readCount[kathisma] == 0:
+			violations = append(violations, CalendarViolation{
+				Day:     day,
+				Message: fmt.Sprintf("kathisma %d is not read", kathisma),
+			})
+		case readCount[kathisma] != passes:
+			violations = append(violations, CalendarViolation{
+				Day:     day,
+				Message: fmt.Sprintf("kathisma %d is read %d times instead of %d", kathisma, readCount[kathisma], passes),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func twoReaderDay(first, second []int) CalendarMap {
+	return CalendarMap{
+		1: {10: first},
+		2: {10: second},
+	}
+}
+
+func TestCalendarOfReader_Verify(t *testing.T) {
+	firstHalf := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	secondHalf := []int{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+
+	tests := []struct {
+		name           string
+		calendar       CalendarMap
+		wantViolations []string
+	}{
+		{
+			name:     "valid day",
+			calendar: twoReaderDay(firstHalf, secondHalf),
+		},
+		{
+			name:     "nobody reads on a no reading day",
+			calendar: CalendarMap{1: {}, 2: {}},
+		},
+		{
+			name:     "kathisma read twice and another missed",
+			calendar: twoReaderDay(firstHalf, []int{10, 12, 13, 14, 15, 16, 17, 18, 19, 20}),
+			wantViolations: []string{
+				"day 10: kathisma 10 is read 2 times instead of 1",
+				"day 10: kathisma 11 is not read",
+			},
+		},
+		{
+			name: "psalter read twice",
+			calendar: twoReaderDay(
+				append(append([]int{}, firstHalf...), secondHalf...),
+				append(append([]int{}, secondHalf...), firstHalf...),
+			),
+		},
+		{
+			name: "second pass is uneven",
+			calendar: twoReaderDay(
+				append(append([]int{}, firstHalf...), secondHalf...),
+				[]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 1},
+			),
+			wantViolations: []string{
+				"day 10: kathisma 1 is read 3 times instead of 2",
+				"day 10: kathisma 20 is read 1 times instead of 2",
+			},
+		},
+		{
+			name: "idle reader",
+			calendar: CalendarMap{
+				1: {10: append(append([]int{}, firstHalf...), secondHalf...)},
+				2: {},
+			},
+			wantViolations: []string{"day 10, reader 2: reader has nothing to read on a reading day"},
+		},
+		{
+			name:     "unknown kathisma",
+			calendar: twoReaderDay(firstHalf, []int{11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}),
+			wantViolations: []string{
+				"day 10, reader 2: unknown kathisma 21",
+			},
+		},
+		{
+			name: "day outside of the year",
+			calendar: CalendarMap{
+				1: {400: firstHalf},
+			},
+			wantViolations: []string{"day 400, reader 1: day is outside of the calendar year of 365 days"},
+		},
+		{
+			name: "gap in reader numbers",
+			calendar: CalendarMap{
+				1: {},
+				3: {},
+			},
+			wantViolations: []string{"reader 3: reader number is outside of 1..2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := NewCalendarOfReader(2025, CalendarYearCivil, 1, tt.calendar)
+
+			err := calendar.Verify()
+
+			if len(tt.wantViolations) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var verificationErr *CalendarVerificationError
+			require.ErrorAs(t, err, &verificationErr)
+			violations := make([]string, 0, len(verificationErr.Violations))
+			for _, violation := range verificationErr.Violations {
+				violations = append(violations, violation.String())
+			}
+			assert.Equal(t, tt.wantViolations, violations)
+		})
+	}
+}
+
+func TestCalendarVerificationError_Error(t *testing.T) {
+	violations := make([]CalendarViolation, 0, 12)
+	for day := 1; day <= 12; day++ {
+		violations = append(violations, CalendarViolation{Day: day, Message: "kathisma 1 is not read"})
+	}
+	err := &CalendarVerificationError{Year: 2025, Violations: violations}
+
+	assert.Contains(t, err.Error(), "calendar for year 2025 has 12 violations")
+	assert.Contains(t, err.Error(), "day 10: kathisma 1 is not read; and 2 more")
+}
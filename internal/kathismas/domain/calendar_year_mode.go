@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinSupportedYear and MaxSupportedYear bound the years calendars are generated for,
+// the whole range is pinned by the golden files of the calendar services
+const (
+	MinSupportedYear = 2000
+	MaxSupportedYear = 2100
+)
+
+// CalendarYearMode defines the window a calendar of a given year covers
+type CalendarYearMode string
+
+const (
+	// CalendarYearCivil runs from January 1 through December 31 of the year
+	CalendarYearCivil CalendarYearMode = "civil"
+	// CalendarYearChurch runs from the church new year, September 1 of the year, through August 31 of the next one
+	CalendarYearChurch CalendarYearMode = "church"
+)
+
+func ParseCalendarYearMode(value string) (CalendarYearMode, error) {
+	switch mode := CalendarYearMode(value); mode {
+	case CalendarYearCivil, CalendarYearChurch:
+		return mode, nil
+	case "":
+		return CalendarYearCivil, nil
+	default:
+		return "", fmt.Errorf("unknown calendar year mode %q", value)
+	}
+}
+
+// StartDate returns the first day of the calendar year
+func (m CalendarYearMode) StartDate(year int) time.Time {
+	if m == CalendarYearChurch {
+		return time.Date(year, time.September, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NumberDays returns the length of the calendar year in days
+func (m CalendarYearMode) NumberDays(year int) int {
+	start := m.StartDate(year)
+	return DayNumber(start, start.AddDate(1, 0, 0)) - 1
+}
+
+// YearOf returns the calendar year the date belongs to
+func (m CalendarYearMode) YearOf(date time.Time) int {
+	if m == CalendarYearChurch && date.Month() < time.September {
+		return date.Year() - 1
+	}
+	return date.Year()
+}
+
+// Label formats the calendar year for people, a church year spans two civil ones
+func (m CalendarYearMode) Label(year int) string {
+	if m == CalendarYearChurch {
+		return fmt.Sprintf("%d/%d", year, year+1)
+	}
+	return fmt.Sprintf("%d", year)
+}
+
+// DayNumber returns the number of the date in a calendar starting at startDate, counting from 1
+func DayNumber(startDate, date time.Time) int {
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(startDate).Hours()/24) + 1
+}
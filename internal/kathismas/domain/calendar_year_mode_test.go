@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarYearMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       CalendarYearMode
+		year       int
+		wantStart  time.Time
+		wantDays   int
+		wantLabel  string
+		insideDate time.Time
+	}{
+		{
+			name:       "civil year",
+			mode:       CalendarYearCivil,
+			year:       2024,
+			wantStart:  time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			wantDays:   366,
+			wantLabel:  "2024",
+			insideDate: time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "church year with leap February",
+			mode:       CalendarYearChurch,
+			year:       2023,
+			wantStart:  time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+			wantDays:   366,
+			wantLabel:  "2023/2024",
+			insideDate: time.Date(2024, time.August, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "church year",
+			mode:       CalendarYearChurch,
+			year:       2025,
+			wantStart:  time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+			wantDays:   365,
+			wantLabel:  "2025/2026",
+			insideDate: time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStart, tt.mode.StartDate(tt.year))
+			assert.Equal(t, tt.wantDays, tt.mode.NumberDays(tt.year))
+			assert.Equal(t, tt.wantLabel, tt.mode.Label(tt.year))
+			assert.Equal(t, tt.year, tt.mode.YearOf(tt.insideDate))
+		})
+	}
+}
+
+func TestParseCalendarYearMode(t *testing.T) {
+	mode, err := ParseCalendarYearMode("")
+	require.NoError(t, err)
+	assert.Equal(t, CalendarYearCivil, mode)
+
+	mode, err = ParseCalendarYearMode("church")
+	require.NoError(t, err)
+	assert.Equal(t, CalendarYearChurch, mode)
+
+	_, err = ParseCalendarYearMode("lunar")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown calendar year mode")
+}
+
+func TestReaderGroup_FindCalendarForDate(t *testing.T) {
+	group, _ := NewReaderGroup("Test", 1)
+	require.NoError(t, group.UpdateYearMode(CalendarYearChurch))
+
+	civil2025 := NewCalendarOfReader(2025, CalendarYearCivil, 1, CalendarMap{1: {1: {1}}})
+	church2024 := NewCalendarOfReader(2024, CalendarYearChurch, 1, CalendarMap{1: {1: {1}}})
+	church2025 := NewCalendarOfReader(2025, CalendarYearChurch, 1, CalendarMap{1: {1: {1}}})
+	require.NoError(t, group.AddCalendar(*civil2025))
+	require.NoError(t, group.AddCalendar(*church2024))
+	require.NoError(t, group.AddCalendar(*church2025))
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		want     *CalendarOfReader
+		wantNone bool
+	}{
+		{"spring belongs to the church year started last autumn", time.Date(2025, time.March, 1, 9, 0, 0, 0, time.Local), church2024, false},
+		{"last day of the church year", time.Date(2025, time.August, 31, 23, 0, 0, 0, time.Local), church2024, false},
+		{"church new year", time.Date(2025, time.September, 1, 0, 0, 0, 0, time.Local), church2025, false},
+		{"only a civil calendar covers the date", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := group.FindCalendarForDate(tt.date)
+			if tt.wantNone {
+				assert.Nil(t, found)
+				return
+			}
+			require.NotNil(t, found)
+			assert.Equal(t, tt.want.ID, found.ID)
+		})
+	}
+
+	// a civil calendar is still found when the group has no calendar in its own mode
+	group.Calendars = []CalendarOfReader{*civil2025}
+	found := group.FindCalendarForDate(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+	require.NotNil(t, found)
+	assert.Equal(t, civil2025.ID, found.ID)
+}
+
+func TestReaderGroup_NextStartOffset(t *testing.T) {
+	group, _ := NewReaderGroup("Test", 7)
+	assert.Equal(t, 7, group.NextStartOffset(2025))
+
+	// reader #1 reads the 4th kathisma on the last reading day of 2024, December 30
+	civil2024 := NewCalendarOfReader(2024, CalendarYearCivil, 1, CalendarMap{1: {364: {3}, 365: {4}}})
+	require.NoError(t, group.AddCalendar(*civil2024))
+	assert.Equal(t, 5, group.NextStartOffset(2025))
+
+	// switching to the church year continues from the civil calendar up to August 31
+	require.NoError(t, group.UpdateYearMode(CalendarYearChurch))
+	civil2025 := NewCalendarOfReader(2025, CalendarYearCivil, 1, CalendarMap{1: {242: {11}, 243: {12}, 244: {13}}})
+	require.NoError(t, group.AddCalendar(*civil2025))
+	assert.Equal(t, 13, group.NextStartOffset(2025))
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LentReadingRule defines how much a group reads during Great Lent
+type LentReadingRule string
+
+const (
+	// LentReadingOrdinary keeps the load of the rest of the year, the Psalter once a day
+	LentReadingOrdinary LentReadingRule = "ordinary"
+	// LentReadingDoublePsalter follows the Typikon, which has the Psalter read twice a week in Lent
+	// instead of once: on weekdays the load doubles, on Wednesdays and Fridays it triples,
+	// Saturdays and Sundays keep the ordinary load
+	LentReadingDoublePsalter LentReadingRule = "double_psalter"
+)
+
+func ParseLentReadingRule(value string) (LentReadingRule, error) {
+	switch rule := LentReadingRule(value); rule {
+	case LentReadingOrdinary, LentReadingDoublePsalter:
+		return rule, nil
+	case "":
+		return LentReadingOrdinary, nil
+	default:
+		return "", fmt.Errorf("unknown lent reading rule %q", value)
+	}
+}
+
+// Load returns how many times the Psalter is read on a day of Great Lent
+func (r LentReadingRule) Load(weekday time.Weekday) int {
+	if r != LentReadingDoublePsalter {
+		return 1
+	}
+	switch weekday {
+	case time.Wednesday, time.Friday:
+		return 3
+	case time.Monday, time.Tuesday, time.Thursday:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLentReadingRule_Load(t *testing.T) {
+	week := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+	}
+
+	tests := []struct {
+		rule LentReadingRule
+		want []int
+	}{
+		{rule: LentReadingOrdinary, want: []int{1, 1, 1, 1, 1, 1, 1}},
+		{rule: LentReadingDoublePsalter, want: []int{2, 2, 3, 2, 3, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			loads := make([]int, 0, len(week))
+			total := 0
+			for _, weekday := range week {
+				loads = append(loads, tt.rule.Load(weekday))
+				total += tt.rule.Load(weekday)
+			}
+			assert.Equal(t, tt.want, loads)
+			if tt.rule == LentReadingDoublePsalter {
+				// the Psalter is read twice as often as in an ordinary week
+				assert.Equal(t, 2*len(week), total)
+			}
+		})
+	}
+}
+
+func TestParseLentReadingRule(t *testing.T) {
+	rule, err := ParseLentReadingRule("")
+	require.NoError(t, err)
+	assert.Equal(t, LentReadingOrdinary, rule)
+
+	rule, err = ParseLentReadingRule("double_psalter")
+	require.NoError(t, err)
+	assert.Equal(t, LentReadingDoublePsalter, rule)
+
+	_, err = ParseLentReadingRule("triple")
+	require.Error(t, err)
+}
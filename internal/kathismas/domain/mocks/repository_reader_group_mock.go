@@ -21,6 +21,9 @@ var _ domain.RepositoryReaderGroup = &RepositoryReaderGroupMock{}
 //
 //		// make and configure a mocked domain.RepositoryReaderGroup
 //		mockedRepositoryReaderGroup := &RepositoryReaderGroupMock{
+//			CountCalendarsFunc: func(ctx context.Context, id uuid.UUID) (int, error) {
+//				panic("mock out the CountCalendars method")
+//			},
 //			CreateFunc: func(ctx context.Context, group *domain.ReaderGroup) error {
 //				panic("mock out the Create method")
 //			},
@@ -33,6 +36,12 @@ var _ domain.RepositoryReaderGroup = &RepositoryReaderGroupMock{}
 //			GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
 //				panic("mock out the GetByID method")
 //			},
+//			GetByIDWithCalendarsFunc: func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+//				panic("mock out the GetByIDWithCalendars method")
+//			},
+//			GetByTelegramIDFunc: func(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error) {
+//				panic("mock out the GetByTelegramID method")
+//			},
 //			UpdateFunc: func(ctx context.Context, group *domain.ReaderGroup) error {
 //				panic("mock out the Update method")
 //			},
@@ -43,6 +52,9 @@ var _ domain.RepositoryReaderGroup = &RepositoryReaderGroupMock{}
 //
 //	}
 type RepositoryReaderGroupMock struct {
+	// CountCalendarsFunc mocks the CountCalendars method.
+	CountCalendarsFunc func(ctx context.Context, id uuid.UUID) (int, error)
+
 	// CreateFunc mocks the Create method.
 	CreateFunc func(ctx context.Context, group *domain.ReaderGroup) error
 
@@ -55,11 +67,24 @@ type RepositoryReaderGroupMock struct {
 	// GetByIDFunc mocks the GetByID method.
 	GetByIDFunc func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error)
 
+	// GetByIDWithCalendarsFunc mocks the GetByIDWithCalendars method.
+	GetByIDWithCalendarsFunc func(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error)
+
+	// GetByTelegramIDFunc mocks the GetByTelegramID method.
+	GetByTelegramIDFunc func(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error)
+
 	// UpdateFunc mocks the Update method.
 	UpdateFunc func(ctx context.Context, group *domain.ReaderGroup) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// CountCalendars holds details about calls to the CountCalendars method.
+		CountCalendars []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
 			// Ctx is the ctx argument value.
@@ -86,6 +111,20 @@ type RepositoryReaderGroupMock struct {
 			// ID is the id argument value.
 			ID uuid.UUID
 		}
+		// GetByIDWithCalendars holds details about calls to the GetByIDWithCalendars method.
+		GetByIDWithCalendars []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// GetByTelegramID holds details about calls to the GetByTelegramID method.
+		GetByTelegramID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TelegramID is the telegramID argument value.
+			TelegramID int64
+		}
 		// Update holds details about calls to the Update method.
 		Update []struct {
 			// Ctx is the ctx argument value.
@@ -94,11 +133,50 @@ type RepositoryReaderGroupMock struct {
 			Group *domain.ReaderGroup
 		}
 	}
-	lockCreate  sync.RWMutex
-	lockDelete  sync.RWMutex
-	lockGetAll  sync.RWMutex
-	lockGetByID sync.RWMutex
-	lockUpdate  sync.RWMutex
+	lockCountCalendars       sync.RWMutex
+	lockCreate               sync.RWMutex
+	lockDelete               sync.RWMutex
+	lockGetAll               sync.RWMutex
+	lockGetByID              sync.RWMutex
+	lockGetByIDWithCalendars sync.RWMutex
+	lockGetByTelegramID      sync.RWMutex
+	lockUpdate               sync.RWMutex
+}
+
+// CountCalendars calls CountCalendarsFunc.
+func (mock *RepositoryReaderGroupMock) CountCalendars(ctx context.Context, id uuid.UUID) (int, error) {
+	if mock.CountCalendarsFunc == nil {
+		panic("RepositoryReaderGroupMock.CountCalendarsFunc: method is nil but RepositoryReaderGroup.CountCalendars was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockCountCalendars.Lock()
+	mock.calls.CountCalendars = append(mock.calls.CountCalendars, callInfo)
+	mock.lockCountCalendars.Unlock()
+	return mock.CountCalendarsFunc(ctx, id)
+}
+
+// CountCalendarsCalls gets all the calls that were made to CountCalendars.
+// Check the length with:
+//
+//	len(mockedRepositoryReaderGroup.CountCalendarsCalls())
+func (mock *RepositoryReaderGroupMock) CountCalendarsCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockCountCalendars.RLock()
+	calls = mock.calls.CountCalendars
+	mock.lockCountCalendars.RUnlock()
+	return calls
 }
 
 // Create calls CreateFunc.
@@ -241,6 +319,78 @@ func (mock *RepositoryReaderGroupMock) GetByIDCalls() []struct {
 	return calls
 }
 
+// GetByIDWithCalendars calls GetByIDWithCalendarsFunc.
+func (mock *RepositoryReaderGroupMock) GetByIDWithCalendars(ctx context.Context, id uuid.UUID) (*domain.ReaderGroup, error) {
+	if mock.GetByIDWithCalendarsFunc == nil {
+		panic("RepositoryReaderGroupMock.GetByIDWithCalendarsFunc: method is nil but RepositoryReaderGroup.GetByIDWithCalendars was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByIDWithCalendars.Lock()
+	mock.calls.GetByIDWithCalendars = append(mock.calls.GetByIDWithCalendars, callInfo)
+	mock.lockGetByIDWithCalendars.Unlock()
+	return mock.GetByIDWithCalendarsFunc(ctx, id)
+}
+
+// GetByIDWithCalendarsCalls gets all the calls that were made to GetByIDWithCalendars.
+// Check the length with:
+//
+//	len(mockedRepositoryReaderGroup.GetByIDWithCalendarsCalls())
+func (mock *RepositoryReaderGroupMock) GetByIDWithCalendarsCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockGetByIDWithCalendars.RLock()
+	calls = mock.calls.GetByIDWithCalendars
+	mock.lockGetByIDWithCalendars.RUnlock()
+	return calls
+}
+
+// GetByTelegramID calls GetByTelegramIDFunc.
+func (mock *RepositoryReaderGroupMock) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.ReaderGroup, error) {
+	if mock.GetByTelegramIDFunc == nil {
+		panic("RepositoryReaderGroupMock.GetByTelegramIDFunc: method is nil but RepositoryReaderGroup.GetByTelegramID was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		TelegramID int64
+	}{
+		Ctx:        ctx,
+		TelegramID: telegramID,
+	}
+	mock.lockGetByTelegramID.Lock()
+	mock.calls.GetByTelegramID = append(mock.calls.GetByTelegramID, callInfo)
+	mock.lockGetByTelegramID.Unlock()
+	return mock.GetByTelegramIDFunc(ctx, telegramID)
+}
+
+// GetByTelegramIDCalls gets all the calls that were made to GetByTelegramID.
+// Check the length with:
+//
+//	len(mockedRepositoryReaderGroup.GetByTelegramIDCalls())
+func (mock *RepositoryReaderGroupMock) GetByTelegramIDCalls() []struct {
+	Ctx        context.Context
+	TelegramID int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		TelegramID int64
+	}
+	mock.lockGetByTelegramID.RLock()
+	calls = mock.calls.GetByTelegramID
+	mock.lockGetByTelegramID.RUnlock()
+	return calls
+}
+
 // Update calls UpdateFunc.
 func (mock *RepositoryReaderGroupMock) Update(ctx context.Context, group *domain.ReaderGroup) error {
 	if mock.UpdateFunc == nil {
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type NoReadingAnchor string
+
+const (
+	NoReadingAnchorPascha NoReadingAnchor = "pascha"
+	NoReadingAnchorFixed  NoReadingAnchor = "fixed"
+)
+
+// maxPaschaOffset bounds Pascha-relative periods to roughly half a year
+// on each side of the feast
+const maxPaschaOffset = 180
+
+type MonthDay struct {
+	Month time.Month
+	Day   int
+}
+
+func (md MonthDay) String() string {
+	return fmt.Sprintf("%02d.%02d", md.Day, md.Month)
+}
+
+// NoReadingPeriod is a window of days on which the Psalter is not read.
+// Pascha-anchored periods are defined by day offsets relative to Pascha,
+// fixed periods by calendar dates; a fixed period may wrap over New Year.
+type NoReadingPeriod struct {
+	ID         uuid.UUID
+	Name       string
+	Anchor     NoReadingAnchor
+	PaschaFrom int
+	PaschaTo   int
+	FixedFrom  MonthDay
+	FixedTo    MonthDay
+}
+
+func NewPaschaNoReadingPeriod(name string, from, to int) (*NoReadingPeriod, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no reading period name cannot be empty")
+	}
+	if from > to {
+		return nil, fmt.Errorf("no reading period start %d is after its end %d", from, to)
+	}
+	if from < -maxPaschaOffset || to > maxPaschaOffset {
+		return nil, fmt.Errorf("pascha offsets must be between -%d and %d days", maxPaschaOffset, maxPaschaOffset)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate uuid7: %w", err)
+	}
+
+	return &NoReadingPeriod{
+		ID:         id,
+		Name:       name,
+		Anchor:     NoReadingAnchorPascha,
+		PaschaFrom: from,
+		PaschaTo:   to,
+	}, nil
+}
+
+func NewFixedNoReadingPeriod(name string, from, to MonthDay) (*NoReadingPeriod, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no reading period name cannot be empty")
+	}
+	if err := validateMonthDay(from); err != nil {
+		return nil, fmt.Errorf("invalid period start: %w", err)
+	}
+	if err := validateMonthDay(to); err != nil {
+		return nil, fmt.Errorf("invalid period end: %w", err)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate uuid7: %w", err)
+	}
+
+	return &NoReadingPeriod{
+		ID:        id,
+		Name:      name,
+		Anchor:    NoReadingAnchorFixed,
+		FixedFrom: from,
+		FixedTo:   to,
+	}, nil
+}
+
+func UnmarshallNoReadingPeriod(
+	id uuid.UUID,
+	name string,
+	anchor NoReadingAnchor,
+	paschaFrom int,
+	paschaTo int,
+	fixedFrom MonthDay,
+	fixedTo MonthDay,
+) *NoReadingPeriod {
+	return &NoReadingPeriod{
+		ID:         id,
+		Name:       name,
+		Anchor:     anchor,
+		PaschaFrom: paschaFrom,
+		PaschaTo:   paschaTo,
+		FixedFrom:  fixedFrom,
+		FixedTo:    fixedTo,
+	}
+}
+
+// DefaultNoReadingPeriods returns the traditional pause from Holy Thursday
+// through Bright Saturday
+func DefaultNoReadingPeriods() []NoReadingPeriod {
+	period, err := NewPaschaNoReadingPeriod("Страстная и Светлая седмицы", -3, 6)
+	if err != nil {
+		panic(err)
+	}
+	return []NoReadingPeriod{*period}
+}
+
+// Bounds returns the first and the last day of the period occurring in the given year.
+// Pascha-anchored periods are resolved against the passed Pascha date.
+func (p NoReadingPeriod) Bounds(year int, pascha time.Time) (start, end time.Time) {
+	if p.Anchor == NoReadingAnchorPascha {
+		return pascha.AddDate(0, 0, p.PaschaFrom), pascha.AddDate(0, 0, p.PaschaTo)
+	}
+
+	start = time.Date(year, p.FixedFrom.Month, p.FixedFrom.Day, 0, 0, 0, 0, time.UTC)
+	end = time.Date(year, p.FixedTo.Month, p.FixedTo.Day, 0, 0, 0, 0, time.UTC)
+	if end.Before(start) {
+		end = end.AddDate(1, 0, 0)
+	}
+	return start, end
+}
+
+func (p NoReadingPeriod) Description() string {
+	if p.Anchor == NoReadingAnchorPascha {
+		return fmt.Sprintf("Пасха %+d … %+d дн.", p.PaschaFrom, p.PaschaTo)
+	}
+	return fmt.Sprintf("%s – %s", p.FixedFrom, p.FixedTo)
+}
+
+func validateMonthDay(md MonthDay) error {
+	if md.Month < time.January || md.Month > time.December {
+		return fmt.Errorf("month must be between 1 and 12, got %d", md.Month)
+	}
+	// 2000 is a leap year, so February 29 is accepted
+	daysInMonth := time.Date(2000, md.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if md.Day < 1 || md.Day > daysInMonth {
+		return fmt.Errorf("day must be between 1 and %d, got %d", daysInMonth, md.Day)
+	}
+	return nil
+}
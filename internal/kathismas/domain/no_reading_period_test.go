@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPaschaNoReadingPeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		periodName  string
+		from        int
+		to          int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "holy and bright week",
+			periodName: "Страстная и Светлая седмицы",
+			from:       -3,
+			to:         6,
+		},
+		{
+			name:       "single day",
+			periodName: "Пасха",
+			from:       0,
+			to:         0,
+		},
+		{
+			name:        "empty name",
+			periodName:  "",
+			from:        -3,
+			to:          6,
+			wantErr:     true,
+			errContains: "name cannot be empty",
+		},
+		{
+			name:        "start after end",
+			periodName:  "Test",
+			from:        6,
+			to:          -3,
+			wantErr:     true,
+			errContains: "is after its end",
+		},
+		{
+			name:        "offset out of range",
+			periodName:  "Test",
+			from:        -200,
+			to:          0,
+			wantErr:     true,
+			errContains: "pascha offsets must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := NewPaschaNoReadingPeriod(tt.periodName, tt.from, tt.to)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, period)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, period.ID)
+			assert.Equal(t, NoReadingAnchorPascha, period.Anchor)
+			assert.Equal(t, tt.from, period.PaschaFrom)
+			assert.Equal(t, tt.to, period.PaschaTo)
+		})
+	}
+}
+
+func TestNewFixedNoReadingPeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        MonthDay
+		to          MonthDay
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "within one year",
+			from: MonthDay{Month: time.August, Day: 14},
+			to:   MonthDay{Month: time.August, Day: 27},
+		},
+		{
+			name: "leap day",
+			from: MonthDay{Month: time.February, Day: 29},
+			to:   MonthDay{Month: time.March, Day: 1},
+		},
+		{
+			name:        "invalid month",
+			from:        MonthDay{Month: 13, Day: 1},
+			to:          MonthDay{Month: time.January, Day: 1},
+			wantErr:     true,
+			errContains: "month must be between 1 and 12",
+		},
+		{
+			name:        "invalid day",
+			from:        MonthDay{Month: time.January, Day: 1},
+			to:          MonthDay{Month: time.April, Day: 31},
+			wantErr:     true,
+			errContains: "day must be between 1 and 30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := NewFixedNoReadingPeriod("Test", tt.from, tt.to)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, NoReadingAnchorFixed, period.Anchor)
+			assert.Equal(t, tt.from, period.FixedFrom)
+			assert.Equal(t, tt.to, period.FixedTo)
+		})
+	}
+}
+
+func TestNoReadingPeriod_Bounds(t *testing.T) {
+	pascha := time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)
+
+	holyWeek, _ := NewPaschaNoReadingPeriod("Holy week", -3, 6)
+	start, end := holyWeek.Bounds(2025, pascha)
+	assert.Equal(t, time.Date(2025, time.April, 17, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, time.April, 26, 0, 0, 0, 0, time.UTC), end)
+
+	christmas, _ := NewFixedNoReadingPeriod(
+		"Святки",
+		MonthDay{Month: time.December, Day: 31},
+		MonthDay{Month: time.January, Day: 2},
+	)
+	start, end = christmas.Bounds(2025, pascha)
+	assert.Equal(t, time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestReaderGroup_NoReadingPeriods(t *testing.T) {
+	group, err := NewReaderGroup("Test", 1)
+	require.NoError(t, err)
+	require.Len(t, group.NoReadingPeriods, 1, "new group gets the default holy and bright week period")
+
+	period, _ := NewPaschaNoReadingPeriod("Пятидесятница", 49, 50)
+	require.NoError(t, group.AddNoReadingPeriod(*period))
+	assert.Len(t, group.NoReadingPeriods, 2)
+
+	err = group.AddNoReadingPeriod(*period)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	require.NoError(t, group.RemoveNoReadingPeriod(period.ID))
+	assert.Len(t, group.NoReadingPeriods, 1)
+
+	err = group.RemoveNoReadingPeriod(period.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found in group")
+}
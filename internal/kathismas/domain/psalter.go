@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+// PsalmRange is a run of psalms in the Septuagint numbering used by the Church Slavonic Psalter.
+// FirstVerse and LastVerse are set only when the range is a part of a single psalm,
+// as with the stases of the 17th kathisma.
+type PsalmRange struct {
+	FirstPsalm int
+	LastPsalm  int
+	FirstVerse int
+	LastVerse  int
+}
+
+func (r PsalmRange) String() string {
+	switch {
+	case r.FirstVerse != 0:
+		return fmt.Sprintf("Пс. %d:%d–%d", r.FirstPsalm, r.FirstVerse, r.LastVerse)
+	case r.FirstPsalm == r.LastPsalm:
+		return fmt.Sprintf("Пс. %d", r.FirstPsalm)
+	default:
+		return fmt.Sprintf("Пс. %d–%d", r.FirstPsalm, r.LastPsalm)
+	}
+}
+
+// KathismaContent describes the psalms of a kathisma and its three stases
+type KathismaContent struct {
+	Number int
+	Psalms PsalmRange
+	Stases [3]PsalmRange
+}
+
+func psalms(first, last int) PsalmRange {
+	return PsalmRange{FirstPsalm: first, LastPsalm: last}
+}
+
+func verses(psalm, first, last int) PsalmRange {
+	return PsalmRange{FirstPsalm: psalm, LastPsalm: psalm, FirstVerse: first, LastVerse: last}
+}
+
+var psalter = [KathismasCount]KathismaContent{
+	{Number: 1, Psalms: psalms(1, 8), Stases: [3]PsalmRange{psalms(1, 3), psalms(4, 6), psalms(7, 8)}},
+	{Number: 2, Psalms: psalms(9, 16), Stases: [3]PsalmRange{psalms(9, 10), psalms(11, 13), psalms(14, 16)}},
+	{Number: 3, Psalms: psalms(17, 23), Stases: [3]PsalmRange{psalms(17, 17), psalms(18, 20), psalms(21, 23)}},
+	{Number: 4, Psalms: psalms(24, 31), Stases: [3]PsalmRange{psalms(24, 26), psalms(27, 29), psalms(30, 31)}},
+	{Number: 5, Psalms: psalms(32, 36), Stases: [3]PsalmRange{psalms(32, 33), psalms(34, 35), psalms(36, 36)}},
+	{Number: 6, Psalms: psalms(37, 45), Stases: [3]PsalmRange{psalms(37, 39), psalms(40, 42), psalms(43, 45)}},
+	{Number: 7, Psalms: psalms(46, 54), Stases: [3]PsalmRange{psalms(46, 48), psalms(49, 50), psalms(51, 54)}},
+	{Number: 8, Psalms: psalms(55, 63), Stases: [3]PsalmRange{psalms(55, 57), psalms(58, 60), psalms(61, 63)}},
+	{Number: 9, Psalms: psalms(64, 69), Stases: [3]PsalmRange{psalms(64, 66), psalms(67, 67), psalms(68, 69)}},
+	{Number: 10, Psalms: psalms(70, 76), Stases: [3]PsalmRange{psalms(70, 71), psalms(72, 73), psalms(74, 76)}},
+	{Number: 11, Psalms: psalms(77, 84), Stases: [3]PsalmRange{psalms(77, 77), psalms(78, 80), psalms(81, 84)}},
+	{Number: 12, Psalms: psalms(85, 90), Stases: [3]PsalmRange{psalms(85, 87), psalms(88, 88), psalms(89, 90)}},
+	{Number: 13, Psalms: psalms(91, 100), Stases: [3]PsalmRange{psalms(91, 93), psalms(94, 96), psalms(97, 100)}},
+	{Number: 14, Psalms: psalms(101, 104), Stases: [3]PsalmRange{psalms(101, 102), psalms(103, 103), psalms(104, 104)}},
+	{Number: 15, Psalms: psalms(105, 108), Stases: [3]PsalmRange{psalms(105, 105), psalms(106, 106), psalms(107, 108)}},
+	{Number: 16, Psalms: psalms(109, 117), Stases: [3]PsalmRange{psalms(109, 111), psalms(112, 114), psalms(115, 117)}},
+	{Number: 17, Psalms: psalms(118, 118), Stases: [3]PsalmRange{verses(118, 1, 72), verses(118, 73, 131), verses(118, 132, 176)}},
+	{Number: 18, Psalms: psalms(119, 133), Stases: [3]PsalmRange{psalms(119, 123), psalms(124, 128), psalms(129, 133)}},
+	{Number: 19, Psalms: psalms(134, 142), Stases: [3]PsalmRange{psalms(134, 136), psalms(137, 139), psalms(140, 142)}},
+	{Number: 20, Psalms: psalms(143, 150), Stases: [3]PsalmRange{psalms(143, 144), psalms(145, 147), psalms(148, 150)}},
+}
+
+// GetKathismaContent returns the psalms and stases of the kathisma with the given number
+func GetKathismaContent(number int) (KathismaContent, error) {
+	if number < 1 || number > KathismasCount {
+		return KathismaContent{}, fmt.Errorf("kathisma number must be between 1 and %d, got %d", KathismasCount, number)
+	}
+	return psalter[number-1], nil
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKathismaContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		number      int
+		wantPsalms  string
+		wantStases  []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "first kathisma",
+			number:     1,
+			wantPsalms: "Пс. 1–8",
+			wantStases: []string{"Пс. 1–3", "Пс. 4–6", "Пс. 7–8"},
+		},
+		{
+			name:       "single psalm stases",
+			number:     12,
+			wantPsalms: "Пс. 85–90",
+			wantStases: []string{"Пс. 85–87", "Пс. 88", "Пс. 89–90"},
+		},
+		{
+			name:       "psalm 118 split by verses",
+			number:     17,
+			wantPsalms: "Пс. 118",
+			wantStases: []string{"Пс. 118:1–72", "Пс. 118:73–131", "Пс. 118:132–176"},
+		},
+		{
+			name:        "number too small",
+			number:      0,
+			wantErr:     true,
+			errContains: "kathisma number must be between 1 and 20",
+		},
+		{
+			name:        "number too large",
+			number:      21,
+			wantErr:     true,
+			errContains: "kathisma number must be between 1 and 20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := GetKathismaContent(tt.number)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.number, content.Number)
+			assert.Equal(t, tt.wantPsalms, content.Psalms.String())
+			stases := make([]string, 0, len(content.Stases))
+			for _, stasis := range content.Stases {
+				stases = append(stases, stasis.String())
+			}
+			assert.Equal(t, tt.wantStases, stases)
+		})
+	}
+}
+
+func TestPsalter_CoversAllPsalms(t *testing.T) {
+	nextPsalm := 1
+	for number := 1; number <= KathismasCount; number++ {
+		content, err := GetKathismaContent(number)
+		require.NoError(t, err)
+		assert.Equal(t, nextPsalm, content.Psalms.FirstPsalm, "kathisma %d", number)
+
+		// the stases split the kathisma without gaps
+		assert.Equal(t, content.Psalms.FirstPsalm, content.Stases[0].FirstPsalm, "kathisma %d", number)
+		for i := 1; i < len(content.Stases); i++ {
+			prev, cur := content.Stases[i-1], content.Stases[i]
+			if cur.FirstVerse != 0 {
+				assert.Equal(t, prev.LastVerse+1, cur.FirstVerse, "kathisma %d", number)
+				continue
+			}
+			assert.Equal(t, prev.LastPsalm+1, cur.FirstPsalm, "kathisma %d", number)
+		}
+		assert.Equal(t, content.Psalms.LastPsalm, content.Stases[2].LastPsalm, "kathisma %d", number)
+
+		nextPsalm = content.Psalms.LastPsalm + 1
+	}
+	assert.Equal(t, 151, nextPsalm)
+}
@@ -2,19 +2,30 @@ package domain
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
 
+// KathismasCount is the number of kathismas in the Psalter, it also bounds the size of a group
+const KathismasCount = 20
+
+// ReaderGroup is saved with optimistic concurrency: Version is the version the group was loaded at,
+// and RepositoryReaderGroup.Update fails with a VersionConflictError when it was saved since
 type ReaderGroup struct {
-	ID          uuid.UUID
-	Name        string
-	Readers     []PsalmReader
-	StartOffset int
-	Calendars   []CalendarOfReader
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID               uuid.UUID
+	Name             string
+	Readers          []PsalmReader
+	Size             int
+	StartOffset      int
+	YearMode         CalendarYearMode
+	LentRule         LentReadingRule
+	NoReadingPeriods []NoReadingPeriod
+	Calendars        []CalendarOfReader
+	Version          int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func NewReaderGroup(name string, startOffset int) (*ReaderGroup, error) {
@@ -30,13 +41,17 @@ func NewReaderGroup(name string, startOffset int) (*ReaderGroup, error) {
 	now := time.Now()
 
 	return &ReaderGroup{
-		ID:          id,
-		Name:        name,
-		Readers:     make([]PsalmReader, 0, 20),
-		StartOffset: startOffset,
-		Calendars:   make([]CalendarOfReader, 0),
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:               id,
+		Name:             name,
+		Readers:          make([]PsalmReader, 0, KathismasCount),
+		Size:             KathismasCount,
+		StartOffset:      startOffset,
+		YearMode:         CalendarYearCivil,
+		LentRule:         LentReadingOrdinary,
+		NoReadingPeriods: DefaultNoReadingPeriods(),
+		Calendars:        make([]CalendarOfReader, 0),
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}, nil
 }
 
@@ -44,25 +59,38 @@ func UnmarshallReaderGroup(
 	id uuid.UUID,
 	name string,
 	readers []PsalmReader,
+	size int,
 	startOffset int,
+	yearMode CalendarYearMode,
+	lentRule LentReadingRule,
+	noReadingPeriods []NoReadingPeriod,
 	calendars []CalendarOfReader,
+	version int,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *ReaderGroup {
 	return &ReaderGroup{
-		ID:          id,
-		Name:        name,
-		Readers:     readers,
-		StartOffset: startOffset,
-		Calendars:   calendars,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		ID:               id,
+		Name:             name,
+		Readers:          readers,
+		Size:             size,
+		StartOffset:      startOffset,
+		YearMode:         yearMode,
+		LentRule:         lentRule,
+		NoReadingPeriods: noReadingPeriods,
+		Calendars:        calendars,
+		Version:          version,
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
 	}
 }
 
 func (rg *ReaderGroup) AddReader(reader *PsalmReader) error {
-	if len(rg.Readers) >= 20 {
-		return fmt.Errorf("group already has maximum number of readers (20)")
+	if len(rg.Readers) >= rg.Size {
+		return fmt.Errorf("group already has maximum number of readers (%d)", rg.Size)
+	}
+	if reader.ReaderNumber < 1 || int(reader.ReaderNumber) > rg.Size {
+		return fmt.Errorf("reader number must be between 1 and %d, got %d", rg.Size, reader.ReaderNumber)
 	}
 
 	for _, r := range rg.Readers {
@@ -126,11 +154,13 @@ func (rg *ReaderGroup) AddCalendar(calendar CalendarOfReader) error {
 	return nil
 }
 
+// RemoveCalendarsByYear removes the calendars of the year in the current year mode of the group,
+// calendars kept from another mode stay so that a new calendar can continue them
 func (rg *ReaderGroup) RemoveCalendarsByYear(year int) int {
 	removed := 0
 	newCalendars := make([]CalendarOfReader, 0, len(rg.Calendars))
 	for _, cal := range rg.Calendars {
-		if cal.Year != year {
+		if cal.Year != year || cal.YearMode != rg.YearMode {
 			newCalendars = append(newCalendars, cal)
 		} else {
 			removed++
@@ -143,6 +173,76 @@ func (rg *ReaderGroup) RemoveCalendarsByYear(year int) int {
 	return removed
 }
 
+// FindCalendarForDate returns the calendar covering the date, preferring calendars
+// in the current year mode of the group, or nil when there is none
+func (rg *ReaderGroup) FindCalendarForDate(date time.Time) *CalendarOfReader {
+	var found *CalendarOfReader
+	for i := range rg.Calendars {
+		if _, ok := rg.Calendars[i].DayNumber(date); !ok {
+			continue
+		}
+		if rg.Calendars[i].YearMode == rg.YearMode {
+			return &rg.Calendars[i]
+		}
+		if found == nil {
+			found = &rg.Calendars[i]
+		}
+	}
+	return found
+}
+
+// CalendarForYear returns the calendar of the year in the current year mode of the group, or nil
+func (rg *ReaderGroup) CalendarForYear(year int) *CalendarOfReader {
+	for i := range rg.Calendars {
+		if rg.Calendars[i].Year == year && rg.Calendars[i].YearMode == rg.YearMode {
+			return &rg.Calendars[i]
+		}
+	}
+	return nil
+}
+
+// CalendarDrift describes a stored calendar whose start offset no longer continues the previous year
+type CalendarDrift struct {
+	Year                int
+	StoredStartOffset   int
+	ExpectedStartOffset int
+}
+
+// FindCalendarDrifts returns the calendars in the current year mode, ordered by year,
+// that follow a stored previous year but do not start where it ends
+func (rg *ReaderGroup) FindCalendarDrifts() []CalendarDrift {
+	var drifts []CalendarDrift
+	for i := range rg.Calendars {
+		calendar := &rg.Calendars[i]
+		if calendar.YearMode != rg.YearMode {
+			continue
+		}
+		previousDay := calendar.StartDate().AddDate(0, 0, -1)
+		if rg.FindCalendarForDate(previousDay) == nil {
+			continue
+		}
+		if expected := rg.NextStartOffset(calendar.Year); expected != calendar.StartOffset {
+			drifts = append(drifts, CalendarDrift{
+				Year:                calendar.Year,
+				StoredStartOffset:   calendar.StartOffset,
+				ExpectedStartOffset: expected,
+			})
+		}
+	}
+	slices.SortFunc(drifts, func(a, b CalendarDrift) int { return a.Year - b.Year })
+	return drifts
+}
+
+// NextStartOffset returns the start offset of the calendar of the given year in the current
+// year mode, continuing the calendar that covers the previous day if there is one
+func (rg *ReaderGroup) NextStartOffset(year int) int {
+	previousDay := rg.YearMode.StartDate(year).AddDate(0, 0, -1)
+	if previous := rg.FindCalendarForDate(previousDay); previous != nil {
+		return previous.CalculateStartOffsetAfter(previousDay)
+	}
+	return rg.StartOffset
+}
+
 func (rg *ReaderGroup) GetLatestCalendar() (*CalendarOfReader, error) {
 	if len(rg.Calendars) == 0 {
 		return nil, fmt.Errorf("no calendars found in group")
@@ -175,6 +275,71 @@ func (rg *ReaderGroup) UpdateStartOffset(startOffset int) error {
 	return nil
 }
 
+func (rg *ReaderGroup) AddNoReadingPeriod(period NoReadingPeriod) error {
+	for _, p := range rg.NoReadingPeriods {
+		if p.ID == period.ID {
+			return fmt.Errorf("no reading period with ID %s already exists in group", period.ID)
+		}
+	}
+
+	rg.NoReadingPeriods = append(rg.NoReadingPeriods, period)
+	rg.UpdatedAt = time.Now()
+	return nil
+}
+
+func (rg *ReaderGroup) RemoveNoReadingPeriod(periodID uuid.UUID) error {
+	for i, period := range rg.NoReadingPeriods {
+		if period.ID == periodID {
+			rg.NoReadingPeriods = append(rg.NoReadingPeriods[:i], rg.NoReadingPeriods[i+1:]...)
+			rg.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("no reading period with ID %s not found in group", periodID)
+}
+
+// UpdateSize changes the number of readers the kathismas are distributed over
+func (rg *ReaderGroup) UpdateSize(size int) error {
+	if err := validateGroupSize(size); err != nil {
+		return err
+	}
+	for _, r := range rg.Readers {
+		if int(r.ReaderNumber) > size {
+			return fmt.Errorf("reader number %d does not fit into group of %d readers", r.ReaderNumber, size)
+		}
+	}
+	rg.Size = size
+	rg.UpdatedAt = time.Now()
+	return nil
+}
+
+func (rg *ReaderGroup) UpdateYearMode(mode CalendarYearMode) error {
+	if mode != CalendarYearCivil && mode != CalendarYearChurch {
+		return fmt.Errorf("unknown calendar year mode %q", mode)
+	}
+	rg.YearMode = mode
+	rg.UpdatedAt = time.Now()
+	return nil
+}
+
+func (rg *ReaderGroup) UpdateLentRule(rule LentReadingRule) error {
+	if rule != LentReadingOrdinary && rule != LentReadingDoublePsalter {
+		return fmt.Errorf("unknown lent reading rule %q", rule)
+	}
+	rg.LentRule = rule
+	rg.UpdatedAt = time.Now()
+	return nil
+}
+
+// Snapshot copies the group so that the copy keeps the current state while the group is changed
+func (rg *ReaderGroup) Snapshot() *ReaderGroup {
+	snapshot := *rg
+	snapshot.Readers = slices.Clone(rg.Readers)
+	snapshot.NoReadingPeriods = slices.Clone(rg.NoReadingPeriods)
+	snapshot.Calendars = slices.Clone(rg.Calendars)
+	return &snapshot
+}
+
 func (rg *ReaderGroup) ReadersCount() int {
 	return len(rg.Readers)
 }
@@ -189,8 +354,8 @@ func (rg *ReaderGroup) GetAvailableReaderNumbers() []int8 {
 		usedNumbers[r.ReaderNumber] = true
 	}
 
-	available := make([]int8, 0, 20-len(rg.Readers))
-	for i := int8(1); i <= 20; i++ {
+	available := make([]int8, 0, max(rg.Size-len(rg.Readers), 0))
+	for i := int8(1); int(i) <= rg.Size; i++ {
 		if !usedNumbers[i] {
 			available = append(available, i)
 		}
@@ -199,7 +364,7 @@ func (rg *ReaderGroup) GetAvailableReaderNumbers() []int8 {
 }
 
 func (rg *ReaderGroup) IsReaderNumberAvailable(number int8) bool {
-	if number < 1 || number > 20 {
+	if number < 1 || int(number) > rg.Size {
 		return false
 	}
 	for _, r := range rg.Readers {
@@ -219,3 +384,10 @@ func validateReaderGroupParams(name string, startOffset int) error {
 	}
 	return nil
 }
+
+func validateGroupSize(size int) error {
+	if size < 1 || size > KathismasCount {
+		return fmt.Errorf("group size must be between 1 and %d, got %d", KathismasCount, size)
+	}
+	return nil
+}
@@ -353,6 +353,86 @@ func TestReaderGroup_UpdateStartOffset(t *testing.T) {
 	}
 }
 
+func TestReaderGroup_UpdateSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		readers     []int8
+		newSize     int
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "shrink empty group",
+			newSize: 5,
+		},
+		{
+			name:    "shrink group whose readers fit",
+			readers: []int8{1, 3},
+			newSize: 3,
+		},
+		{
+			name:        "reader does not fit",
+			readers:     []int8{1, 12},
+			newSize:     10,
+			wantErr:     true,
+			errContains: "reader number 12 does not fit into group of 10 readers",
+		},
+		{
+			name:        "size too small",
+			newSize:     0,
+			wantErr:     true,
+			errContains: "group size must be between 1 and 20",
+		},
+		{
+			name:        "size too large",
+			newSize:     21,
+			wantErr:     true,
+			errContains: "group size must be between 1 and 20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, _ := NewReaderGroup("Test", 1)
+			for _, number := range tt.readers {
+				reader, _ := NewPsalmReader("Reader", 0, "", number)
+				require.NoError(t, group.AddReader(reader))
+			}
+
+			err := group.UpdateSize(tt.newSize)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Equal(t, KathismasCount, group.Size)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.newSize, group.Size)
+		})
+	}
+}
+
+func TestReaderGroup_SmallGroupReaders(t *testing.T) {
+	group, _ := NewReaderGroup("Test", 1)
+	require.NoError(t, group.UpdateSize(3))
+
+	assert.Equal(t, []int8{1, 2, 3}, group.GetAvailableReaderNumbers())
+	assert.False(t, group.IsReaderNumberAvailable(4))
+
+	outOfRange, _ := NewPsalmReader("Reader4", 0, "", 4)
+	err := group.AddReader(outOfRange)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reader number must be between 1 and 3")
+
+	for i := int8(1); i <= 3; i++ {
+		reader, _ := NewPsalmReader("Reader", 0, "", i)
+		require.NoError(t, group.AddReader(reader))
+	}
+	assert.Empty(t, group.GetAvailableReaderNumbers())
+}
+
 func TestReaderGroup_GetAvailableReaderNumbers(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gofrs/uuid/v5"
@@ -15,6 +16,51 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("PsalmReader '%s' not found", e.PsalmReaderUUID)
 }
 
+// ReaderGroupNotFoundError means no group is stored under the ID
+type ReaderGroupNotFoundError struct {
+	GroupID uuid.UUID
+}
+
+func (e ReaderGroupNotFoundError) Error() string {
+	return fmt.Sprintf("reader group with ID %s not found", e.GroupID)
+}
+
+// TelegramReaderNotFoundError means no group has a reader with the Telegram ID
+type TelegramReaderNotFoundError struct {
+	TelegramID int64
+}
+
+func (e TelegramReaderNotFoundError) Error() string {
+	return fmt.Sprintf("reader with telegram ID %d not found", e.TelegramID)
+}
+
+// VersionConflictError means the group was saved by somebody else after it was loaded
+type VersionConflictError struct {
+	GroupID       uuid.UUID
+	Version       int
+	StoredVersion int
+}
+
+func (e VersionConflictError) Error() string {
+	return fmt.Sprintf("reader group %s was changed concurrently: version %d is stale, stored version is %d",
+		e.GroupID, e.Version, e.StoredVersion)
+}
+
+// IsVersionConflict reports whether the error is caused by a VersionConflictError
+func IsVersionConflict(err error) bool {
+	var conflict VersionConflictError
+	return errors.As(err, &conflict)
+}
+
+// TelegramIDTakenError means the Telegram account is already a reader of another group
+type TelegramIDTakenError struct {
+	TelegramID int64
+}
+
+func (e TelegramIDTakenError) Error() string {
+	return fmt.Sprintf("telegram ID %d is already registered in another group", e.TelegramID)
+}
+
 type RepositoryPsalmReader interface {
 	GetPsalmReaderTG(ctx context.Context, id uuid.UUID) (*PsalmReader, error)
 	CreatePsalmReaderTG(ctx context.Context, psalmReader *PsalmReader) error
@@ -25,10 +71,21 @@ type RepositoryCalendarOfReaders interface {
 	CreateCalendarOfReader(calendarOfReader *CalendarOfReader) error
 }
 
+// RepositoryReaderGroup stores groups apart from their calendars, which are only loaded
+// by GetByIDWithCalendars. Update saves the calendars the group holds and keeps the rest,
+// Delete removes the group together with all of its calendars.
+// Create and Update bump the version of the group, Update fails with a VersionConflictError
+// when the group was saved after it was loaded.
+// A Telegram account reads in one group only, Create and Update fail with a TelegramIDTakenError otherwise.
+// Missing groups fail with a ReaderGroupNotFoundError, GetAll returns the groups ordered by ID.
 type RepositoryReaderGroup interface {
 	Create(ctx context.Context, group *ReaderGroup) error
 	GetByID(ctx context.Context, id uuid.UUID) (*ReaderGroup, error)
+	GetByIDWithCalendars(ctx context.Context, id uuid.UUID) (*ReaderGroup, error)
+	// GetByTelegramID returns the group the reader with the Telegram ID belongs to
+	GetByTelegramID(ctx context.Context, telegramID int64) (*ReaderGroup, error)
 	GetAll(ctx context.Context) ([]ReaderGroup, error)
+	CountCalendars(ctx context.Context, id uuid.UUID) (int, error)
 	Update(ctx context.Context, group *ReaderGroup) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
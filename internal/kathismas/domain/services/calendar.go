@@ -3,6 +3,7 @@ package services
 import (
 	"time"
 
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
@@ -37,6 +38,17 @@ func GetCalendarYear(startCalendarDate time.Time, year int) map[int][]int {
 	return tableYear
 }
 
+// GetCalendarMonths lists the days of every month in a calendar of numberDays days starting at startDate.
+// A calendar spans at most a year, so the month number is enough to tell the months apart.
+func GetCalendarMonths(startDate time.Time, numberDays int) map[int][]int {
+	months := make(map[int][]int)
+	for i := range numberDays {
+		date := startDate.AddDate(0, 0, i)
+		months[int(date.Month())] = append(months[int(date.Month())], date.Day())
+	}
+	return months
+}
+
 func GetEasterDate(year int) time.Time {
 	a := year % 4
 	b := year % 7
@@ -47,15 +59,67 @@ func GetEasterDate(year int) time.Time {
 	day := ((d + e + 114) % 31) + 1
 
 	easter := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-	offset := year/100 - year/400 - 2
-	easter = easter.AddDate(0, 0, offset)
+	easter = easter.AddDate(0, 0, julianCalendarOffset(year))
 	return easter
 }
 
-func GetBoundaryDays(easterDay time.Time) (startNoReading, endNoReading time.Time) {
-	startNoReading = easterDay.AddDate(0, 0, -3)
-	endNoReading = easterDay.AddDate(0, 0, 6)
-	return startNoReading, endNoReading
+// julianCalendarOffset returns how many days the Julian calendar lags behind the Gregorian one
+// in the spring and summer of the year
+func julianCalendarOffset(year int) int {
+	return year/100 - year/400 - 2
+}
+
+// GetNoReadingDays returns the days of a calendar starting at startDate, numbered from 1,
+// which fall into any of the no reading periods
+func GetNoReadingDays(startDate time.Time, numberDays int, periods []domain.NoReadingPeriod) map[int]bool {
+	noReadingDays := make(map[int]bool)
+	endDate := startDate.AddDate(0, 0, numberDays-1)
+	for _, period := range periods {
+		// periods of the previous year may wrap over New Year into the calendar
+		for year := startDate.Year() - 1; year <= endDate.Year(); year++ {
+			periodStart, periodEnd := period.Bounds(year, GetEasterDate(year))
+			for date := periodStart; !date.After(periodEnd); date = date.AddDate(0, 0, 1) {
+				if date.Before(startDate) || date.After(endDate) {
+					continue
+				}
+				noReadingDays[domain.DayNumber(startDate, date)] = true
+			}
+		}
+	}
+	return noReadingDays
+}
+
+// GetReadingLoads returns the days of a calendar starting at startDate, numbered from 1,
+// on which the Psalter is read more than once under the lent reading rule, with the number of times
+func GetReadingLoads(startDate time.Time, numberDays int, lentRule domain.LentReadingRule) map[int]int {
+	loads := make(map[int]int)
+	endDate := startDate.AddDate(0, 0, numberDays-1)
+	for year := startDate.Year(); year <= endDate.Year(); year++ {
+		greatLent := GetPaschalion(year).GreatLent
+		for date := greatLent.Start; !date.After(greatLent.End); date = date.AddDate(0, 0, 1) {
+			if date.Before(startDate) || date.After(endDate) {
+				continue
+			}
+			if load := lentRule.Load(date.Weekday()); load > 1 {
+				loads[domain.DayNumber(startDate, date)] = load
+			}
+		}
+	}
+	return loads
+}
+
+// FindNoReadingPeriod returns the period the date falls into, or nil on a reading day
+func FindNoReadingPeriod(date time.Time, periods []domain.NoReadingPeriod) *domain.NoReadingPeriod {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for i := range periods {
+		for year := date.Year() - 1; year <= date.Year(); year++ {
+			periodStart, periodEnd := periods[i].Bounds(year, GetEasterDate(year))
+			if !date.Before(periodStart) && !date.After(periodEnd) {
+				return &periods[i]
+			}
+		}
+	}
+	return nil
 }
 
 func GetNumberDaysInYear(year int) int {
@@ -64,17 +128,17 @@ func GetNumberDaysInYear(year int) int {
 	return int(endYear.Sub(startYear).Hours() / 24)
 }
 
-func CreateCalendar(startDate time.Time, startKathisma, year int) *orderedmap.OrderedMap[int, map[int]int] {
+func CreateCalendar(startDate time.Time, startKathisma, year int) *orderedmap.OrderedMap[int, map[int][]int] {
 	if year == 0 {
 		year = startDate.Year()
 	}
-	easterDay := GetEasterDate(year)
-	startNoReading, endNoReading := GetBoundaryDays(easterDay)
 	numberDaysInYear := GetNumberDaysInYear(year)
+	startYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	noReadingDays := GetNoReadingDays(startYear, numberDaysInYear, domain.DefaultNoReadingPeriods())
 	totalKathismas := getTotalKathismas()
-	readersMap := orderedmap.New[int, map[int]int]()
+	readersMap := orderedmap.New[int, map[int][]int]()
 	for _, numberKathisma := range totalKathismas {
-		allKathismas := GetListDate(startNoReading, endNoReading, numberKathisma, numberDaysInYear, totalKathismas)
+		allKathismas := GetListDate(noReadingDays, numberKathisma, 1, numberDaysInYear, totalKathismas)
 		if startKathisma > 19 {
 			startKathisma = 0
 		}
@@ -84,23 +148,36 @@ func CreateCalendar(startDate time.Time, startKathisma, year int) *orderedmap.Or
 	return readersMap
 }
 
-func CreateCalendarForGroup(startOffset, year int) *orderedmap.OrderedMap[int, map[int]int] {
+// CreateCalendarForGroup distributes all 20 kathismas over readersCount readers every reading day.
+// Reader #1 starts the year with the startOffset kathisma, each next reader continues the run.
+// The year covers January to December or September to August depending on yearMode,
+// lentRule may have the Psalter read several times a day during Great Lent.
+func CreateCalendarForGroup(
+	startOffset, year, readersCount int,
+	yearMode domain.CalendarYearMode,
+	lentRule domain.LentReadingRule,
+	noReadingPeriods []domain.NoReadingPeriod,
+) *orderedmap.OrderedMap[int, map[int][]int] {
 	if year == 0 {
 		year = time.Now().Year()
 	}
-	easterDay := GetEasterDate(year)
-	startNoReading, endNoReading := GetBoundaryDays(easterDay)
-	numberDaysInYear := GetNumberDaysInYear(year)
+	numberDaysInYear := yearMode.NumberDays(year)
+	startYear := yearMode.StartDate(year)
+	noReadingDays := GetNoReadingDays(startYear, numberDaysInYear, noReadingPeriods)
+	readingLoads := GetReadingLoads(startYear, numberDaysInYear, lentRule)
 	totalKathismas := getTotalKathismas()
-	readersMap := orderedmap.New[int, map[int]int]()
+	readersMap := orderedmap.New[int, map[int][]int]()
 
 	currentKathisma := startOffset
-	for _, readerNumber := range totalKathismas {
-		allKathismas := GetListDate(startNoReading, endNoReading, currentKathisma, numberDaysInYear, totalKathismas)
-		readersMap.Set(readerNumber, allKathismas)
-		currentKathisma++
+	runOffset := 0
+	for i, share := range GetReaderShares(readersCount, len(totalKathismas)) {
+		allKathismas := GetListDate(noReadingDays, currentKathisma, share, numberDaysInYear, totalKathismas)
+		ApplyReadingLoads(allKathismas, readingLoads, runOffset, share, totalKathismas)
+		readersMap.Set(i+1, allKathismas)
+		runOffset += share
+		currentKathisma += share
 		if currentKathisma > 20 {
-			currentKathisma = 1
+			currentKathisma -= 20
 		}
 	}
 	return readersMap
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./internal/kathismas/domain/services -run Golden -update
+var update = flag.Bool("update", false, "rewrite golden files from the current output")
+
+const calendarsGoldenFile = "calendars_2000_2100.golden"
+
+// goldenCalendarLine describes one generated calendar in a single line: its size and a hash
+// of every reader's kathismas on every day, so that any change of the distribution shows up in a diff
+func goldenCalendarLine(yearMode domain.CalendarYearMode, year, startOffset int) string {
+	calendar := CreateCalendarForGroup(
+		startOffset, year, domain.KathismasCount, yearMode, domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+	)
+
+	numberDays := yearMode.NumberDays(year)
+	hash := sha256.New()
+	buf := make([]byte, 0, 64)
+	for pair := calendar.Oldest(); pair != nil; pair = pair.Next() {
+		for day := 1; day <= numberDays; day++ {
+			if _, ok := pair.Value[day]; !ok {
+				continue
+			}
+			buf = strconv.AppendInt(buf[:0], int64(pair.Key), 10)
+			buf = append(buf, ':')
+			buf = strconv.AppendInt(buf, int64(day), 10)
+			for _, kathisma := range pair.Value[day] {
+				buf = append(buf, ' ')
+				buf = strconv.AppendInt(buf, int64(kathisma), 10)
+			}
+			buf = append(buf, '\n')
+			_, _ = hash.Write(buf)
+		}
+	}
+
+	return fmt.Sprintf("%s %d offset=%02d days=%d reading=%d sha256=%s",
+		yearMode, year, startOffset, numberDays, len(calendar.Oldest().Value),
+		hex.EncodeToString(hash.Sum(nil))[:16],
+	)
+}
+
+func TestCreateCalendarForGroup_Golden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("golden calendars take a while")
+	}
+
+	var lines []string
+	for _, yearMode := range []domain.CalendarYearMode{domain.CalendarYearCivil, domain.CalendarYearChurch} {
+		for year := domain.MinSupportedYear; year <= domain.MaxSupportedYear; year++ {
+			for startOffset := 1; startOffset <= domain.KathismasCount; startOffset++ {
+				lines = append(lines, goldenCalendarLine(yearMode, year, startOffset))
+			}
+		}
+	}
+
+	path := filepath.Join("testdata", calendarsGoldenFile)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+		return
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err, "run the test with -update to create the golden file")
+	defer file.Close()
+
+	var want []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		want = append(want, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, len(want))
+
+	for i := range want {
+		assert.Equal(t, want[i], lines[i])
+	}
+}
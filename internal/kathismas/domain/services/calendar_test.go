@@ -0,0 +1,282 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNoReadingDays(t *testing.T) {
+	startYear := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// Pascha 2025 is April 20, year day 110
+	noReadingDays := GetNoReadingDays(startYear, 365, domain.DefaultNoReadingPeriods())
+	assert.Len(t, noReadingDays, 10)
+	assert.True(t, noReadingDays[107])
+	assert.True(t, noReadingDays[116])
+	assert.False(t, noReadingDays[106])
+	assert.False(t, noReadingDays[117])
+
+	newYear, err := domain.NewFixedNoReadingPeriod(
+		"Святки",
+		domain.MonthDay{Month: time.December, Day: 30},
+		domain.MonthDay{Month: time.January, Day: 2},
+	)
+	require.NoError(t, err)
+	noReadingDays = GetNoReadingDays(startYear, 365, []domain.NoReadingPeriod{*newYear})
+	assert.Equal(t, map[int]bool{1: true, 2: true, 364: true, 365: true}, noReadingDays)
+}
+
+func TestGetListDate_SeveralGaps(t *testing.T) {
+	noReadingDays := map[int]bool{3: true, 4: true, 10: true}
+
+	listDate := GetListDate(noReadingDays, 19, 1, 12, getTotalKathismas())
+
+	assert.Equal(t, map[int][]int{
+		1: {19}, 2: {20}, 5: {1}, 6: {2}, 7: {3}, 8: {4}, 9: {5}, 11: {6}, 12: {7},
+	}, listDate)
+}
+
+func TestGetListDate_SeveralKathismasPerDay(t *testing.T) {
+	listDate := GetListDate(map[int]bool{2: true}, 20, 3, 4, getTotalKathismas())
+
+	assert.Equal(t, map[int][]int{
+		1: {20, 1, 2}, 3: {1, 2, 3}, 4: {2, 3, 4},
+	}, listDate)
+}
+
+func TestGetReaderShares(t *testing.T) {
+	tests := []struct {
+		name    string
+		readers int
+		want    []int
+	}{
+		{name: "full group", readers: 20, want: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{name: "even split", readers: 10, want: []int{2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		{name: "uneven split", readers: 7, want: []int{3, 3, 3, 3, 3, 3, 2}},
+		{name: "single reader", readers: 1, want: []int{20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetReaderShares(tt.readers, domain.KathismasCount))
+		})
+	}
+}
+
+func TestCreateCalendarForGroup_WholePsalterEveryDay(t *testing.T) {
+	psalter := getTotalKathismas()
+	for _, readers := range []int{1, 3, 7, 10, 20} {
+		calendar := CreateCalendarForGroup(
+			5, 2024, readers, domain.CalendarYearCivil, domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+		)
+		require.Equal(t, readers, calendar.Len())
+
+		for day := 1; day <= 366; day++ {
+			var read []int
+			for pair := calendar.Oldest(); pair != nil; pair = pair.Next() {
+				read = append(read, pair.Value[day]...)
+			}
+			if len(read) == 0 {
+				continue
+			}
+			assert.ElementsMatch(t, psalter[:], read, "readers %d, day %d", readers, day)
+		}
+	}
+}
+
+func TestCreateCalendarForGroup_FollowsPeriods(t *testing.T) {
+	pentecost, err := domain.NewPaschaNoReadingPeriod("Пятидесятница", 49, 49)
+	require.NoError(t, err)
+	periods := append(domain.DefaultNoReadingPeriods(), *pentecost)
+
+	calendar := CreateCalendarForGroup(
+		1, 2025, domain.KathismasCount, domain.CalendarYearCivil, domain.LentReadingOrdinary, periods,
+	)
+
+	pentecostDay := GetEasterDate(2025).AddDate(0, 0, 49).YearDay()
+	for pair := calendar.Oldest(); pair != nil; pair = pair.Next() {
+		_, ok := pair.Value[pentecostDay]
+		assert.False(t, ok, "reader %d must not read on Pentecost", pair.Key)
+		assert.Len(t, pair.Value, 365-11)
+		// the cycle picks up with the next kathisma after the gap
+		before, after := pair.Value[pentecostDay-1], pair.Value[pentecostDay+1]
+		assert.Equal(t, []int{before[0]%20 + 1}, after)
+	}
+}
+
+func TestCreateCalendarForGroup_ChurchYear(t *testing.T) {
+	calendar := CreateCalendarForGroup(
+		3, 2025, domain.KathismasCount, domain.CalendarYearChurch,
+		domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+	)
+	readerOne, ok := calendar.Get(1)
+	require.True(t, ok)
+
+	// September 1, 2025 through August 31, 2026 starts with the offset kathisma
+	assert.Equal(t, []int{3}, readerOne[1])
+	_, ok = readerOne[365]
+	assert.True(t, ok)
+	_, ok = readerOne[366]
+	assert.False(t, ok)
+
+	// the church year 2025/2026 holds the Pascha of 2026 only
+	start := domain.CalendarYearChurch.StartDate(2025)
+	pascha2026 := domain.DayNumber(start, GetEasterDate(2026))
+	for day := pascha2026 - 3; day <= pascha2026+6; day++ {
+		_, ok = readerOne[day]
+		assert.False(t, ok, "day %d must be a no reading day", day)
+	}
+	assert.Len(t, readerOne, 365-10)
+}
+
+func TestGetCalendarMonths(t *testing.T) {
+	start := domain.CalendarYearChurch.StartDate(2023)
+	months := GetCalendarMonths(start, domain.CalendarYearChurch.NumberDays(2023))
+
+	assert.Len(t, months, 12)
+	assert.Len(t, months[int(time.September)], 30)
+	// February 2024 is a leap one
+	assert.Len(t, months[int(time.February)], 29)
+	assert.Equal(t, 1, months[int(time.January)][0])
+}
+
+func TestCreateCalendarForGroup_PassesVerification(t *testing.T) {
+	for _, yearMode := range []domain.CalendarYearMode{domain.CalendarYearCivil, domain.CalendarYearChurch} {
+		for _, readers := range []int{1, 6, 13, 20} {
+			generated := CreateCalendarForGroup(
+				11, 2027, readers, yearMode, domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+			)
+			calendarMap := make(domain.CalendarMap)
+			for pair := generated.Oldest(); pair != nil; pair = pair.Next() {
+				calendarMap[pair.Key] = pair.Value
+			}
+
+			calendar := domain.NewCalendarOfReader(2027, yearMode, 11, calendarMap)
+			require.NoError(t, calendar.Verify(), "mode %s, readers %d", yearMode, readers)
+			assert.Equal(t, yearMode.NumberDays(2027)-10, calendar.ReadingDays())
+		}
+	}
+}
+
+// orthodoxPascha lists the Gregorian dates of the Orthodox Pascha, month*100 + day
+var orthodoxPascha = map[int]int{
+	2000: 430, 2001: 415, 2002: 505, 2003: 427, 2004: 411, 2005: 501, 2006: 423, 2007: 408, 2008: 427, 2009: 419,
+	2010: 404, 2011: 424, 2012: 415, 2013: 505, 2014: 420, 2015: 412, 2016: 501, 2017: 416, 2018: 408, 2019: 428,
+	2020: 419, 2021: 502, 2022: 424, 2023: 416, 2024: 505, 2025: 420, 2026: 412, 2027: 502, 2028: 416, 2029: 408,
+	2030: 428, 2031: 413, 2032: 502, 2033: 424, 2034: 409, 2035: 429, 2036: 420, 2037: 405, 2038: 425, 2039: 417,
+	2040: 506, 2041: 421, 2042: 413, 2043: 503, 2044: 424, 2045: 409, 2046: 429, 2047: 421, 2048: 405, 2049: 425,
+	2050: 417, 2051: 507, 2052: 421, 2053: 413, 2054: 503, 2055: 418, 2056: 409, 2057: 429, 2058: 414, 2059: 504,
+	2060: 425, 2061: 410, 2062: 430, 2063: 422, 2064: 413, 2065: 426, 2066: 418, 2067: 410, 2068: 429, 2069: 414,
+	2070: 504, 2071: 419, 2072: 410, 2073: 430, 2074: 422, 2075: 407, 2076: 426, 2077: 418, 2078: 508, 2079: 423,
+	2080: 414, 2081: 504, 2082: 419, 2083: 411, 2084: 430, 2085: 415, 2086: 407, 2087: 427, 2088: 418, 2089: 501,
+	2090: 423, 2091: 408, 2092: 427, 2093: 419, 2094: 411, 2095: 424, 2096: 415, 2097: 505, 2098: 427, 2099: 412,
+	2100: 502,
+}
+
+func TestGetEasterDate_SupportedYears(t *testing.T) {
+	for year := domain.MinSupportedYear; year <= domain.MaxSupportedYear; year++ {
+		want, ok := orthodoxPascha[year]
+		require.True(t, ok, "no reference Pascha for %d", year)
+
+		pascha := GetEasterDate(year)
+		assert.Equal(t, time.Date(year, time.Month(want/100), want%100, 0, 0, 0, 0, time.UTC), pascha)
+		assert.Equal(t, time.Sunday, pascha.Weekday(), "year %d", year)
+	}
+}
+
+func TestGetNumberDaysInYear_LeapYears(t *testing.T) {
+	tests := []struct {
+		year int
+		want int
+	}{
+		{year: 2000, want: 366},
+		{year: 2023, want: 365},
+		{year: 2024, want: 366},
+		{year: 2100, want: 365},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetNumberDaysInYear(tt.year), "year %d", tt.year)
+		assert.Equal(t, tt.want, domain.CalendarYearCivil.NumberDays(tt.year), "year %d", tt.year)
+		// the church year holds the February of the next civil year
+		assert.Equal(t, GetNumberDaysInYear(tt.year+1), domain.CalendarYearChurch.NumberDays(tt.year), "year %d", tt.year)
+	}
+}
+
+func TestCreateCalendarForGroup_SupportedYears(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a calendar for every supported year takes a while")
+	}
+
+	for _, yearMode := range []domain.CalendarYearMode{domain.CalendarYearCivil, domain.CalendarYearChurch} {
+		for year := domain.MinSupportedYear; year <= domain.MaxSupportedYear; year++ {
+			readers := year%domain.KathismasCount + 1
+			startOffset := (year*7)%domain.KathismasCount + 1
+			generated := CreateCalendarForGroup(
+				startOffset, year, readers, yearMode, domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+			)
+			calendarMap := make(domain.CalendarMap)
+			for pair := generated.Oldest(); pair != nil; pair = pair.Next() {
+				calendarMap[pair.Key] = pair.Value
+			}
+
+			calendar := domain.NewCalendarOfReader(year, yearMode, startOffset, calendarMap)
+			require.NoError(t, calendar.Verify(), "mode %s, year %d", yearMode, year)
+			// the default pause is ten days around Pascha and falls into every calendar year once
+			assert.Equal(t, yearMode.NumberDays(year)-10, calendar.ReadingDays(), "mode %s, year %d", yearMode, year)
+			// the next year picks up where this one stops
+			readingDays := calendar.ReadingDays()
+			assert.Equal(t, (startOffset-1+readingDays)%domain.KathismasCount+1,
+				calendar.CalculateNextStartOffset(), "mode %s, year %d", yearMode, year)
+		}
+	}
+}
+
+func TestCreateCalendarForGroup_DoublePsalterInLent(t *testing.T) {
+	greatLent := GetPaschalion(2026).GreatLent
+	start := domain.CalendarYearCivil.StartDate(2026)
+	cleanMonday := domain.DayNumber(start, greatLent.Start)
+	psalter := getTotalKathismas()
+
+	for _, readers := range []int{1, 7, 20} {
+		ordinary := CreateCalendarForGroup(
+			4, 2026, readers, domain.CalendarYearCivil, domain.LentReadingOrdinary, domain.DefaultNoReadingPeriods(),
+		)
+		double := CreateCalendarForGroup(
+			4, 2026, readers, domain.CalendarYearCivil, domain.LentReadingDoublePsalter, domain.DefaultNoReadingPeriods(),
+		)
+
+		calendarMap := make(domain.CalendarMap)
+		for pair := double.Oldest(); pair != nil; pair = pair.Next() {
+			calendarMap[pair.Key] = pair.Value
+		}
+		calendar := domain.NewCalendarOfReader(2026, domain.CalendarYearCivil, 4, calendarMap)
+		require.NoError(t, calendar.Verify(), "readers %d", readers)
+
+		shares := GetReaderShares(readers, domain.KathismasCount)
+		for pair := double.Oldest(); pair != nil; pair = pair.Next() {
+			share := shares[pair.Key-1]
+			// Monday twice the share, Wednesday three times, Saturday and Sunday the ordinary one
+			assert.Len(t, pair.Value[cleanMonday], 2*share, "readers %d, reader %d", readers, pair.Key)
+			assert.Len(t, pair.Value[cleanMonday+2], 3*share, "readers %d, reader %d", readers, pair.Key)
+			assert.Len(t, pair.Value[cleanMonday+5], share, "readers %d, reader %d", readers, pair.Key)
+			assert.Len(t, pair.Value[cleanMonday+6], share, "readers %d, reader %d", readers, pair.Key)
+
+			// outside of Lent the calendar is the ordinary one
+			ordinaryDays, _ := ordinary.Get(pair.Key)
+			assert.Equal(t, ordinaryDays[cleanMonday-1], pair.Value[cleanMonday-1])
+			assert.Equal(t, ordinaryDays[cleanMonday+5], pair.Value[cleanMonday+5])
+			assert.Equal(t, ordinaryDays[365], pair.Value[365])
+		}
+
+		var read []int
+		for pair := double.Oldest(); pair != nil; pair = pair.Next() {
+			read = append(read, pair.Value[cleanMonday+2]...)
+		}
+		assert.ElementsMatch(t, append(append(psalter[:], psalter[:]...), psalter[:]...), read, "readers %d", readers)
+	}
+}
@@ -1,77 +1,67 @@
 package services
 
-import (
-	"maps"
-	"time"
-)
-
-func getGenLoop(startLoop, endLoop int) []int {
-	newLoop := make([]int, 0)
-	for i := startLoop; i < endLoop+1; i++ {
-		newLoop = append(newLoop, i)
-	}
-	return newLoop
-}
-
-func cycleSlice(genLoop []int, loopFromTotalKathisma [20]int) map[int]int {
-	loopFirst := make(map[int]int)
-	kathismaIndex := 0
-	for _, day := range genLoop {
-		loopFirst[day] = loopFromTotalKathisma[kathismaIndex]
-		kathismaIndex++
-		if kathismaIndex >= len(loopFromTotalKathisma) {
-			kathismaIndex = 0
+// GetListDate distributes kathismas over the days of a calendar for one reader.
+// On the first reading day the reader gets kathismasPerDay kathismas in a row
+// starting with startKathisma, every next reading day the run moves on by one
+// kathisma, so the cycle picks up right after each gap.
+func GetListDate(
+	noReadingDays map[int]bool,
+	startKathisma int,
+	kathismasPerDay int,
+	numberDays int,
+	loopFromTotalKathisma [20]int,
+) map[int][]int {
+	listDate := make(map[int][]int, numberDays)
+	kathismaIndex := startKathisma - 1
+	for day := 1; day <= numberDays; day++ {
+		if noReadingDays[day] {
+			continue
+		}
+		dayKathismas := make([]int, 0, kathismasPerDay)
+		for i := range kathismasPerDay {
+			dayKathismas = append(dayKathismas, loopFromTotalKathisma[(kathismaIndex+i)%len(loopFromTotalKathisma)])
 		}
+		listDate[day] = dayKathismas
+		kathismaIndex = (kathismaIndex + 1) % len(loopFromTotalKathisma)
 	}
-	return loopFirst
+	return listDate
 }
 
-func getCalendarMap(
-	endNumberKathismaFirstLoop, numberDaysInYear, startZeroLoopSecond, stepKathisma int, loopFromTotalKathisma [20]int,
-) (loopSecond, zeroLoopSecond map[int]int) {
-	startNumberKathismaZeroLoopSecond := endNumberKathismaFirstLoop + stepKathisma
-	zeroLoopSecond = make(map[int]int)
-	count := 0
-	for kathisma := startNumberKathismaZeroLoopSecond; kathisma < 21; kathisma++ {
-		zeroLoopSecond[count+startZeroLoopSecond] = kathisma
-		count++
+// ApplyReadingLoads widens the runs of one reader on the days the Psalter is read several times.
+// On such a day the group goes through the Psalter load times in a row, so the reader whose run
+// starts runOffset kathismas after reader #1 gets load*share kathismas starting load*runOffset after it.
+// The cycle of the next days is not affected.
+func ApplyReadingLoads(
+	listDate map[int][]int,
+	loads map[int]int,
+	runOffset int,
+	kathismasPerDay int,
+	loopFromTotalKathisma [20]int,
+) {
+	total := len(loopFromTotalKathisma)
+	for day, load := range loads {
+		kathismas, ok := listDate[day]
+		if !ok || len(kathismas) == 0 {
+			continue
+		}
+		firstIndex := ((kathismas[0]-1-runOffset)%total + total + load*runOffset) % total
+		dayKathismas := make([]int, 0, load*kathismasPerDay)
+		for i := range load * kathismasPerDay {
+			dayKathismas = append(dayKathismas, loopFromTotalKathisma[(firstIndex+i)%total])
+		}
+		listDate[day] = dayKathismas
 	}
-	startLoopSecond := startZeroLoopSecond + len(zeroLoopSecond)
-	genLoopSecond := getGenLoop(startLoopSecond, numberDaysInYear)
-	loopSecond = cycleSlice(genLoopSecond, loopFromTotalKathisma)
-	return loopSecond, zeroLoopSecond
 }
 
-func GetListDate(
-	startNoReading time.Time,
-	endNoReading time.Time,
-	startKathisma int,
-	numberDaysInYear int,
-	loopFromTotalKathisma [20]int,
-) map[int]int {
-	stepKathisma := 1
-	zeroLoopFirst := make(map[int]int)
-	count := 1
-	for i := startKathisma; i < 21; i++ {
-		zeroLoopFirst[count] = i
-		count++
+// GetReaderShares splits the kathismas of a day into runs as even as possible
+// and returns how many kathismas each of the readers gets
+func GetReaderShares(readersCount, kathismasCount int) []int {
+	shares := make([]int, readersCount)
+	for i := range shares {
+		shares[i] = kathismasCount / readersCount
+		if i < kathismasCount%readersCount {
+			shares[i]++
+		}
 	}
-	startLoopFirst := len(zeroLoopFirst) + stepKathisma
-	endLoopFirst := startNoReading.YearDay() - 1
-	startZeroLoopSecond := endNoReading.YearDay() + 1
-	genLoopFirst := getGenLoop(startLoopFirst, endLoopFirst)
-	loopFirst := cycleSlice(genLoopFirst, loopFromTotalKathisma)
-	endNumberKathismaFirstLoop := loopFirst[endLoopFirst]
-	loopSecond, zeroLoopSecond := getCalendarMap(
-		endNumberKathismaFirstLoop,
-		numberDaysInYear,
-		startZeroLoopSecond,
-		stepKathisma,
-		loopFromTotalKathisma,
-	)
-	maps.Copy(zeroLoopFirst, loopFirst)
-	maps.Copy(zeroLoopSecond, loopSecond)
-
-	maps.Copy(zeroLoopFirst, zeroLoopSecond)
-	return zeroLoopFirst
+	return shares
 }
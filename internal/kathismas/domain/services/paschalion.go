@@ -0,0 +1,52 @@
+package services
+
+import "time"
+
+// MovableFeast is a feast or a fast whose date depends on Pascha. A feast lasts one day,
+// so its End equals its Start
+type MovableFeast struct {
+	Key   string
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Paschalion holds Pascha of the year and the feasts moving with it, in the order they come
+type Paschalion struct {
+	Year         int
+	Pascha       time.Time
+	GreatLent    MovableFeast
+	PalmSunday   MovableFeast
+	Ascension    MovableFeast
+	Pentecost    MovableFeast
+	ApostlesFast MovableFeast
+}
+
+// GetPaschalion returns the movable feasts of the year in the Gregorian calendar.
+// Great Lent runs from Clean Monday through the Friday before Lazarus Saturday,
+// the Apostles' Fast from the Monday after All Saints through the eve of Saints Peter and Paul, June 29 old style.
+func GetPaschalion(year int) Paschalion {
+	pascha := GetEasterDate(year)
+	return Paschalion{
+		Year:       year,
+		Pascha:     pascha,
+		GreatLent:  movableFeast("great_lent", "Великий пост", pascha.AddDate(0, 0, -48), pascha.AddDate(0, 0, -9)),
+		PalmSunday: movableFeast("palm_sunday", "Вход Господень в Иерусалим", pascha.AddDate(0, 0, -7), pascha.AddDate(0, 0, -7)),
+		Ascension:  movableFeast("ascension", "Вознесение Господне", pascha.AddDate(0, 0, 39), pascha.AddDate(0, 0, 39)),
+		Pentecost:  movableFeast("pentecost", "День Святой Троицы", pascha.AddDate(0, 0, 49), pascha.AddDate(0, 0, 49)),
+		ApostlesFast: movableFeast(
+			"apostles_fast", "Петров пост",
+			pascha.AddDate(0, 0, 57),
+			time.Date(year, time.June, 28+julianCalendarOffset(year), 0, 0, 0, 0, time.UTC),
+		),
+	}
+}
+
+// Feasts lists the movable feasts in the order they come
+func (p Paschalion) Feasts() []MovableFeast {
+	return []MovableFeast{p.GreatLent, p.PalmSunday, p.Ascension, p.Pentecost, p.ApostlesFast}
+}
+
+func movableFeast(key, name string, start, end time.Time) MovableFeast {
+	return MovableFeast{Key: key, Name: name, Start: start, End: end}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPaschalion(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		year int
+		want Paschalion
+	}{
+		{
+			year: 2025,
+			want: Paschalion{
+				Year:         2025,
+				Pascha:       date(2025, time.April, 20),
+				GreatLent:    movableFeast("great_lent", "Великий пост", date(2025, time.March, 3), date(2025, time.April, 11)),
+				PalmSunday:   movableFeast("palm_sunday", "Вход Господень в Иерусалим", date(2025, time.April, 13), date(2025, time.April, 13)),
+				Ascension:    movableFeast("ascension", "Вознесение Господне", date(2025, time.May, 29), date(2025, time.May, 29)),
+				Pentecost:    movableFeast("pentecost", "День Святой Троицы", date(2025, time.June, 8), date(2025, time.June, 8)),
+				ApostlesFast: movableFeast("apostles_fast", "Петров пост", date(2025, time.June, 16), date(2025, time.July, 11)),
+			},
+		},
+		{
+			year: 2100,
+			want: Paschalion{
+				Year:         2100,
+				Pascha:       date(2100, time.May, 2),
+				GreatLent:    movableFeast("great_lent", "Великий пост", date(2100, time.March, 15), date(2100, time.April, 23)),
+				PalmSunday:   movableFeast("palm_sunday", "Вход Господень в Иерусалим", date(2100, time.April, 25), date(2100, time.April, 25)),
+				Ascension:    movableFeast("ascension", "Вознесение Господне", date(2100, time.June, 10), date(2100, time.June, 10)),
+				Pentecost:    movableFeast("pentecost", "День Святой Троицы", date(2100, time.June, 20), date(2100, time.June, 20)),
+				ApostlesFast: movableFeast("apostles_fast", "Петров пост", date(2100, time.June, 28), date(2100, time.July, 12)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetPaschalion(tt.year), "year %d", tt.year)
+	}
+}
+
+func TestGetPaschalion_SupportedYears(t *testing.T) {
+	for year := domain.MinSupportedYear; year <= domain.MaxSupportedYear; year++ {
+		paschalion := GetPaschalion(year)
+
+		assert.Equal(t, time.Monday, paschalion.GreatLent.Start.Weekday(), "year %d", year)
+		assert.Equal(t, time.Sunday, paschalion.PalmSunday.Start.Weekday(), "year %d", year)
+		assert.Equal(t, time.Thursday, paschalion.Ascension.Start.Weekday(), "year %d", year)
+		assert.Equal(t, time.Sunday, paschalion.Pentecost.Start.Weekday(), "year %d", year)
+		// the latest Pascha still leaves a week of the Apostles' Fast
+		assert.True(t, paschalion.ApostlesFast.Start.Before(paschalion.ApostlesFast.End), "year %d", year)
+		assert.Len(t, paschalion.Feasts(), 5)
+	}
+}
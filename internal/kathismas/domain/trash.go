@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// TrashKind tells what a trash item keeps
+type TrashKind string
+
+const (
+	TrashKindGroup  TrashKind = "group"
+	TrashKindReader TrashKind = "reader"
+)
+
+// TrashItem keeps a deleted group with its calendars, or a reader removed from a group, until ExpiresAt.
+// GroupID and GroupName are those of the deleted group or of the group the reader was removed from.
+type TrashItem struct {
+	ID        uuid.UUID
+	Kind      TrashKind
+	GroupID   uuid.UUID
+	GroupName string
+	Group     *ReaderGroup
+	Reader    *PsalmReader
+	DeletedAt time.Time
+	ExpiresAt time.Time
+}
+
+// NewGroupTrashItem keeps the group as it is, it should be loaded with its calendars
+func NewGroupTrashItem(group *ReaderGroup, retention time.Duration) (*TrashItem, error) {
+	item, err := newTrashItem(TrashKindGroup, group, retention)
+	if err != nil {
+		return nil, err
+	}
+	item.Group = group.Snapshot()
+	return item, nil
+}
+
+func NewReaderTrashItem(group *ReaderGroup, reader PsalmReader, retention time.Duration) (*TrashItem, error) {
+	item, err := newTrashItem(TrashKindReader, group, retention)
+	if err != nil {
+		return nil, err
+	}
+	item.Reader = &reader
+	return item, nil
+}
+
+func newTrashItem(kind TrashKind, group *ReaderGroup, retention time.Duration) (*TrashItem, error) {
+	if retention <= 0 {
+		return nil, fmt.Errorf("trash retention must be positive, got %s", retention)
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate uuid7: %w", err)
+	}
+	now := time.Now()
+	return &TrashItem{
+		ID:        id,
+		Kind:      kind,
+		GroupID:   group.ID,
+		GroupName: group.Name,
+		DeletedAt: now,
+		ExpiresAt: now.Add(retention),
+	}, nil
+}
+
+func (t TrashItem) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// TrashItemNotFoundError means the item was restored, purged or never existed
+type TrashItemNotFoundError struct {
+	ItemID uuid.UUID
+}
+
+func (e TrashItemNotFoundError) Error() string {
+	return fmt.Sprintf("trash item with ID %s not found", e.ItemID)
+}
+
+// RepositoryTrash keeps the items apart from the groups. Missing items fail with a TrashItemNotFoundError,
+// List returns the most recently deleted items first.
+type RepositoryTrash interface {
+	Put(ctx context.Context, item *TrashItem) error
+	GetByID(ctx context.Context, id uuid.UUID) (*TrashItem, error)
+	List(ctx context.Context) ([]TrashItem, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
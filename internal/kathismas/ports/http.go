@@ -3,8 +3,12 @@ package ports
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -17,9 +21,11 @@ import (
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/query"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/config"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/go-pkgz/rest"
+	"github.com/go-pkgz/rest/realip"
 	"github.com/gofrs/uuid/v5"
 )
 
@@ -58,6 +64,12 @@ func (s *Server) Run(ctx context.Context, port int) {
 		"add": func(a, b int) int {
 			return a + b
 		},
+		"auditAction": func(action string) string {
+			if label, ok := auditActionLabels[domain.AuditAction(action)]; ok {
+				return label
+			}
+			return action
+		},
 	}
 	s.templates = template.Must(template.New("").Funcs(funcMap).ParseGlob(s.TemplLocation))
 
@@ -76,7 +88,7 @@ func (s *Server) Run(ctx context.Context, port int) {
 
 func (s *Server) router() *chi.Mux {
 	router := chi.NewRouter()
-	router.Use(rest.AppInfo("for-twenty-readers", "DjaPy", s.Version), rest.Ping)
+	router.Use(rest.AppInfo("for-twenty-readers", "DjaPy", s.Version), rest.Ping, webActor)
 
 	router.Get("/", s.groupsPage)
 
@@ -86,14 +98,30 @@ func (s *Server) router() *chi.Mux {
 	router.Get("/groups", s.groupsPage)
 	router.Get("/groups/list", s.listGroupsPartial)
 	router.Post("/groups", s.createGroup)
+	router.Post("/groups/import", s.importGroup)
 	router.Get("/groups/{id}", s.getGroupPage)
 	router.Put("/groups/{id}", s.updateGroup)
 	router.Delete("/groups/{id}", s.deleteGroup)
+	router.Get("/groups/{id}/export", s.exportGroup)
 	router.Post("/groups/{id}/readers", s.addReaderToGroup)
 	router.Delete("/groups/{id}/readers/{readerId}", s.removeReaderFromGroup)
 	router.Post("/groups/{id}/generate", s.generateCalendarForGroup)
 	router.Post("/groups/{id}/regenerate", s.regenerateCalendarForGroup)
+	router.Post("/groups/{id}/generate-range", s.generateCalendarRangeForGroup)
 	router.Get("/groups/{id}/current-kathisma", s.getCurrentKathisma)
+	router.Get("/groups/{id}/calendars/{year}/verify", s.verifyCalendar)
+	router.Post("/groups/{id}/no-reading-periods", s.addNoReadingPeriod)
+	router.Delete("/groups/{id}/no-reading-periods/{periodId}", s.removeNoReadingPeriod)
+
+	router.Get("/paschalion", s.getPaschalion)
+	router.Get("/paschalion/{year}", s.getPaschalion)
+
+	router.Get("/audit", s.listAuditEntries)
+
+	router.Get("/trash", s.trashPage)
+	router.Post("/trash/{id}/restore", s.restoreFromTrash)
+
+	router.With(s.backupToken).Get("/backup", s.downloadBackup)
 
 	return router
 }
@@ -140,9 +168,24 @@ func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	yearMode, err := domain.ParseCalendarYearMode(r.FormValue("year_mode"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lentRule, err := domain.ParseLentReadingRule(r.FormValue("lent_rule"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	cmd := command.CreateReaderGroup{
 		Name:        r.FormValue("name"),
 		StartOffset: atoi(r.FormValue("start_offset")),
+		Size:        atoi(r.FormValue("size")),
+		YearMode:    yearMode,
+		LentRule:    lentRule,
 	}
 
 	groupID, err := s.App.Commands.CreateReaderGroup.Handle(r.Context(), cmd)
@@ -165,10 +208,14 @@ func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
 			Groups: []query.ReaderGroupDTO{{
 				ID:             group.ID,
 				Name:           group.Name,
+				Size:           group.Size,
+				YearMode:       group.YearMode,
+				LentRule:       group.LentRule,
 				StartOffset:    group.StartOffset,
 				ReadersCount:   len(group.Readers),
 				CalendarsCount: 0,
 				CreatedAt:      group.CreatedAt,
+				Version:        group.Version,
 			}},
 			CurrentYear: time.Now().Year(),
 		}
@@ -202,15 +249,25 @@ func (s *Server) getGroupPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditEntries, err := s.App.Queries.ListAuditEntries.Handle(r.Context(), query.ListAuditEntries{
+		Filter: domain.AuditFilter{GroupID: id, Limit: groupPageAuditEntries},
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	data := struct {
 		Title           string
 		ContentTemplate string
 		CurrentYear     int
+		AuditEntries    []query.AuditEntryDTO
 		*query.ReaderGroupDetailDTO
 	}{
 		Title:                group.Name,
 		ContentTemplate:      "group-detail-content",
-		CurrentYear:          time.Now().Year(),
+		CurrentYear:          domain.CalendarYearMode(group.YearMode).YearOf(time.Now()),
+		AuditEntries:         auditEntries,
 		ReaderGroupDetailDTO: group,
 	}
 
@@ -236,8 +293,8 @@ func (s *Server) addReaderToGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	readerNumber := atoi(r.FormValue("reader_number"))
-	if readerNumber < 1 || readerNumber > 20 {
-		http.Error(w, "reader number must be between 1 and 20", http.StatusBadRequest)
+	if readerNumber < 1 || readerNumber > domain.KathismasCount {
+		http.Error(w, fmt.Sprintf("reader number must be between 1 and %d", domain.KathismasCount), http.StatusBadRequest)
 		return
 	}
 
@@ -256,7 +313,7 @@ func (s *Server) addReaderToGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.App.Commands.AddReaderToGroup.Handle(r.Context(), cmd); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeCommandError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -284,10 +341,87 @@ func (s *Server) removeReaderFromGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.App.Commands.RemoveReaderFromGroup.Handle(r.Context(), cmd); err != nil {
+		writeCommandError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/groups/%s", idStr), http.StatusSeeOther)
+}
+
+func (s *Server) addNoReadingPeriod(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	groupID, err := uuid.FromString(idStr)
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	cmd := command.AddNoReadingPeriod{
+		GroupID: groupID,
+		Name:    r.FormValue("name"),
+		Anchor:  domain.NoReadingAnchor(r.FormValue("anchor")),
+	}
+	switch cmd.Anchor {
+	case domain.NoReadingAnchorPascha:
+		cmd.PaschaFrom = atoi(r.FormValue("pascha_from"))
+		cmd.PaschaTo = atoi(r.FormValue("pascha_to"))
+	case domain.NoReadingAnchorFixed:
+		fixedFrom, errFrom := parseMonthDay(r.FormValue("fixed_from"))
+		if errFrom != nil {
+			http.Error(w, errFrom.Error(), http.StatusBadRequest)
+			return
+		}
+		fixedTo, errTo := parseMonthDay(r.FormValue("fixed_to"))
+		if errTo != nil {
+			http.Error(w, errTo.Error(), http.StatusBadRequest)
+			return
+		}
+		cmd.FixedFrom = fixedFrom
+		cmd.FixedTo = fixedTo
+	}
+
+	if err := s.App.Commands.AddNoReadingPeriod.Handle(r.Context(), cmd); err != nil {
+		writeCommandError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/groups/%s", idStr), http.StatusSeeOther)
+}
+
+func (s *Server) removeNoReadingPeriod(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	groupID, err := uuid.FromString(idStr)
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	periodID, err := uuid.FromString(chi.URLParam(r, "periodId"))
+	if err != nil {
+		http.Error(w, "invalid period id", http.StatusBadRequest)
+		return
+	}
+
+	cmd := command.RemoveNoReadingPeriod{
+		GroupID:  groupID,
+		PeriodID: periodID,
+	}
+
+	if err := s.App.Commands.RemoveNoReadingPeriod.Handle(r.Context(), cmd); err != nil {
+		writeCommandError(w, err, http.StatusBadRequest)
+		return
+	}
+
 	if r.Header.Get("HX-Request") == "true" {
 		w.WriteHeader(http.StatusOK)
 		return
@@ -309,10 +443,10 @@ func (s *Server) handleCalendarGeneration(w http.ResponseWriter, r *http.Request
 		return
 	}
 	year := atoi(r.FormValue("year"))
-	currentYear := time.Now().Year()
 
-	if year != 0 && (year < 2000) {
-		http.Error(w, "year must be bigest 2000", http.StatusBadRequest)
+	if year != 0 && (year < domain.MinSupportedYear || year > domain.MaxSupportedYear) {
+		http.Error(w, fmt.Sprintf("year must be between %d and %d", domain.MinSupportedYear, domain.MaxSupportedYear),
+			http.StatusBadRequest)
 		return
 	}
 
@@ -323,16 +457,22 @@ func (s *Server) handleCalendarGeneration(w http.ResponseWriter, r *http.Request
 	}
 
 	var buffer *bytes.Buffer
+	var changes []command.CalendarChange
 	action := "generation"
 	if isRegenerate {
 		action = "regeneration"
 		cmd := command.RegenerateCalendarForGroup{
 			GroupID: groupID,
 			Year:    year,
+			Cascade: r.FormValue("cascade") == "on" || r.FormValue("cascade") == "true",
 		}
-		slog.Info("starting calendar "+action, "group_id", groupID, "year", year)
+		slog.Info("starting calendar "+action, "group_id", groupID, "year", year, "cascade", cmd.Cascade)
 		startTime := time.Now()
-		buffer, err = s.App.Commands.RegenerateCalendarForGroup.Handle(r.Context(), cmd)
+		var result *command.RegenerateCalendarResult
+		result, err = s.App.Commands.RegenerateCalendarForGroup.Handle(r.Context(), cmd)
+		if err == nil {
+			buffer, changes = result.Buffer, result.Changes
+		}
 		duration := time.Since(startTime)
 		slog.Info("calendar "+action+" completed", "duration", duration)
 	} else {
@@ -349,14 +489,30 @@ func (s *Server) handleCalendarGeneration(w http.ResponseWriter, r *http.Request
 
 	if err != nil {
 		slog.Error("failed to "+action[0:len(action)-2]+" calendar", "error", err)
+		if domain.IsVersionConflict(err) {
+			http.Error(w, conflictMessage, http.StatusConflict)
+			return
+		}
 		http.Error(w, "failed to "+action[0:len(action)-2]+" calendar", http.StatusInternalServerError)
 		return
 	}
+	yearMode := domain.CalendarYearMode(group.YearMode)
 	if year == 0 {
-		year = currentYear
+		year = yearMode.YearOf(time.Now())
+	}
+
+	if isRegenerate && r.Header.Get("Accept") == "application/json" {
+		render.JSON(w, r, rest.JSON{"changes": changes})
+		return
+	}
+	if len(changes) > 0 {
+		if report, errReport := json.Marshal(changes); errReport == nil {
+			w.Header().Set("X-Regeneration-Report", string(report))
+		}
 	}
 
-	filename := fmt.Sprintf("calendar_%s_%d.xlsx", sanitizeFilename(group.Name), year)
+	yearLabel := strings.ReplaceAll(yearMode.Label(year), "/", "-")
+	filename := fmt.Sprintf("calendar_%s_%s.xlsx", sanitizeFilename(group.Name), yearLabel)
 	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename)) //nolint:gocritic
 	w.WriteHeader(http.StatusOK)
@@ -369,6 +525,55 @@ func (s *Server) generateCalendarForGroup(w http.ResponseWriter, r *http.Request
 	s.handleCalendarGeneration(w, r, false)
 }
 
+func (s *Server) generateCalendarRangeForGroup(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	groupID, err := uuid.FromString(idStr)
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	if errParse := r.ParseForm(); errParse != nil {
+		http.Error(w, "failed to parse form data", http.StatusBadRequest)
+		return
+	}
+	fromYear := atoi(r.FormValue("from_year"))
+	toYear := atoi(r.FormValue("to_year"))
+
+	group, err := s.App.Queries.GetReaderGroup.Handle(r.Context(), query.GetReaderGroup{ID: groupID})
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+
+	slog.Info("starting calendar range generation", "group_id", groupID, "from_year", fromYear, "to_year", toYear)
+	startTime := time.Now()
+	buffer, err := s.App.Commands.GenerateCalendarRange.Handle(r.Context(), command.GenerateCalendarRangeForGroup{
+		GroupID:  groupID,
+		FromYear: fromYear,
+		ToYear:   toYear,
+	})
+	if err != nil {
+		slog.Error("failed to generate calendar range", "error", err)
+		writeCommandError(w, err, http.StatusBadRequest)
+		return
+	}
+	slog.Info("calendar range generation completed", "duration", time.Since(startTime))
+
+	yearMode := domain.CalendarYearMode(group.YearMode)
+	filename := fmt.Sprintf("calendar_%s_%s_%s.xlsx",
+		sanitizeFilename(group.Name),
+		strings.ReplaceAll(yearMode.Label(fromYear), "/", "-"),
+		strings.ReplaceAll(yearMode.Label(toYear), "/", "-"),
+	)
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename)) //nolint:gocritic
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
 func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	groupID, err := uuid.FromString(idStr)
@@ -384,9 +589,16 @@ func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
 
 	name := r.FormValue("name")
 	startOffsetStr := r.FormValue("start_offset")
+	sizeStr := r.FormValue("size")
+	yearModeStr := r.FormValue("year_mode")
+	lentRuleStr := r.FormValue("lent_rule")
+	versionStr := r.FormValue("version")
 
 	var namePtr *string
 	var startOffsetPtr *int
+	var sizePtr *int
+	var yearModePtr *domain.CalendarYearMode
+	var lentRulePtr *domain.LentReadingRule
 
 	if name != "" {
 		namePtr = &name
@@ -397,14 +609,43 @@ func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
 		startOffsetPtr = &startOffset
 	}
 
+	if sizeStr != "" {
+		size := atoi(sizeStr)
+		sizePtr = &size
+	}
+
+	if yearModeStr != "" {
+		yearMode, errMode := domain.ParseCalendarYearMode(yearModeStr)
+		if errMode != nil {
+			http.Error(w, errMode.Error(), http.StatusBadRequest)
+			return
+		}
+		yearModePtr = &yearMode
+	}
+
+	if lentRuleStr != "" {
+		lentRule, errRule := domain.ParseLentReadingRule(lentRuleStr)
+		if errRule != nil {
+			http.Error(w, errRule.Error(), http.StatusBadRequest)
+			return
+		}
+		lentRulePtr = &lentRule
+	}
+
 	cmd := command.UpdateReaderGroup{
 		GroupID:     groupID,
 		Name:        namePtr,
 		StartOffset: startOffsetPtr,
+		Size:        sizePtr,
+		YearMode:    yearModePtr,
+		LentRule:    lentRulePtr,
+	}
+	if versionStr != "" {
+		cmd.Version = atoi(versionStr)
 	}
 
 	if err := s.App.Commands.UpdateReaderGroup.Handle(r.Context(), cmd); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeCommandError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -426,10 +667,12 @@ func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
 
 	cmd := command.DeleteReaderGroup{
 		GroupID: groupID,
+		// htmx sends the values of a DELETE in the body, which ParseForm does not read, so it comes in the URL
+		Version: atoi(r.URL.Query().Get("version")),
 	}
 
 	if err := s.App.Commands.DeleteReaderGroup.Handle(r.Context(), cmd); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeCommandError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -441,10 +684,123 @@ func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/groups", http.StatusSeeOther)
 }
 
+// exportGroup downloads the group with its readers, no reading periods and calendars as a JSON document
+func (s *Server) exportGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.FromString(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	exported, err := s.App.Queries.ExportReaderGroup.Handle(r.Context(), query.ExportReaderGroup{ID: groupID})
+	if err != nil {
+		var notFound domain.ReaderGroupNotFoundError
+		if errors.As(err, &notFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("group_%s.json", sanitizeFilename(exported.Name))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename)) //nolint:gocritic
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(exported.Document); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// importGroup takes an exported document either as the request body, with the mode in the query,
+// or as the file "document" of a form with the field "mode"
+func (s *Server) importGroup(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxGroupDocumentSize)
+
+	isForm := strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+	var document []byte
+	var err error
+	if isForm {
+		file, _, errFile := r.FormFile("document")
+		if errFile != nil {
+			http.Error(w, "missing document file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		document, err = io.ReadAll(file)
+	} else {
+		document, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		http.Error(w, "failed to read document", http.StatusBadRequest)
+		return
+	}
+
+	mode, err := command.ParseImportMode(r.FormValue("mode"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.App.Commands.ImportReaderGroup.Handle(r.Context(), command.ImportReaderGroup{
+		Document: document,
+		Mode:     mode,
+	})
+	if err != nil {
+		var exists command.GroupExistsError
+		var taken domain.TelegramIDTakenError
+		if errors.As(err, &exists) || errors.As(err, &taken) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeCommandError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if isForm {
+		http.Redirect(w, r, fmt.Sprintf("/groups/%s", result.GroupID), http.StatusSeeOther)
+		return
+	}
+
+	if result.Created {
+		render.Status(r, http.StatusCreated)
+	}
+	render.JSON(w, r, result)
+}
+
 func (s *Server) regenerateCalendarForGroup(w http.ResponseWriter, r *http.Request) {
 	s.handleCalendarGeneration(w, r, true)
 }
 
+// downloadBackup streams a backup archive of a snapshot of the database taken while the application runs
+func (s *Server) downloadBackup(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := s.App.Queries.DownloadBackup.Handle(r.Context(), query.DownloadBackup{})
+	if err != nil {
+		slog.Error("failed to snapshot database for backup", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if errClose := snapshot.Archive.Close(); errClose != nil {
+			slog.Warn("failed to remove backup snapshot", "error", errClose)
+		}
+	}()
+
+	// the archive of a large database takes longer than the pages the server write timeout is set for
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(backupWriteTimeout)); err != nil {
+		slog.Warn("failed to extend write deadline of backup download", "error", err)
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", snapshot.Name)) //nolint:gocritic
+	w.WriteHeader(http.StatusOK)
+	written, err := io.Copy(w, snapshot.Archive)
+	if err != nil {
+		slog.Error("failed to write backup", "name", snapshot.Name, "error", err)
+		return
+	}
+	slog.Info("backup downloaded", "name", snapshot.Name, "size", written, "remote", r.RemoteAddr)
+}
+
 func sanitizeFilename(name string) string {
 	// Заменяем пробелы на подчёркивания
 	result := strings.ReplaceAll(name, " ", "_")
@@ -530,8 +886,8 @@ func (s *Server) getCurrentKathisma(w http.ResponseWriter, r *http.Request) {
 	}
 
 	readerNumber := atoi(readerNumberStr)
-	if readerNumber < 1 || readerNumber > 20 {
-		http.Error(w, "reader number must be between 1 and 20", http.StatusBadRequest)
+	if readerNumber < 1 || readerNumber > domain.KathismasCount {
+		http.Error(w, fmt.Sprintf("reader number must be between 1 and %d", domain.KathismasCount), http.StatusBadRequest)
 		return
 	}
 
@@ -553,6 +909,165 @@ func (s *Server) getCurrentKathisma(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, result)
 }
 
+func (s *Server) verifyCalendar(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	groupID, err := uuid.FromString(idStr)
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	year := atoi(chi.URLParam(r, "year"))
+	if year < domain.MinSupportedYear || year > domain.MaxSupportedYear {
+		http.Error(w, "invalid year", http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.App.Queries.VerifyCalendar.Handle(r.Context(), query.VerifyCalendar{
+		GroupID: groupID,
+		Year:    year,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	render.JSON(w, r, result)
+}
+
+// getPaschalion shows the movable feasts of the year, the current one when no year is given
+func (s *Server) getPaschalion(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if yearStr := chi.URLParam(r, "year"); yearStr != "" {
+		year = atoi(yearStr)
+	}
+
+	result, err := s.App.Queries.GetPaschalion.Handle(r.Context(), query.GetPaschalion{Year: year})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get("Accept") == "application/json" {
+		render.JSON(w, r, result)
+		return
+	}
+
+	data := struct {
+		Title           string
+		ContentTemplate string
+		*query.PaschalionDTO
+	}{
+		Title:           fmt.Sprintf("Пасхалия %d", result.Year),
+		ContentTemplate: "paschalion-content",
+		PaschalionDTO:   result,
+	}
+
+	if err := s.templates.ExecuteTemplate(w, "layout.gohtml", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) trashPage(w http.ResponseWriter, r *http.Request) {
+	items, err := s.App.Queries.ListTrash.Handle(r.Context(), query.ListTrash{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("Accept") == "application/json" {
+		render.JSON(w, r, items)
+		return
+	}
+
+	data := struct {
+		Title           string
+		ContentTemplate string
+		RetentionDays   int
+		Items           []query.TrashItemDTO
+	}{
+		Title:           "Корзина",
+		ContentTemplate: "trash-content",
+		RetentionDays:   int(s.Conf.Trash.Retention.Hours() / 24),
+		Items:           items,
+	}
+
+	if err := s.templates.ExecuteTemplate(w, "layout.gohtml", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) restoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	itemID, err := uuid.FromString(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid trash item id", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.App.Commands.RestoreFromTrash.Handle(r.Context(), command.RestoreFromTrash{ItemID: itemID}); err != nil {
+		var notFound domain.TrashItemNotFoundError
+		if errors.As(err, &notFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeCommandError(w, err, http.StatusConflict)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/trash", http.StatusSeeOther)
+}
+
+// listAuditEntries returns the audit log filtered by the query parameters group_id, actor, source, action,
+// since and until (RFC 3339 or YYYY-MM-DD, until is exclusive) and limit
+func (s *Server) listAuditEntries(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor:  params.Get("actor"),
+		Source: domain.AuditSource(params.Get("source")),
+		Action: domain.AuditAction(params.Get("action")),
+		Limit:  defaultAuditLimit,
+	}
+
+	if groupID := params.Get("group_id"); groupID != "" {
+		id, err := uuid.FromString(groupID)
+		if err != nil {
+			http.Error(w, "invalid group id", http.StatusBadRequest)
+			return
+		}
+		filter.GroupID = id
+	}
+
+	var err error
+	if filter.Since, err = parseAuditTime(params.Get("since")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.Until, err = parseAuditTime(params.Get("until")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		filter.Limit = atoi(limit)
+		if filter.Limit < 1 || filter.Limit > maxAuditLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxAuditLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := s.App.Queries.ListAuditEntries.Handle(r.Context(), query.ListAuditEntries{Filter: filter})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render.JSON(w, r, entries)
+}
+
 func (s *Server) renderErrorPage(w http.ResponseWriter, r *http.Request, err error, errCode int) { // nolint
 	tmplData := struct {
 		Status int
@@ -571,6 +1086,112 @@ type CalendarEntry struct {
 	Year              int    `json:"year"`
 }
 
+// parseMonthDay accepts both "MM-DD" and a full "YYYY-MM-DD" date, whose year is ignored
+func parseMonthDay(value string) (domain.MonthDay, error) {
+	layout := "01-02"
+	if strings.Count(value, "-") == 2 {
+		layout = "2006-01-02"
+	}
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		return domain.MonthDay{}, fmt.Errorf("invalid date %q: %w", value, err)
+	}
+	return domain.MonthDay{Month: date.Month(), Day: date.Day()}, nil
+}
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+	// groupPageAuditEntries is how many of the latest changes the group page shows
+	groupPageAuditEntries = 20
+	// maxGroupDocumentSize bounds an imported group, twenty years of calendars take a few megabytes
+	maxGroupDocumentSize = 32 << 20
+	// backupWriteTimeout bounds the download of a backup
+	backupWriteTimeout = 30 * time.Minute
+)
+
+var auditActionLabels = map[domain.AuditAction]string{
+	domain.AuditActionCreateGroup:           "Группа создана",
+	domain.AuditActionUpdateGroup:           "Группа изменена",
+	domain.AuditActionDeleteGroup:           "Группа удалена",
+	domain.AuditActionAddReader:             "Добавлен чтец",
+	domain.AuditActionRemoveReader:          "Удален чтец",
+	domain.AuditActionAddNoReadingPeriod:    "Добавлен период без чтения",
+	domain.AuditActionRemoveNoReadingPeriod: "Удален период без чтения",
+	domain.AuditActionGenerateCalendar:      "Сгенерирован календарь",
+	domain.AuditActionGenerateCalendarRange: "Сгенерированы календари на несколько лет",
+	domain.AuditActionRegenerateCalendar:    "Календарь перегенерирован",
+	domain.AuditActionCreatePsalmReader:     "Создан чтец",
+	domain.AuditActionRestoreGroup:          "Группа восстановлена из корзины",
+	domain.AuditActionRestoreReader:         "Чтец восстановлен из корзины",
+	domain.AuditActionPurgeTrash:            "Окончательно удалено из корзины",
+	domain.AuditActionImportGroup:           "Группа импортирована",
+	domain.AuditActionRestoreBackupGroup:    "Группа восстановлена из резервной копии",
+}
+
+// parseAuditTime accepts RFC 3339 or a date, an empty value is no bound
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// webActor runs the commands of the request on behalf of the basic auth user,
+// or of the client address when there is none
+func webActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, _, ok := r.BasicAuth()
+		if !ok {
+			ip, err := realip.Get(r)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			name = ip
+		}
+		ctx := command.WithActor(r.Context(), command.Actor{Name: name, Source: domain.AuditSourceWeb})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// backupToken lets the requests with the bearer token of the backup configuration through,
+// without a configured token there is no backup download
+func (s *Server) backupToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := s.Conf.Backup.Token
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="backup"`)
+			http.Error(w, "invalid backup token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// conflictMessage is shown when the group was changed by somebody else in the meantime
+const conflictMessage = "Группа была изменена другим пользователем. Обновите страницу и повторите изменение."
+
+// writeCommandError answers 409 when the group was changed concurrently and the given status otherwise
+func writeCommandError(w http.ResponseWriter, err error, status int) {
+	if domain.IsVersionConflict(err) {
+		http.Error(w, conflictMessage, http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
 func atoi(s string) int {
 	i, err := strconv.Atoi(s)
 	if err != nil {
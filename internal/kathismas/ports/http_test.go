@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/memory"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_DeleteGroupStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	groupRepo := memory.NewReaderGroupRepository()
+	trash := memory.NewTrashRepository()
+	s := &Server{App: &app.Application{Commands: app.Commands{
+		DeleteReaderGroup: command.NewDeleteReaderGroupHandler(
+			groupRepo, trash, memory.NewAuditLogRepository(), time.Hour,
+		),
+	}}}
+
+	group, err := domain.NewReaderGroup("Test Group", 1)
+	require.NoError(t, err)
+	require.NoError(t, groupRepo.Create(ctx, group))
+	seenVersion := group.Version
+	require.NoError(t, group.UpdateName("Renamed Group"))
+	require.NoError(t, groupRepo.Update(ctx, group))
+
+	deleteGroup := func(version int) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodDelete,
+			"/groups/"+group.ID.String()+"?version="+strconv.Itoa(version), http.NoBody)
+		r.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		s.router().ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusConflict, deleteGroup(seenVersion).Code)
+	_, err = groupRepo.GetByID(ctx, group.ID)
+	require.NoError(t, err, "a group changed since it was shown stays")
+
+	assert.Equal(t, http.StatusOK, deleteGroup(group.Version).Code)
+	items, err := trash.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
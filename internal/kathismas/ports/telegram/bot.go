@@ -8,6 +8,7 @@ import (
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/query"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -83,14 +84,29 @@ func (b *Bot) Start(ctx context.Context) error {
 func (b *Bot) worker(ctx context.Context, jobs <-chan tgbotapi.Update) {
 	defer b.wg.Done()
 	for update := range jobs {
+		updateCtx := command.WithActor(ctx, telegramActor(update.SentFrom()))
 		if update.Message != nil {
-			b.handleUpdate(ctx, update)
+			b.handleUpdate(updateCtx, update)
 		} else if update.CallbackQuery != nil {
-			b.handleCallbackQuery(ctx, update)
+			b.handleCallbackQuery(updateCtx, update)
 		}
 	}
 }
 
+// telegramActor names the sender by the username when there is one, otherwise by the Telegram ID
+func telegramActor(user *tgbotapi.User) command.Actor {
+	actor := command.Actor{Source: domain.AuditSourceTelegram}
+	switch {
+	case user == nil:
+		actor.Name = "unknown"
+	case user.UserName != "":
+		actor.Name = "@" + user.UserName
+	default:
+		actor.Name = fmt.Sprintf("%d", user.ID)
+	}
+	return actor
+}
+
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 	if update.Message.IsCommand() {
 		if err := b.handlers.HandleCommand(ctx, b.api, update.Message); err != nil {
@@ -2,12 +2,15 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/query"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/gofrs/uuid/v5"
 )
@@ -369,7 +372,7 @@ func (h *Handlers) handleGroupCallback(ctx context.Context, bot MessageSender, c
 			h.log.Error("failed to answer callback", "error", sendErr)
 		}
 
-		errorMsg := "Группа полностью заполнена (20 чтецов). Обратитесь к администратору."
+		errorMsg := fmt.Sprintf("Группа полностью заполнена (%d чтецов). Обратитесь к администратору.", group.Size)
 		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, errorMsg)
 		h.sessionManager.DeleteSession(callback.From.ID)
 		_, sendErr = bot.Send(msg)
@@ -426,7 +429,7 @@ func (h *Handlers) handleReaderNumberCallback(bot MessageSender, callback *tgbot
 
 	var readerNumber int8
 	_, err := fmt.Sscanf(parts[1], "%d", &readerNumber)
-	if err != nil || readerNumber < 1 || readerNumber > 20 {
+	if err != nil || readerNumber < 1 || readerNumber > domain.KathismasCount {
 		answerCallback := tgbotapi.NewCallback(callback.ID, "Неверный номер чтеца")
 		_, sendErr := bot.Request(answerCallback)
 		if sendErr != nil {
@@ -524,6 +527,13 @@ func (h *Handlers) handleConfirmCallback(ctx context.Context, bot MessageSender,
 		}
 
 		errorMsg := fmt.Sprintf("Ошибка при регистрации: %v\n\nПопробуйте снова через /register", err)
+		var taken domain.TelegramIDTakenError
+		switch {
+		case domain.IsVersionConflict(err):
+			errorMsg = "Группа была изменена одновременно с вашей регистрацией.\n\nПопробуйте снова через /register"
+		case errors.As(err, &taken):
+			errorMsg = "Вы уже зарегистрированы в другой группе. Используйте /kathisma для просмотра текущей кафизмы."
+		}
 		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, errorMsg)
 		h.sessionManager.DeleteSession(callback.From.ID)
 		_, sendErr = bot.Send(msg)
@@ -573,6 +583,14 @@ func (h *Handlers) handleGetKathismaForRegistered(
 	var responseText string
 	if result.Kathisma == 0 {
 		responseText = fmt.Sprintf("📖 На сегодня (%s) чтение не предусмотрено.\n\n", result.Date)
+		if result.NoReadingPeriod != "" {
+			responseText += result.NoReadingPeriod
+		}
+	} else if len(result.Kathismas) > 1 {
+		responseText = fmt.Sprintf(
+			"📖 Ваши кафизмы на сегодня (%s):\n\n Кафизмы №%s\n\nЧтец №%d в группе \"%q\"",
+			result.Date, formatKathismas(result.Kathismas), result.ReaderNumber, result.GroupName,
+		)
 	} else {
 		responseText = fmt.Sprintf(
 			"📖 Ваша кафизма на сегодня (%s):\n\n Кафизма №%d\n\nЧтец №%d в группе \"%q\"",
@@ -580,6 +598,8 @@ func (h *Handlers) handleGetKathismaForRegistered(
 		)
 	}
 
+	responseText += formatKathismaContent(result.Content)
+
 	msg := tgbotapi.NewMessage(message.Chat.ID, responseText)
 	_, err = bot.Send(msg)
 	if err != nil {
@@ -587,3 +607,22 @@ func (h *Handlers) handleGetKathismaForRegistered(
 	}
 	return nil
 }
+
+func formatKathismas(kathismas []int) string {
+	parts := make([]string, 0, len(kathismas))
+	for _, k := range kathismas {
+		parts = append(parts, strconv.Itoa(k))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatKathismaContent(content []query.KathismaContentDTO) string {
+	var sb strings.Builder
+	for _, kathisma := range content {
+		fmt.Fprintf(&sb, "\n\nКафизма %d: %s", kathisma.Kathisma, kathisma.Psalms.Title)
+		for i, stasis := range kathisma.Stases {
+			fmt.Fprintf(&sb, "\n  Слава %d: %s", i+1, stasis.Title)
+		}
+	}
+	return sb.String()
+}
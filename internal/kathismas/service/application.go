@@ -2,55 +2,204 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 
 	"github.com/DjaPy/fot-twenty-readers-go/internal/common/metrics"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/backup"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/excel"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/groupdoc"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/adapters/sqlite"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
 	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/query"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/config"
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/domain"
 	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/codec/json"
+	bolt "go.etcd.io/bbolt"
 )
 
-func NewApplication(ctx context.Context, logger *slog.Logger) *app.Application {
-	db, err := storm.Open("for-twenty-readers.db", storm.Codec(json.Codec))
-	if err != nil {
-		slog.Error("could not open database", "error", err)
-		os.Exit(1)
-	}
-
-	cleanup := func() {
-		if errClose := db.Close(); errClose != nil {
-			slog.Error("could not close database", "error", errClose)
-		}
+func NewApplication(ctx context.Context, logger *slog.Logger, cfg config.Config) *app.Application {
+	var repos repositories
+	switch cfg.Storage.Backend {
+	case config.StorageSQLite:
+		repos = openSQLite(cfg.Storage.SQLitePath)
+	default:
+		repos = openBolt(cfg)
 	}
+	readerGroupRepository, auditLogRepository, trashRepository := repos.groups, repos.auditLog, repos.trash
 
 	metricsClient := metrics.NoOp{}
 
-	psalmReaderTGRepository := adapters.NewPsalmReaderTGRepository(db)
-	readerGroupRepository := adapters.NewReaderGroupRepository(db)
 	calendarGenerator := excel.NewCalendarGenerator()
+	groupDocument := groupdoc.NewDocument()
 
 	return app.NewApplication(
 		app.Commands{
-			CreateCalendarOfReader:     command.NewCreatePsalmReaderTGHandler(psalmReaderTGRepository, logger, metricsClient),
-			CreateReaderGroup:          command.NewCreateReaderGroupHandler(readerGroupRepository),
-			AddReaderToGroup:           command.NewAddReaderToGroupHandler(readerGroupRepository),
-			GenerateCalendarForGroup:   command.NewGenerateCalendarForGroupHandler(readerGroupRepository, calendarGenerator),
-			RemoveReaderFromGroup:      command.NewRemoveReaderFromGroupHandler(readerGroupRepository),
-			DeleteReaderGroup:          command.NewDeleteReaderGroupHandler(readerGroupRepository),
-			UpdateReaderGroup:          command.NewUpdateReaderGroupHandler(readerGroupRepository),
-			RegenerateCalendarForGroup: command.NewRegenerateCalendarForGroupHandler(readerGroupRepository, calendarGenerator),
+			CreateCalendarOfReader: command.NewCreatePsalmReaderTGHandler(
+				repos.psalmReaders, auditLogRepository, logger, metricsClient,
+			),
+			CreateReaderGroup: command.NewCreateReaderGroupHandler(readerGroupRepository, auditLogRepository),
+			AddReaderToGroup:  command.NewAddReaderToGroupHandler(readerGroupRepository, auditLogRepository),
+			GenerateCalendarForGroup: command.NewGenerateCalendarForGroupHandler(
+				readerGroupRepository, calendarGenerator, auditLogRepository,
+			),
+			RemoveReaderFromGroup: command.NewRemoveReaderFromGroupHandler(
+				readerGroupRepository, trashRepository, auditLogRepository, cfg.Trash.Retention,
+			),
+			DeleteReaderGroup: command.NewDeleteReaderGroupHandler(
+				readerGroupRepository, trashRepository, auditLogRepository, cfg.Trash.Retention,
+			),
+			UpdateReaderGroup: command.NewUpdateReaderGroupHandler(readerGroupRepository, auditLogRepository),
+			RegenerateCalendarForGroup: command.NewRegenerateCalendarForGroupHandler(
+				readerGroupRepository, calendarGenerator, auditLogRepository,
+			),
+			AddNoReadingPeriod:    command.NewAddNoReadingPeriodHandler(readerGroupRepository, auditLogRepository),
+			RemoveNoReadingPeriod: command.NewRemoveNoReadingPeriodHandler(readerGroupRepository, auditLogRepository),
+			GenerateCalendarRange: command.NewGenerateCalendarRangeForGroupHandler(
+				readerGroupRepository, calendarGenerator, auditLogRepository,
+			),
+			RestoreFromTrash: command.NewRestoreFromTrashHandler(readerGroupRepository, trashRepository, auditLogRepository),
+			PurgeTrash:       command.NewPurgeTrashHandler(trashRepository, auditLogRepository),
+			ImportReaderGroup: command.NewImportReaderGroupHandler(
+				readerGroupRepository, groupDocument, auditLogRepository,
+			),
+			RestoreGroupFromBackup: command.NewRestoreGroupFromBackupHandler(
+				readerGroupRepository, trashRepository, auditLogRepository, cfg.Trash.Retention,
+			),
+			CreateBackup: command.NewCreateBackupHandler(repos.backups),
 		},
 		app.Queries{
 			ListReaderGroups:      query.NewListReaderGroupsHandler(readerGroupRepository),
 			GetReaderGroup:        query.NewGetReaderGroupHandler(readerGroupRepository),
 			GetCurrentKathisma:    query.NewGetCurrentKathismaHandler(readerGroupRepository),
 			GetReaderByTelegramID: query.NewGetReaderByTelegramIDHandler(readerGroupRepository),
+			VerifyCalendar:        query.NewVerifyCalendarHandler(readerGroupRepository),
+			GetPaschalion:         query.NewGetPaschalionHandler(),
+			ListAuditEntries:      query.NewListAuditEntriesHandler(auditLogRepository),
+			ListTrash:             query.NewListTrashHandler(trashRepository),
+			ExportReaderGroup:     query.NewExportReaderGroupHandler(readerGroupRepository, groupDocument),
+			DownloadBackup:        query.NewDownloadBackupHandler(repos.backups),
 		},
-		cleanup,
+		repos.cleanup,
 	)
 }
+
+// repositories are the storage of one backend, cleanup closes its database
+type repositories struct {
+	psalmReaders domain.RepositoryPsalmReader
+	groups       domain.RepositoryReaderGroup
+	auditLog     domain.RepositoryAuditLog
+	trash        domain.RepositoryTrash
+	backups      backups
+	cleanup      func()
+}
+
+// backups takes the backups of the database while the application holds it
+type backups interface {
+	command.BackupCreator
+	query.BackupSnapshotter
+}
+
+func openBolt(cfg config.Config) repositories {
+	db, err := storm.Open(cfg.Storage.BoltPath, storm.Codec(json.Codec))
+	if err != nil {
+		slog.Error("could not open database", "error", err)
+		os.Exit(1)
+	}
+
+	results, err := adapters.Migrate(db, false)
+	if err != nil {
+		slog.Error("could not migrate database", "error", err)
+		os.Exit(1)
+	}
+	for _, result := range results {
+		slog.Info("applied database migration", "version", result.Version, "name", result.Name, "changed", result.Changed)
+	}
+
+	cleanup := func() {
+		if errClose := db.Close(); errClose != nil {
+			slog.Error("could not close database", "error", errClose)
+		}
+	}
+	return repositories{
+		psalmReaders: adapters.NewPsalmReaderTGRepository(db),
+		groups:       adapters.NewReaderGroupRepository(db),
+		auditLog:     adapters.NewAuditLogRepository(db),
+		trash:        adapters.NewTrashRepository(db),
+		backups:      newBackups(db.Bolt, cfg),
+		cleanup:      cleanup,
+	}
+}
+
+func openSQLite(path string) repositories {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		slog.Error("could not open database", "error", err)
+		os.Exit(1)
+	}
+
+	cleanup := func() {
+		if errClose := db.Close(); errClose != nil {
+			slog.Error("could not close database", "error", errClose)
+		}
+	}
+	return repositories{
+		psalmReaders: sqlite.NewPsalmReaderRepository(db),
+		groups:       sqlite.NewReaderGroupRepository(db),
+		auditLog:     sqlite.NewAuditLogRepository(db),
+		trash:        sqlite.NewTrashRepository(db),
+		backups:      noBackups{},
+		cleanup:      cleanup,
+	}
+}
+
+func newBackups(db *bolt.DB, cfg config.Config) *backup.Backups {
+	secret, err := backup.NewSecret(cfg.Backup.Passphrase, cfg.Backup.KeyFile)
+	if err != nil {
+		slog.Error("invalid backup encryption", "error", err)
+		os.Exit(1)
+	}
+	policy := backup.RetentionPolicy{
+		Daily:   cfg.Backup.KeepDaily,
+		Weekly:  cfg.Backup.KeepWeekly,
+		Monthly: cfg.Backup.KeepMonthly,
+		Yearly:  cfg.Backup.KeepYearly,
+	}
+	if err := policy.Validate(); err != nil {
+		slog.Error("invalid backup retention policy", "error", err)
+		os.Exit(1)
+	}
+	return backup.NewBackups(db, cfg.Backup.Dir, cfg.System.Revision, secret, policy)
+}
+
+// noBackups stands for the backups of the sqlite backend, which are taken of its file
+type noBackups struct{}
+
+func (noBackups) CreateBackup() (string, error) {
+	return "", errNoBackups
+}
+
+func (noBackups) OpenSnapshot() (string, io.ReadCloser, error) {
+	return "", nil, errNoBackups
+}
+
+var errNoBackups = fmt.Errorf("backups are only taken of the %s backend", config.StorageBolt)
+
+// MigrateDryRun reports the migrations the bolt database is waiting for without applying them
+func MigrateDryRun(cfg config.Config) ([]adapters.MigrationResult, error) {
+	if cfg.Storage.Backend != config.StorageBolt {
+		return nil, fmt.Errorf("migrations are only kept for the %s backend", config.StorageBolt)
+	}
+	db, err := storm.Open(cfg.Storage.BoltPath, storm.Codec(json.Codec))
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %w", err)
+	}
+	defer db.Close()
+
+	return adapters.Migrate(db, true)
+}
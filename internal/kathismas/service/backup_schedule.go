@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
+)
+
+// BackupPeriodically takes a backup at once and then every interval until ctx is done
+func BackupPeriodically(ctx context.Context, create command.CreateBackupHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := create.Handle(ctx, command.CreateBackup{}); err != nil {
+			slog.Error("failed to create backup", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/DjaPy/fot-twenty-readers-go/internal/kathismas/app/command"
+)
+
+// PurgeTrashPeriodically purges the expired trash items at once and then every interval until ctx is done
+func PurgeTrashPeriodically(ctx context.Context, purge command.PurgeTrashHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purge.Handle(ctx, command.PurgeTrash{})
+		if err != nil {
+			slog.Error("failed to purge trash", "error", err)
+		} else if purged > 0 {
+			slog.Info("purged expired trash items", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}